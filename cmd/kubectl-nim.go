@@ -1,12 +1,14 @@
 package main
 
 import (
+	"errors"
 	"os"
 
 	flag "github.com/spf13/pflag"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 
     "k8s-nim-operator-cli/pkg/cmd"
+    "k8s-nim-operator-cli/pkg/util"
 )
 
 func main() {
@@ -16,6 +18,10 @@ func main() {
 
 	root := cmd.NewNIMCommand(ioStreams)
 	if err := root.Execute(); err != nil {
+		var exitErr *util.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
 		os.Exit(1)
 	}
 }
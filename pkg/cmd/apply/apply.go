@@ -0,0 +1,137 @@
+package apply
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/utils/ptr"
+
+	"k8s-nim-operator-cli/pkg/util"
+	"k8s-nim-operator-cli/pkg/util/client"
+	"k8s-nim-operator-cli/pkg/util/validation"
+)
+
+// Field manager of the server-side apply, the same one nim diff previews with.
+const fieldManager = "kubectl-nim"
+
+type ApplyOptions struct {
+	IoStreams *genericclioptions.IOStreams
+	Namespace string
+	Filenames []string
+}
+
+func NewApplyCommand(cmdFactory cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	options := &ApplyOptions{IoStreams: &streams}
+
+	cmd := &cobra.Command{
+		Use:   "apply -f FILENAME",
+		Short: "Validate NIMService and NIMCache manifests and apply them to the cluster",
+		Long: `Validate NIMService and NIMCache manifests with the same rules as nim validate, and apply them with a server-side
+apply if every object is valid. Nothing is applied when any object is invalid, and every problem is reported with its
+field path.
+
+Files may hold several YAML documents. A directory contributes its .yaml, .yml and .json files, and "-" reads from
+standard input. Objects without a namespace use --namespace. Objects of other kinds are skipped.`,
+		Example: `  nim apply -f llama3-nimservice.yaml
+  nim apply -f deploy/ -n nim`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				return fmt.Errorf("unknown argument(s) %q; pass manifests with -f", strings.Join(args, " "))
+			}
+			if len(options.Filenames) == 0 {
+				cmd.HelpFunc()(cmd, args)
+				return nil
+			}
+			namespace, err := cmd.Flags().GetString("namespace")
+			if err != nil {
+				return fmt.Errorf("failed to get namespace: %w", err)
+			}
+			options.Namespace = namespace
+			if options.Namespace == "" {
+				options.Namespace = "default"
+			}
+			k8sClient, err := client.NewClient(cmdFactory)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			return Run(cmd.Context(), options, k8sClient)
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&options.Filenames, "filename", "f", nil, "File or directory of manifests to apply, or - for standard input. Can be repeated.")
+
+	return cmd
+}
+
+// Run validates every object before applying any, so that an invalid manifest leaves the cluster untouched.
+func Run(ctx context.Context, options *ApplyOptions, k8sClient client.Client) error {
+	objects, err := util.ReadObjects(options.Filenames, options.IoStreams.In)
+	if err != nil {
+		return err
+	}
+
+	var valid []util.FileObject
+	invalid := 0
+	for _, object := range objects {
+		obj := object.Object
+		errs, supported, err := validation.ValidateObject(obj)
+		if !supported {
+			fmt.Fprintf(options.IoStreams.ErrOut, "%s: %s/%s: skipped, only NIMService and NIMCache are applied\n", object.Source, obj.GetKind(), obj.GetName())
+			continue
+		}
+		if err != nil {
+			err = fmt.Errorf("%s %q is invalid: %w", obj.GetKind(), obj.GetName(), err)
+		} else {
+			err = validation.Error(obj.GetKind(), obj.GetName(), errs)
+		}
+		if err != nil {
+			fmt.Fprintf(options.IoStreams.ErrOut, "%s: %v\n", object.Source, err)
+			invalid++
+			continue
+		}
+		valid = append(valid, object)
+	}
+	if invalid > 0 {
+		return fmt.Errorf("%d object(s) are invalid, nothing was applied", invalid)
+	}
+	if len(valid) == 0 {
+		return fmt.Errorf("no NIMService or NIMCache found in %s", strings.Join(options.Filenames, ", "))
+	}
+
+	for _, object := range valid {
+		obj := object.Object
+		if obj.GetNamespace() == "" {
+			obj.SetNamespace(options.Namespace)
+		}
+		if err := apply(ctx, k8sClient, obj); err != nil {
+			return fmt.Errorf("%s: failed to apply %s %s/%s: %w", object.Source, obj.GetKind(), obj.GetNamespace(), obj.GetName(), err)
+		}
+		fmt.Fprintf(options.IoStreams.Out, "%s %q applied in namespace %q\n", obj.GetKind(), obj.GetName(), obj.GetNamespace())
+	}
+	return nil
+}
+
+func apply(ctx context.Context, k8sClient client.Client, obj *unstructured.Unstructured) error {
+	data, err := json.Marshal(obj.Object)
+	if err != nil {
+		return err
+	}
+	namespace, name := obj.GetNamespace(), obj.GetName()
+	patchOptions := metav1.PatchOptions{FieldManager: fieldManager, Force: ptr.To(true)}
+	nimClient := k8sClient.NIMClient().AppsV1alpha1()
+	if obj.GetKind() == "NIMService" {
+		_, err = nimClient.NIMServices(namespace).Patch(ctx, name, types.ApplyPatchType, data, patchOptions)
+	} else {
+		_, err = nimClient.NIMCaches(namespace).Patch(ctx, name, types.ApplyPatchType, data, patchOptions)
+	}
+	return err
+}
@@ -0,0 +1,104 @@
+package apply
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	k8stesting "k8s.io/client-go/testing"
+
+	"k8s-nim-operator-cli/pkg/util/client/fake"
+)
+
+const manifests = `apiVersion: apps.nvidia.com/v1alpha1
+kind: NIMService
+metadata:
+  name: llama3
+spec:
+  image:
+    repository: nvcr.io/nim/meta/llama3-8b-instruct
+    tag: 1.0.3
+  authSecret: ngc-api-secret
+  storage:
+    nimCache:
+      name: llama3-cache
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+`
+
+const invalidNIMCache = `
+---
+apiVersion: apps.nvidia.com/v1alpha1
+kind: NIMCache
+metadata:
+  name: llama3-cache
+spec:
+  source:
+    ngc:
+      authSecret: ngc-api-secret
+  storage:
+    pvc:
+      create: true
+`
+
+// Records the server-side applies made through the NIM clientset.
+func newTestClient(applied *[]string) *fake.Client {
+	client := fake.NewFakeClient(nil, nil)
+	client.NIM.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patch := action.(k8stesting.PatchActionImpl)
+		if patch.GetPatchType() == types.ApplyPatchType {
+			*applied = append(*applied, patch.GetResource().Resource+" "+patch.GetNamespace()+"/"+patch.GetName())
+		}
+		return true, nil, nil
+	})
+	return client
+}
+
+func newTestOptions(in string) (*ApplyOptions, *bytes.Buffer, *bytes.Buffer) {
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	streams := genericclioptions.IOStreams{In: strings.NewReader(in), Out: out, ErrOut: errOut}
+	return &ApplyOptions{IoStreams: &streams, Namespace: "nim", Filenames: []string{"-"}}, out, errOut
+}
+
+func TestRunAppliesValidObjects(t *testing.T) {
+	var applied []string
+	options, out, errOut := newTestOptions(manifests)
+
+	if err := Run(context.Background(), options, newTestClient(&applied)); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(applied) != 1 || applied[0] != "nimservices nim/llama3" {
+		t.Fatalf("unexpected applies: %v", applied)
+	}
+	if out.String() != "NIMService \"llama3\" applied in namespace \"nim\"\n" {
+		t.Errorf("unexpected output %q", out.String())
+	}
+	if !strings.Contains(errOut.String(), "ConfigMap/settings: skipped") {
+		t.Errorf("expected the ConfigMap to be skipped, got %q", errOut.String())
+	}
+}
+
+func TestRunAppliesNothingWhenInvalid(t *testing.T) {
+	var applied []string
+	options, _, errOut := newTestOptions(manifests + invalidNIMCache)
+
+	err := Run(context.Background(), options, newTestClient(&applied))
+	if err == nil || err.Error() != "1 object(s) are invalid, nothing was applied" {
+		t.Fatalf("expected an invalid object error, got %v", err)
+	}
+	if len(applied) != 0 {
+		t.Fatalf("nothing should be applied, got %v", applied)
+	}
+	for _, want := range []string{`NIMCache "llama3-cache" is invalid`, "spec.storage.pvc.size: Required value", "spec.storage.pvc.storageClass: Required value"} {
+		if !strings.Contains(errOut.String(), want) {
+			t.Errorf("expected %q in errors:\n%s", want, errOut.String())
+		}
+	}
+}
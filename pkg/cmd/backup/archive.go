@@ -0,0 +1,229 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	archiveVersion = 1
+	indexFile      = "index.yaml"
+
+	// Encrypted entries get this suffix. Only secrets are encrypted.
+	encryptedSuffix = ".enc"
+
+	// Upper bound on a single entry, so a corrupt or hostile archive cannot exhaust memory.
+	maxEntrySize = 64 << 20
+
+	cipherAlgorithm = "aes-256-gcm"
+	kdfAlgorithm    = "pbkdf2-sha256"
+	kdfIterations   = 600000
+)
+
+// The directories of an archive, one per kind, in the order restore creates them: secrets and claims first, since
+// caches and services refer to them, then caches, which services mount, then services and pipelines.
+var kinds = []struct {
+	dir, kind string
+}{
+	{"secrets", "Secret"},
+	{"persistentvolumeclaims", "PersistentVolumeClaim"},
+	{"nimcaches", "NIMCache"},
+	{"nimservices", "NIMService"},
+	{"nimpipelines", "NIMPipeline"},
+}
+
+func kindDir(kind string) string {
+	for _, k := range kinds {
+		if k.kind == kind {
+			return k.dir
+		}
+	}
+	return ""
+}
+
+// Describes the archive. Stored as index.yaml, the first entry.
+type index struct {
+	Version    int         `json:"version"`
+	Namespace  string      `json:"namespace"`
+	Created    time.Time   `json:"created"`
+	Encryption *encryption `json:"encryption,omitempty"`
+}
+
+// How the secrets are encrypted: AES-256-GCM with a key derived from the passphrase. Each entry holds the nonce
+// followed by the ciphertext.
+type encryption struct {
+	Cipher     string `json:"cipher"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+}
+
+func newEncryption() (*encryption, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return &encryption{Cipher: cipherAlgorithm, KDF: kdfAlgorithm, Iterations: kdfIterations, Salt: salt}, nil
+}
+
+func (e *encryption) aead(passphrase string) (cipher.AEAD, error) {
+	if e.Cipher != cipherAlgorithm || e.KDF != kdfAlgorithm {
+		return nil, fmt.Errorf("unsupported encryption %s with %s", e.Cipher, e.KDF)
+	}
+	key, err := pbkdf2.Key(sha256.New, passphrase, e.Salt, e.Iterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func seal(aead cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(aead cipher.AEAD, data []byte) ([]byte, error) {
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("encrypted entry is truncated")
+	}
+	plaintext, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, errors.New("wrong passphrase or corrupted backup")
+	}
+	return plaintext, nil
+}
+
+// A backup: the index and the entries, keyed by path like "nimcaches/llama3-cache.yaml".
+type archive struct {
+	index   index
+	entries map[string][]byte
+}
+
+func entryPath(kind, name string, encrypted bool) string {
+	p := path.Join(kindDir(kind), name+".yaml")
+	if encrypted {
+		p += encryptedSuffix
+	}
+	return p
+}
+
+// Writes the archive as a gzipped tar, the index first and then the entries in path order.
+func (a *archive) write(w io.Writer) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	data, err := yaml.Marshal(a.index)
+	if err != nil {
+		return err
+	}
+	if err := writeEntry(tw, indexFile, data, a.index.Created); err != nil {
+		return err
+	}
+	paths := make([]string, 0, len(a.entries))
+	for p := range a.entries {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		if err := writeEntry(tw, p, a.entries[p], a.index.Created); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func writeEntry(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	header := &tar.Header{Name: name, Mode: 0o600, Size: int64(len(data)), ModTime: modTime, Typeflag: tar.TypeReg}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// Reads an archive written by write. Entries outside the kind directories are rejected.
+func readArchive(r io.Reader) (*archive, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a nim backup: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	a := &archive{entries: map[string][]byte{}}
+	foundIndex := false
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read backup: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, maxEntrySize+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", header.Name, err)
+		}
+		if len(data) > maxEntrySize {
+			return nil, fmt.Errorf("%s is larger than %d bytes", header.Name, maxEntrySize)
+		}
+
+		if header.Name == indexFile {
+			if err := yaml.UnmarshalStrict(data, &a.index); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", indexFile, err)
+			}
+			foundIndex = true
+			continue
+		}
+		dir, file := path.Split(header.Name)
+		if kindOf(strings.TrimSuffix(dir, "/")) == "" || file == "" || strings.Contains(file, "..") {
+			return nil, fmt.Errorf("unexpected entry %s in backup", header.Name)
+		}
+		a.entries[header.Name] = data
+	}
+
+	if !foundIndex {
+		return nil, fmt.Errorf("not a nim backup: %s is missing", indexFile)
+	}
+	if a.index.Version != archiveVersion {
+		return nil, fmt.Errorf("unsupported backup version %d, this nim reads version %d", a.index.Version, archiveVersion)
+	}
+	return a, nil
+}
+
+func kindOf(dir string) string {
+	for _, k := range kinds {
+		if k.dir == dir {
+			return k.kind
+		}
+	}
+	return ""
+}
@@ -0,0 +1,341 @@
+package backup
+
+import (
+	"context"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/yaml"
+
+	"k8s-nim-operator-cli/pkg/util"
+	"k8s-nim-operator-cli/pkg/util/client"
+)
+
+// Environment variable holding the passphrase, when --passphrase-file is not set.
+const passphraseEnv = "NIM_BACKUP_PASSPHRASE"
+
+// PVC fields and annotations that bind a claim to its volume. Dropped so a restored claim provisions a new volume.
+var pvcBindingAnnotations = []string{
+	"pv.kubernetes.io/bind-completed",
+	"pv.kubernetes.io/bound-by-controller",
+	"volume.beta.kubernetes.io/storage-provisioner",
+	"volume.kubernetes.io/storage-provisioner",
+	"volume.kubernetes.io/selected-node",
+}
+
+type BackupOptions struct {
+	IoStreams  *genericclioptions.IOStreams
+	Namespace  string
+	Output     string
+	Encrypt    bool
+	Passphrase string
+}
+
+func NewBackupCommand(cmdFactory cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	options := &BackupOptions{IoStreams: &streams}
+	var passphraseFile string
+
+	cmd := &cobra.Command{
+		Use:   "backup -o FILE",
+		Short: "Back up the NIM resources of a namespace",
+		Long: `Back up the NIMCaches, NIMServices and NIMPipelines of a namespace into a gzipped tar archive, together with the
+secrets they reference (auth, image pull, environment and Ingress TLS secrets) and the specs of the existing PVCs they
+mount. PVCs the operator creates are left out, since restoring the objects creates them again. Volume contents are not
+backed up: restored caches download their models again.
+
+Objects are stored without status, server-set metadata or namespace, as in nim export. Secrets are stored in plain text
+unless --encrypt is set, which encrypts them with AES-256-GCM using the passphrase in --passphrase-file or the
+` + passphraseEnv + ` environment variable.
+
+Restore the archive with nim restore.`,
+		Example: `  nim backup -n nim -o backup.tar.gz
+  NIM_BACKUP_PASSPHRASE=... nim backup -n nim -o backup.tar.gz --encrypt`,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if options.Output == "" {
+				cmd.HelpFunc()(cmd, args)
+				return nil
+			}
+			namespace, err := cmd.Flags().GetString("namespace")
+			if err != nil {
+				return fmt.Errorf("failed to get namespace: %w", err)
+			}
+			options.Namespace = namespace
+			if options.Namespace == "" {
+				options.Namespace = "default"
+			}
+			if options.Encrypt {
+				if options.Passphrase, err = readPassphrase(passphraseFile); err != nil {
+					return err
+				}
+			} else if passphraseFile != "" {
+				return errors.New("--passphrase-file requires --encrypt")
+			}
+			k8sClient, err := client.NewClient(cmdFactory)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			return RunBackup(cmd.Context(), options, k8sClient)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.Output, "output", "o", "", "Path of the archive to write, like backup.tar.gz.")
+	cmd.Flags().BoolVar(&options.Encrypt, "encrypt", false, "Encrypt the backed up secrets with a passphrase.")
+	cmd.Flags().StringVar(&passphraseFile, "passphrase-file", "", "File holding the passphrase. Defaults to the "+passphraseEnv+" environment variable.")
+
+	return cmd
+}
+
+// Reads the passphrase from file, or from the environment when file is empty.
+func readPassphrase(file string) (string, error) {
+	passphrase := os.Getenv(passphraseEnv)
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("failed to read the passphrase: %w", err)
+		}
+		passphrase = strings.TrimRight(string(data), "\r\n")
+	}
+	if passphrase == "" {
+		return "", fmt.Errorf("a passphrase is required: set --passphrase-file or %s", passphraseEnv)
+	}
+	return passphrase, nil
+}
+
+func RunBackup(ctx context.Context, options *BackupOptions, k8sClient client.Client) error {
+	namespace := options.Namespace
+	nimClient := k8sClient.NIMClient().AppsV1alpha1()
+	kubeClient := k8sClient.KubernetesClient().CoreV1()
+
+	nimCaches, err := nimClient.NIMCaches(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to retrieve NIMCaches for namespace %s: %w", namespace, err)
+	}
+	nimServices, err := nimClient.NIMServices(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to retrieve NIMServices for namespace %s: %w", namespace, err)
+	}
+	nimPipelines, err := nimClient.NIMPipelines(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to retrieve NIMPipelines for namespace %s: %w", namespace, err)
+	}
+	if len(nimCaches.Items)+len(nimServices.Items)+len(nimPipelines.Items) == 0 {
+		return fmt.Errorf("no NIMCaches, NIMServices or NIMPipelines found in namespace %s", namespace)
+	}
+
+	a := &archive{
+		index:   index{Version: archiveVersion, Namespace: namespace, Created: time.Now().UTC().Truncate(time.Second)},
+		entries: map[string][]byte{},
+	}
+	var aead cipher.AEAD
+	if options.Encrypt {
+		if a.index.Encryption, err = newEncryption(); err != nil {
+			return err
+		}
+		if aead, err = a.index.Encryption.aead(options.Passphrase); err != nil {
+			return err
+		}
+	}
+
+	refs := &references{secrets: map[string]string{}, pvcs: map[string]string{}}
+	counts := map[string]int{}
+	add := func(kind string, obj runtime.Object, name string) error {
+		data, err := cleanYAML(kind, obj)
+		if err != nil {
+			return fmt.Errorf("failed to back up %s %s: %w", kind, name, err)
+		}
+		encrypted := kind == "Secret" && aead != nil
+		if encrypted {
+			if data, err = seal(aead, data); err != nil {
+				return err
+			}
+		}
+		a.entries[entryPath(kind, name, encrypted)] = data
+		counts[kind]++
+		return nil
+	}
+
+	for i := range nimCaches.Items {
+		nimCache := &nimCaches.Items[i]
+		refs.addNIMCache(nimCache)
+		if err := add("NIMCache", nimCache, nimCache.Name); err != nil {
+			return err
+		}
+	}
+	for i := range nimServices.Items {
+		nimService := &nimServices.Items[i]
+		refs.addNIMServiceSpec(&nimService.Spec, "NIMService "+nimService.Name)
+		if err := add("NIMService", nimService, nimService.Name); err != nil {
+			return err
+		}
+	}
+	for i := range nimPipelines.Items {
+		nimPipeline := &nimPipelines.Items[i]
+		for j := range nimPipeline.Spec.Services {
+			refs.addNIMServiceSpec(&nimPipeline.Spec.Services[j].Spec, "NIMPipeline "+nimPipeline.Name)
+		}
+		if err := add("NIMPipeline", nimPipeline, nimPipeline.Name); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range sortedKeys(refs.secrets) {
+		secret, err := kubeClient.Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			fmt.Fprintf(options.IoStreams.ErrOut, "Warning: secret %s referenced by %s not found, skipped\n", name, refs.secrets[name])
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("unable to retrieve secret %s: %w", name, err)
+		}
+		if err := add("Secret", secret, name); err != nil {
+			return err
+		}
+	}
+	for _, name := range sortedKeys(refs.pvcs) {
+		pvc, err := kubeClient.PersistentVolumeClaims(namespace).Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			fmt.Fprintf(options.IoStreams.ErrOut, "Warning: PVC %s referenced by %s not found, skipped\n", name, refs.pvcs[name])
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("unable to retrieve PVC %s: %w", name, err)
+		}
+		if err := add("PersistentVolumeClaim", pvc, name); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.OpenFile(options.Output, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", options.Output, err)
+	}
+	if err := a.write(file); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %s: %w", options.Output, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", options.Output, err)
+	}
+
+	if counts["Secret"] > 0 && aead == nil {
+		fmt.Fprintf(options.IoStreams.ErrOut, "Warning: %s holds secrets in plain text; use --encrypt to encrypt them\n", options.Output)
+	}
+	var summary []string
+	for _, k := range kinds {
+		summary = append(summary, fmt.Sprintf("%d %s(s)", counts[k.kind], k.kind))
+	}
+	fmt.Fprintf(options.IoStreams.Out, "Backed up namespace %s to %s: %s\n", namespace, options.Output, strings.Join(summary, ", "))
+	return nil
+}
+
+// The secrets and PVCs the backed up objects reference, by name, with the first object referencing each.
+type references struct {
+	secrets map[string]string
+	pvcs    map[string]string
+}
+
+func (r *references) addSecret(name, owner string) {
+	if _, ok := r.secrets[name]; name != "" && !ok {
+		r.secrets[name] = owner
+	}
+}
+
+// Records a PVC the object mounts but does not create. Claims the operator creates are created again on restore.
+func (r *references) addPVC(pvc appsv1alpha1.PersistentVolumeClaim, owner string) {
+	if pvc.Name == "" || (pvc.Create != nil && *pvc.Create) {
+		return
+	}
+	if _, ok := r.pvcs[pvc.Name]; !ok {
+		r.pvcs[pvc.Name] = owner
+	}
+}
+
+// Records the secrets environment variables are read from.
+func (r *references) addEnv(env []corev1.EnvVar, owner string) {
+	for _, e := range env {
+		if e.ValueFrom != nil && e.ValueFrom.SecretKeyRef != nil {
+			r.addSecret(e.ValueFrom.SecretKeyRef.Name, owner)
+		}
+	}
+}
+
+func (r *references) addNIMCache(nimCache *appsv1alpha1.NIMCache) {
+	owner := "NIMCache " + nimCache.Name
+	source := nimCache.Spec.Source
+	switch {
+	case source.NGC != nil:
+		r.addSecret(source.NGC.AuthSecret, owner)
+		r.addSecret(source.NGC.PullSecret, owner)
+	case source.HF != nil:
+		r.addSecret(source.HF.AuthSecret, owner)
+		r.addSecret(source.HF.PullSecret, owner)
+	case source.DataStore != nil:
+		r.addSecret(source.DataStore.AuthSecret, owner)
+		r.addSecret(source.DataStore.PullSecret, owner)
+	}
+	r.addEnv(nimCache.Spec.Env, owner)
+	r.addPVC(nimCache.Spec.Storage.PVC, owner)
+}
+
+func (r *references) addNIMServiceSpec(spec *appsv1alpha1.NIMServiceSpec, owner string) {
+	r.addSecret(spec.AuthSecret, owner)
+	for _, pullSecret := range spec.Image.PullSecrets {
+		r.addSecret(pullSecret, owner)
+	}
+	r.addEnv(spec.Env, owner)
+	for _, tls := range spec.Expose.Ingress.Spec.TLS {
+		r.addSecret(tls.SecretName, owner)
+	}
+	r.addPVC(spec.Storage.PVC, owner)
+}
+
+// Returns the object as YAML, cleaned like nim export and without its namespace. Claims also lose their binding to
+// a volume.
+func cleanYAML(kind string, obj runtime.Object) ([]byte, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	u := &unstructured.Unstructured{Object: content}
+	if kind == "Secret" || kind == "PersistentVolumeClaim" {
+		u.SetAPIVersion(corev1.SchemeGroupVersion.String())
+	} else {
+		u.SetAPIVersion(appsv1alpha1.SchemeGroupVersion.String())
+	}
+	u.SetKind(kind)
+	if kind == "PersistentVolumeClaim" {
+		unstructured.RemoveNestedField(u.Object, "spec", "volumeName")
+		annotations := u.GetAnnotations()
+		for _, annotation := range pvcBindingAnnotations {
+			delete(annotations, annotation)
+		}
+		u.SetAnnotations(annotations)
+	}
+	util.Clean(u, false)
+	return yaml.Marshal(u.Object)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,432 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	k8stesting "k8s.io/client-go/testing"
+
+	"k8s-nim-operator-cli/pkg/util/client/fake"
+)
+
+// Records the resources created through either clientset, in order.
+func newRecordingClient(created *[]string, kubeObjects, nimObjects []runtime.Object) *fake.Client {
+	record := func(action k8stesting.Action) (bool, runtime.Object, error) {
+		*created = append(*created, action.GetResource().Resource)
+		return false, nil, nil
+	}
+	client := fake.NewFakeClient(kubeObjects, nimObjects)
+	client.Kube.PrependReactor("create", "*", record)
+	client.NIM.PrependReactor("create", "*", record)
+	return client
+}
+
+func newTestStreams() (*genericclioptions.IOStreams, *bytes.Buffer, *bytes.Buffer) {
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	return &genericclioptions.IOStreams{In: &bytes.Buffer{}, Out: out, ErrOut: errOut}, out, errOut
+}
+
+func ptr[T any](v T) *T { return &v }
+
+// A namespace with a cache, two services and a pipeline, the secrets and the PVC they reference, and objects that
+// must not be backed up.
+func sourceObjects() ([]runtime.Object, []runtime.Object) {
+	meta := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: "nim", UID: "1234", ResourceVersion: "42"}
+	}
+
+	nimCache := &appsv1alpha1.NIMCache{ObjectMeta: meta("llama3-cache")}
+	nimCache.Spec.Source.NGC = &appsv1alpha1.NGCSource{AuthSecret: "ngc-api-secret", PullSecret: "ngc-secret", ModelPuller: "nvcr.io/nim/meta/llama3-8b-instruct:1.0.3"}
+	nimCache.Spec.Storage.PVC = appsv1alpha1.PersistentVolumeClaim{Create: ptr(true), Name: "llama3-cache-pvc", Size: "50Gi"}
+	nimCache.Status.State = appsv1alpha1.NimCacheStatusReady
+
+	llama3 := &appsv1alpha1.NIMService{ObjectMeta: meta("llama3")}
+	llama3.Spec.Image = appsv1alpha1.Image{Repository: "nvcr.io/nim/meta/llama3-8b-instruct", Tag: "1.0.3", PullSecrets: []string{"ngc-secret"}}
+	llama3.Spec.AuthSecret = "ngc-api-secret"
+	llama3.Spec.Storage.NIMCache.Name = "llama3-cache"
+	llama3.Status.State = "Ready"
+
+	mistral := &appsv1alpha1.NIMService{ObjectMeta: meta("mistral")}
+	mistral.Spec.Image = appsv1alpha1.Image{Repository: "nvcr.io/nim/mistralai/mistral-7b-instruct-v03", Tag: "1.0.0"}
+	mistral.Spec.AuthSecret = "missing-secret"
+	mistral.Spec.Storage.PVC = appsv1alpha1.PersistentVolumeClaim{Name: "models"}
+
+	pipeline := &appsv1alpha1.NIMPipeline{ObjectMeta: meta("rag")}
+	pipeline.Spec.Services = []appsv1alpha1.NIMServicePipelineSpec{{Name: "embedding", Spec: llama3.Spec}}
+
+	other := &appsv1alpha1.NIMService{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "other"}}
+
+	apiKey := &corev1.Secret{ObjectMeta: meta("ngc-api-secret"), Type: corev1.SecretTypeOpaque, Data: map[string][]byte{"NGC_API_KEY": []byte("nvapi-test-key")}}
+	pullSecret := &corev1.Secret{ObjectMeta: meta("ngc-secret"), Type: corev1.SecretTypeDockerConfigJson, Data: map[string][]byte{".dockerconfigjson": []byte("{}")}}
+	unrelated := &corev1.Secret{ObjectMeta: meta("unrelated")}
+	models := &corev1.PersistentVolumeClaim{ObjectMeta: meta("models")}
+	models.Annotations = map[string]string{"pv.kubernetes.io/bind-completed": "yes", "team": "nlp"}
+	models.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}
+	models.Spec.Resources.Requests = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("100Gi")}
+	models.Spec.VolumeName = "pvc-1234"
+	models.Status.Phase = corev1.ClaimBound
+
+	return []runtime.Object{apiKey, pullSecret, unrelated, models}, []runtime.Object{nimCache, llama3, mistral, pipeline, other}
+}
+
+func backupSource(t *testing.T, encrypt bool, passphrase string) (string, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+	var created []string
+	kubeObjects, nimObjects := sourceObjects()
+	client := newRecordingClient(&created, kubeObjects, nimObjects)
+	streams, out, errOut := newTestStreams()
+	file := filepath.Join(t.TempDir(), "backup.tar.gz")
+	options := &BackupOptions{IoStreams: streams, Namespace: "nim", Output: file, Encrypt: encrypt, Passphrase: passphrase}
+	if err := RunBackup(context.Background(), options, client); err != nil {
+		t.Fatalf("backup failed: %v", err)
+	}
+	return file, out, errOut
+}
+
+func readTestArchive(t *testing.T, file string) *archive {
+	t.Helper()
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	a, err := readArchive(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func Test_RunBackup(t *testing.T) {
+	file, out, errOut := backupSource(t, false, "")
+
+	if want := "Backed up namespace nim to " + file + ": 2 Secret(s), 1 PersistentVolumeClaim(s), 1 NIMCache(s), 2 NIMService(s), 1 NIMPipeline(s)\n"; out.String() != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", out.String(), want)
+	}
+	for _, want := range []string{
+		"Warning: secret missing-secret referenced by NIMService mistral not found, skipped",
+		"holds secrets in plain text",
+	} {
+		if !strings.Contains(errOut.String(), want) {
+			t.Errorf("expected %q in errors:\n%s", want, errOut.String())
+		}
+	}
+
+	a := readTestArchive(t, file)
+	if a.index.Namespace != "nim" || a.index.Encryption != nil {
+		t.Errorf("unexpected index: %+v", a.index)
+	}
+	var paths []string
+	for p := range a.entries {
+		paths = append(paths, p)
+	}
+	wantPaths := []string{
+		"nimcaches/llama3-cache.yaml",
+		"nimpipelines/rag.yaml",
+		"nimservices/llama3.yaml",
+		"nimservices/mistral.yaml",
+		"persistentvolumeclaims/models.yaml",
+		"secrets/ngc-api-secret.yaml",
+		"secrets/ngc-secret.yaml",
+	}
+	if !reflect.DeepEqual(sortedKeys(toSet(paths)), wantPaths) {
+		t.Errorf("unexpected entries %v, want %v", sortedKeys(toSet(paths)), wantPaths)
+	}
+
+	pvc := string(a.entries["persistentvolumeclaims/models.yaml"])
+	for _, unwanted := range []string{"volumeName", "bind-completed", "status", "namespace", "uid"} {
+		if strings.Contains(pvc, unwanted) {
+			t.Errorf("expected %q to be stripped from the PVC:\n%s", unwanted, pvc)
+		}
+	}
+	if !strings.Contains(pvc, "team: nlp") {
+		t.Errorf("expected the PVC's own annotations to be kept:\n%s", pvc)
+	}
+}
+
+func toSet(items []string) map[string]string {
+	set := map[string]string{}
+	for _, item := range items {
+		set[item] = ""
+	}
+	return set
+}
+
+func Test_RunRestore(t *testing.T) {
+	file, _, _ := backupSource(t, false, "")
+
+	var created []string
+	client := newRecordingClient(&created, nil, nil)
+	streams, out, _ := newTestStreams()
+	options := &RestoreOptions{IoStreams: streams, Filename: file, Namespace: "nim-copy", Timeout: time.Minute, pollInterval: time.Millisecond}
+	if err := RunRestore(context.Background(), options, client); err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+
+	wantOrder := []string{"secrets", "secrets", "persistentvolumeclaims", "nimcaches", "nimservices", "nimservices", "nimpipelines"}
+	if !reflect.DeepEqual(created, wantOrder) {
+		t.Errorf("unexpected creation order %v, want %v", created, wantOrder)
+	}
+	if !strings.HasPrefix(out.String(), "Restoring 7 object(s) from namespace nim into namespace nim-copy\nsecret/ngc-api-secret created\n") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+
+	ctx := context.Background()
+	secret, err := client.Kube.CoreV1().Secrets("nim-copy").Get(ctx, "ngc-api-secret", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(secret.Data["NGC_API_KEY"]) != "nvapi-test-key" {
+		t.Errorf("unexpected secret data %v", secret.Data)
+	}
+	nimService, err := client.NIM.AppsV1alpha1().NIMServices("nim-copy").Get(ctx, "llama3", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nimService.Status.State != "" || nimService.UID != "" || nimService.Spec.Storage.NIMCache.Name != "llama3-cache" {
+		t.Errorf("unexpected restored NIMService: %+v", nimService)
+	}
+	if _, err := client.NIM.AppsV1alpha1().NIMPipelines("nim-copy").Get(ctx, "rag", metav1.GetOptions{}); err != nil {
+		t.Error(err)
+	}
+
+	// Restoring again leaves the existing objects alone.
+	streams, out, _ = newTestStreams()
+	options.IoStreams = streams
+	if err := RunRestore(ctx, options, client); err != nil {
+		t.Fatalf("second restore failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "nimservice/llama3 unchanged, already exists\n") {
+		t.Errorf("expected existing objects to be reported:\n%s", out.String())
+	}
+}
+
+func Test_RunRestore_OriginalNamespace(t *testing.T) {
+	file, _, _ := backupSource(t, false, "")
+
+	var created []string
+	client := newRecordingClient(&created, nil, nil)
+	streams, _, _ := newTestStreams()
+	options := &RestoreOptions{IoStreams: streams, Filename: file, Timeout: time.Minute}
+	if err := RunRestore(context.Background(), options, client); err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+	if _, err := client.NIM.AppsV1alpha1().NIMCaches("nim").Get(context.Background(), "llama3-cache", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected the cache in the original namespace: %v", err)
+	}
+}
+
+func Test_Encryption(t *testing.T) {
+	file, _, errOut := backupSource(t, true, "correct horse")
+	if strings.Contains(errOut.String(), "plain text") {
+		t.Errorf("unexpected plain text warning:\n%s", errOut.String())
+	}
+
+	a := readTestArchive(t, file)
+	if a.index.Encryption == nil || len(a.index.Encryption.Salt) == 0 {
+		t.Fatalf("expected encryption settings in the index: %+v", a.index)
+	}
+	data, ok := a.entries["secrets/ngc-api-secret.yaml.enc"]
+	if !ok {
+		t.Fatalf("expected an encrypted secret entry")
+	}
+	if bytes.Contains(data, []byte("NGC_API_KEY")) {
+		t.Errorf("secret stored in plain text")
+	}
+	if _, ok := a.entries["nimservices/llama3.yaml"]; !ok {
+		t.Errorf("expected other objects to be stored in plain text")
+	}
+
+	for _, tc := range []struct {
+		passphrase string
+		wantErr    string
+	}{
+		{"", "is encrypted: set --passphrase-file or NIM_BACKUP_PASSPHRASE"},
+		{"wrong", "wrong passphrase or corrupted backup"},
+	} {
+		var created []string
+		client := newRecordingClient(&created, nil, nil)
+		streams, _, _ := newTestStreams()
+		options := &RestoreOptions{IoStreams: streams, Filename: file, Passphrase: tc.passphrase, Timeout: time.Minute}
+		err := RunRestore(context.Background(), options, client)
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("passphrase %q: expected error %q, got %v", tc.passphrase, tc.wantErr, err)
+		}
+		if len(created) != 0 {
+			t.Errorf("passphrase %q: expected nothing to be created, got %v", tc.passphrase, created)
+		}
+	}
+
+	var created []string
+	client := newRecordingClient(&created, nil, nil)
+	streams, _, _ := newTestStreams()
+	options := &RestoreOptions{IoStreams: streams, Filename: file, Passphrase: "correct horse", Timeout: time.Minute}
+	if err := RunRestore(context.Background(), options, client); err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+	secret, err := client.Kube.CoreV1().Secrets("nim").Get(context.Background(), "ngc-api-secret", metav1.GetOptions{})
+	if err != nil || string(secret.Data["NGC_API_KEY"]) != "nvapi-test-key" {
+		t.Errorf("unexpected restored secret %v: %v", secret, err)
+	}
+}
+
+// Serves the restored cache with the given states, one per get, repeating the last.
+func serveNIMCacheStates(client *fake.Client, states ...string) {
+	client.NIM.PrependReactor("get", "nimcaches", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		nimCache := &appsv1alpha1.NIMCache{ObjectMeta: metav1.ObjectMeta{Name: "llama3-cache", Namespace: action.GetNamespace()}}
+		nimCache.Status.State = states[0]
+		if len(states) > 1 {
+			states = states[1:]
+		}
+		return true, nimCache, nil
+	})
+}
+
+func Test_RunRestore_Wait(t *testing.T) {
+	file, _, _ := backupSource(t, false, "")
+
+	var created []string
+	client := newRecordingClient(&created, nil, nil)
+	serveNIMCacheStates(client, appsv1alpha1.NimCacheStatusInProgress, appsv1alpha1.NimCacheStatusInProgress, appsv1alpha1.NimCacheStatusReady)
+	streams, out, _ := newTestStreams()
+	options := &RestoreOptions{IoStreams: streams, Filename: file, Wait: true, Timeout: time.Minute, pollInterval: time.Millisecond}
+	if err := RunRestore(context.Background(), options, client); err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+	want := "nimcache/llama3-cache created\nWaiting for NIMCache llama3-cache to be Ready\nNIMCache llama3-cache is Ready\nnimservice/llama3 created\n"
+	if !strings.Contains(out.String(), want) {
+		t.Errorf("expected the service to be created after the cache is Ready:\n%s", out.String())
+	}
+	// The pipeline uses the same cache and does not wait again.
+	if strings.Count(out.String(), "Waiting for NIMCache") != 1 {
+		t.Errorf("expected a single wait:\n%s", out.String())
+	}
+}
+
+func Test_RunRestore_WaitFailed(t *testing.T) {
+	file, _, _ := backupSource(t, false, "")
+
+	var created []string
+	client := newRecordingClient(&created, nil, nil)
+	serveNIMCacheStates(client, appsv1alpha1.NimCacheStatusFailed)
+	streams, _, errOut := newTestStreams()
+	options := &RestoreOptions{IoStreams: streams, Filename: file, Wait: true, Timeout: time.Minute, pollInterval: time.Millisecond}
+	err := RunRestore(context.Background(), options, client)
+	if err == nil || err.Error() != "2 object(s) could not be restored" {
+		t.Fatalf("expected the service and pipeline to fail, got %v", err)
+	}
+	for _, want := range []string{
+		"nimservices/llama3.yaml: NIMService llama3 not created: NIMCache llama3-cache failed",
+		"nimpipelines/rag.yaml: NIMPipeline rag not created: NIMCache llama3-cache failed",
+	} {
+		if !strings.Contains(errOut.String(), want) {
+			t.Errorf("expected %q in errors:\n%s", want, errOut.String())
+		}
+	}
+	// mistral uses no cache and is still restored.
+	wantOrder := []string{"secrets", "secrets", "persistentvolumeclaims", "nimcaches", "nimservices"}
+	if !reflect.DeepEqual(created, wantOrder) {
+		t.Errorf("unexpected creation order %v, want %v", created, wantOrder)
+	}
+
+	options = &RestoreOptions{IoStreams: streams, Filename: file, Wait: true, Timeout: 10 * time.Millisecond, pollInterval: time.Millisecond}
+	serveNIMCacheStates(client, appsv1alpha1.NimCacheStatusInProgress)
+	streams, _, errOut = newTestStreams()
+	options.IoStreams = streams
+	if err := RunRestore(context.Background(), options, client); err == nil || !strings.Contains(errOut.String(), "timed out after 10ms waiting for NIMCache llama3-cache to be Ready") {
+		t.Errorf("expected a timeout, got %v:\n%s", err, errOut.String())
+	}
+}
+
+func Test_readArchive_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	notGzip := filepath.Join(dir, "backup.tar.gz")
+	if err := os.WriteFile(notGzip, []byte("not a backup"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	streams, _, _ := newTestStreams()
+	options := &RestoreOptions{IoStreams: streams, Filename: notGzip, Timeout: time.Minute}
+	if err := RunRestore(context.Background(), options, fake.NewFakeClient(nil, nil)); err == nil || !strings.Contains(err.Error(), "not a nim backup") {
+		t.Errorf("expected an invalid backup error, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	a := &archive{index: index{Version: archiveVersion + 1, Namespace: "nim"}, entries: map[string][]byte{}}
+	if err := a.write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readArchive(&buf); err == nil || !strings.Contains(err.Error(), "unsupported backup version 2") {
+		t.Errorf("expected a version error, got %v", err)
+	}
+
+	buf.Reset()
+	a = &archive{index: index{Version: archiveVersion, Namespace: "nim"}, entries: map[string][]byte{"configmaps/settings.yaml": nil}}
+	if err := a.write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readArchive(&buf); err == nil || !strings.Contains(err.Error(), "unexpected entry configmaps/settings.yaml") {
+		t.Errorf("expected an unexpected entry error, got %v", err)
+	}
+}
+
+func Test_RunBackup_SecretReferences(t *testing.T) {
+	meta := metav1.ObjectMeta{Name: "llama3", Namespace: "nim"}
+	envFromSecret := []corev1.EnvVar{{
+		Name:      "HF_TOKEN",
+		ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "env-secret"}, Key: "token"}},
+	}}
+	spec := appsv1alpha1.NIMServiceSpec{Env: envFromSecret}
+	spec.Expose.Ingress.Spec.TLS = []networkingv1.IngressTLS{{Hosts: []string{"llama3.example.com"}, SecretName: "tls-secret"}}
+
+	nimService := &appsv1alpha1.NIMService{ObjectMeta: meta, Spec: spec}
+	nimPipeline := &appsv1alpha1.NIMPipeline{ObjectMeta: meta}
+	nimPipeline.Spec.Services = []appsv1alpha1.NIMServicePipelineSpec{{Name: "llm", Spec: spec}}
+	nimCache := &appsv1alpha1.NIMCache{ObjectMeta: meta}
+	nimCache.Spec.Env = envFromSecret
+
+	tests := []struct {
+		name    string
+		object  runtime.Object
+		secrets []string
+	}{
+		{"NIMService", nimService, []string{"secrets/env-secret.yaml", "secrets/tls-secret.yaml"}},
+		{"NIMPipeline", nimPipeline, []string{"secrets/env-secret.yaml", "secrets/tls-secret.yaml"}},
+		{"NIMCache", nimCache, []string{"secrets/env-secret.yaml"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created []string
+			kubeObjects := []runtime.Object{
+				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "env-secret", Namespace: "nim"}, Data: map[string][]byte{"token": []byte("hf-test-token")}},
+				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "tls-secret", Namespace: "nim"}, Type: corev1.SecretTypeTLS},
+			}
+			client := newRecordingClient(&created, kubeObjects, []runtime.Object{tt.object})
+			streams, _, _ := newTestStreams()
+			file := filepath.Join(t.TempDir(), "backup.tar.gz")
+			options := &BackupOptions{IoStreams: streams, Namespace: "nim", Output: file}
+			if err := RunBackup(context.Background(), options, client); err != nil {
+				t.Fatalf("backup failed: %v", err)
+			}
+
+			a := readTestArchive(t, file)
+			for _, secret := range tt.secrets {
+				if _, ok := a.entries[secret]; !ok {
+					t.Errorf("expected %s in the backup", secret)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,296 @@
+package backup
+
+import (
+	"context"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/yaml"
+
+	"k8s-nim-operator-cli/pkg/util/client"
+)
+
+const defaultPollInterval = 5 * time.Second
+
+type RestoreOptions struct {
+	IoStreams *genericclioptions.IOStreams
+	Filename  string
+	// Namespace to restore into. Empty restores into the namespace the backup was taken from.
+	Namespace  string
+	Wait       bool
+	Timeout    time.Duration
+	Passphrase string
+
+	pollInterval time.Duration
+}
+
+func NewRestoreCommand(cmdFactory cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	options := &RestoreOptions{IoStreams: &streams, pollInterval: defaultPollInterval}
+	var passphraseFile string
+
+	cmd := &cobra.Command{
+		Use:   "restore FILE",
+		Short: "Restore NIM resources from a backup",
+		Long: `Restore the objects in an archive written by nim backup, in dependency order: secrets and PVCs first, then NIMCaches,
+then NIMServices and NIMPipelines.
+
+Objects are restored into the namespace the backup was taken from, or into --namespace. Objects that already exist
+are left unchanged. With --wait, each NIMService and NIMPipeline is created only once the NIMCaches it uses are Ready.
+
+An encrypted backup needs the passphrase it was written with, in --passphrase-file or the ` + passphraseEnv + `
+environment variable.`,
+		Example: `  nim restore backup.tar.gz
+  nim restore backup.tar.gz --namespace nim-copy --wait --timeout 2h`,
+		SilenceUsage: true,
+		Args:         cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				cmd.HelpFunc()(cmd, args)
+				return nil
+			}
+			options.Filename = args[0]
+			if cmd.Flags().Changed("namespace") {
+				namespace, err := cmd.Flags().GetString("namespace")
+				if err != nil {
+					return fmt.Errorf("failed to get namespace: %w", err)
+				}
+				options.Namespace = namespace
+			}
+			if options.Timeout <= 0 {
+				return fmt.Errorf("--timeout must be positive, got %s", options.Timeout)
+			}
+			// Only required when the backup is encrypted, which Run checks.
+			if passphrase, err := readPassphrase(passphraseFile); err == nil {
+				options.Passphrase = passphrase
+			} else if passphraseFile != "" {
+				return err
+			}
+			k8sClient, err := client.NewClient(cmdFactory)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			return RunRestore(cmd.Context(), options, k8sClient)
+		},
+	}
+
+	cmd.Flags().BoolVar(&options.Wait, "wait", false, "Wait for each NIMCache to be Ready before creating the NIMServices and NIMPipelines that use it.")
+	cmd.Flags().DurationVar(&options.Timeout, "timeout", time.Hour, "How long to wait for each NIMCache with --wait.")
+	cmd.Flags().StringVar(&passphraseFile, "passphrase-file", "", "File holding the passphrase of an encrypted backup. Defaults to the "+passphraseEnv+" environment variable.")
+
+	return cmd
+}
+
+type restorer struct {
+	options   *RestoreOptions
+	k8sClient client.Client
+	namespace string
+	// Result of waiting for each NIMCache, so several services using one cache wait once.
+	waited map[string]error
+}
+
+func RunRestore(ctx context.Context, options *RestoreOptions, k8sClient client.Client) error {
+	file, err := os.Open(options.Filename)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", options.Filename, err)
+	}
+	defer file.Close()
+	a, err := readArchive(file)
+	if err != nil {
+		return fmt.Errorf("%s: %w", options.Filename, err)
+	}
+
+	var aead cipher.AEAD
+	if a.index.Encryption != nil {
+		if options.Passphrase == "" {
+			return fmt.Errorf("%s is encrypted: set --passphrase-file or %s", options.Filename, passphraseEnv)
+		}
+		if aead, err = a.index.Encryption.aead(options.Passphrase); err != nil {
+			return err
+		}
+	}
+
+	r := &restorer{options: options, k8sClient: k8sClient, namespace: options.Namespace, waited: map[string]error{}}
+	if r.namespace == "" {
+		r.namespace = a.index.Namespace
+	}
+
+	// Decode everything first, so a wrong passphrase or a corrupt entry fails before anything is created.
+	type entry struct {
+		path, kind string
+		data       []byte
+	}
+	var entries []entry
+	for _, k := range kinds {
+		var paths []string
+		for p := range a.entries {
+			if strings.HasPrefix(p, k.dir+"/") {
+				paths = append(paths, p)
+			}
+		}
+		sort.Strings(paths)
+		for _, p := range paths {
+			data := a.entries[p]
+			if strings.HasSuffix(p, encryptedSuffix) {
+				if aead == nil {
+					return fmt.Errorf("%s: %s is encrypted, but the backup has no encryption settings", options.Filename, p)
+				}
+				if data, err = open(aead, data); err != nil {
+					return fmt.Errorf("%s: %s: %w", options.Filename, p, err)
+				}
+			}
+			entries = append(entries, entry{p, k.kind, data})
+		}
+	}
+
+	fmt.Fprintf(options.IoStreams.Out, "Restoring %d object(s) from namespace %s into namespace %s\n", len(entries), a.index.Namespace, r.namespace)
+	failed := 0
+	for _, e := range entries {
+		if err := r.restore(ctx, e.kind, e.data); err != nil {
+			fmt.Fprintf(options.IoStreams.ErrOut, "%s: %v\n", e.path, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d object(s) could not be restored", failed)
+	}
+	return nil
+}
+
+// Creates one object in the target namespace, first waiting for the NIMCaches it uses with --wait.
+func (r *restorer) restore(ctx context.Context, kind string, data []byte) error {
+	nimClient := r.k8sClient.NIMClient().AppsV1alpha1()
+	kubeClient := r.k8sClient.KubernetesClient().CoreV1()
+	createOptions := metav1.CreateOptions{}
+
+	var name string
+	var err error
+	switch kind {
+	case "Secret":
+		obj := &corev1.Secret{}
+		if err := decode(data, obj, r.namespace); err != nil {
+			return err
+		}
+		name = obj.Name
+		_, err = kubeClient.Secrets(r.namespace).Create(ctx, obj, createOptions)
+	case "PersistentVolumeClaim":
+		obj := &corev1.PersistentVolumeClaim{}
+		if err := decode(data, obj, r.namespace); err != nil {
+			return err
+		}
+		name = obj.Name
+		_, err = kubeClient.PersistentVolumeClaims(r.namespace).Create(ctx, obj, createOptions)
+	case "NIMCache":
+		obj := &appsv1alpha1.NIMCache{}
+		if err := decode(data, obj, r.namespace); err != nil {
+			return err
+		}
+		name = obj.Name
+		_, err = nimClient.NIMCaches(r.namespace).Create(ctx, obj, createOptions)
+	case "NIMService":
+		obj := &appsv1alpha1.NIMService{}
+		if err := decode(data, obj, r.namespace); err != nil {
+			return err
+		}
+		name = obj.Name
+		if err := r.waitForNIMCaches(ctx, obj.Spec.Storage.NIMCache.Name); err != nil {
+			return fmt.Errorf("NIMService %s not created: %w", name, err)
+		}
+		_, err = nimClient.NIMServices(r.namespace).Create(ctx, obj, createOptions)
+	case "NIMPipeline":
+		obj := &appsv1alpha1.NIMPipeline{}
+		if err := decode(data, obj, r.namespace); err != nil {
+			return err
+		}
+		name = obj.Name
+		var nimCaches []string
+		for _, service := range obj.Spec.Services {
+			nimCaches = append(nimCaches, service.Spec.Storage.NIMCache.Name)
+		}
+		if err := r.waitForNIMCaches(ctx, nimCaches...); err != nil {
+			return fmt.Errorf("NIMPipeline %s not created: %w", name, err)
+		}
+		_, err = nimClient.NIMPipelines(r.namespace).Create(ctx, obj, createOptions)
+	}
+
+	resource := strings.ToLower(kind) + "/" + name
+	switch {
+	case apierrors.IsAlreadyExists(err):
+		fmt.Fprintf(r.options.IoStreams.Out, "%s unchanged, already exists\n", resource)
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to create %s: %w", resource, err)
+	}
+	fmt.Fprintf(r.options.IoStreams.Out, "%s created\n", resource)
+	return nil
+}
+
+func decode(data []byte, obj metav1.Object, namespace string) error {
+	if err := yaml.Unmarshal(data, obj); err != nil {
+		return fmt.Errorf("invalid object: %w", err)
+	}
+	if obj.GetName() == "" {
+		return errors.New("invalid object: metadata.name is empty")
+	}
+	obj.SetNamespace(namespace)
+	return nil
+}
+
+// Waits for each named NIMCache to be Ready, when --wait is set. Empty names are skipped.
+func (r *restorer) waitForNIMCaches(ctx context.Context, names ...string) error {
+	if !r.options.Wait {
+		return nil
+	}
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		err, ok := r.waited[name]
+		if !ok {
+			err = r.waitForNIMCache(ctx, name)
+			r.waited[name] = err
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *restorer) waitForNIMCache(ctx context.Context, name string) error {
+	fmt.Fprintf(r.options.IoStreams.Out, "Waiting for NIMCache %s to be Ready\n", name)
+	nimCaches := r.k8sClient.NIMClient().AppsV1alpha1().NIMCaches(r.namespace)
+	err := wait.PollUntilContextTimeout(ctx, r.options.pollInterval, r.options.Timeout, true, func(ctx context.Context) (bool, error) {
+		nimCache, err := nimCaches.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, fmt.Errorf("unable to retrieve NIMCache %s: %w", name, err)
+		}
+		switch nimCache.Status.State {
+		case appsv1alpha1.NimCacheStatusReady:
+			return true, nil
+		case appsv1alpha1.NimCacheStatusFailed:
+			return false, fmt.Errorf("NIMCache %s failed", name)
+		}
+		return false, nil
+	})
+	if wait.Interrupted(err) {
+		return fmt.Errorf("timed out after %s waiting for NIMCache %s to be Ready", r.options.Timeout, name)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(r.options.IoStreams.Out, "NIMCache %s is Ready\n", name)
+	return nil
+}
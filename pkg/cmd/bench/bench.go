@@ -0,0 +1,324 @@
+package bench
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/rest"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	"k8s-nim-operator-cli/pkg/cmd/infer"
+	"k8s-nim-operator-cli/pkg/util"
+	"k8s-nim-operator-cli/pkg/util/client"
+	nimconfig "k8s-nim-operator-cli/pkg/util/config"
+	"k8s-nim-operator-cli/pkg/util/openai"
+)
+
+// Prompt used when no --prompt-file is given.
+const defaultPrompt = "Explain in a few paragraphs how GPUs accelerate the inference of large language models."
+
+type BenchOptions struct {
+	*infer.InferenceOptions
+	Concurrency int
+	Requests    int
+	PromptFile  string
+	Compare     string
+	Output      string
+}
+
+func NewBenchCommand(cmdFactory cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	options := &BenchOptions{InferenceOptions: infer.NewInferenceOptions(cmdFactory, streams)}
+
+	cmd := &cobra.Command{
+		Use:   "bench RESOURCE NAME",
+		Short: "Run a load test against a NIMService",
+		Long: `Send streamed chat completions to a NIMService from a fixed number of concurrent workers and report
+time-to-first-token, inter-token latency, end-to-end latency (mean, p50, p90, p99), throughput and error rate.
+
+Prompts are read from --prompt-file, one per line, and reused round-robin until --requests have been sent.
+With --compare, the same workload is run against a second NIMService in the same namespace after the first one
+finishes, and the results are shown side by side. Endpoint and model resolution is the same as for "nim infer".`,
+		Example: `  nim bench nimservice llama3-nimservice --concurrency=8 --requests=200 --prompt-file=prompts.txt
+  nim bench nimservice llama3-fp8 --compare=llama3-bf16 --max-tokens=256 -o json`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch len(args) {
+			case 0:
+				// Show help if no args provided.
+				cmd.HelpFunc()(cmd, args)
+			case 2:
+				if err := options.CompleteNamespace(args, cmd); err != nil {
+					return err
+				}
+				if options.ResourceType != util.NIMService {
+					return fmt.Errorf("bench only supports nimservice, got %q", args[0])
+				}
+				if err := options.Validate(); err != nil {
+					return err
+				}
+				k8sClient, err := client.NewClient(cmdFactory)
+				if err != nil {
+					return fmt.Errorf("failed to create client: %w", err)
+				}
+				restConfig, err := cmdFactory.ToRESTConfig()
+				if err != nil {
+					return fmt.Errorf("failed to get REST config: %w", err)
+				}
+				ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+				defer stop()
+				return Run(ctx, options, k8sClient, restConfig)
+			default:
+				fmt.Println(fmt.Errorf("unknown command(s) %q", strings.Join(args, " ")))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&options.Concurrency, "concurrency", 1, "Number of requests in flight at the same time.")
+	cmd.Flags().IntVar(&options.Requests, "requests", 10, "Total number of requests to send to each NIMService.")
+	cmd.Flags().StringVar(&options.PromptFile, "prompt-file", "", "File with one prompt per line. A built-in prompt is used if not set.")
+	cmd.Flags().StringVar(&options.Compare, "compare", "", "Name of a second NIMService in the same namespace to run the same workload against.")
+	cmd.Flags().StringVarP(&options.Output, "output", "o", "table", "Output format. One of: table, json.")
+	infer.AddInferenceFlags(cmd, options.InferenceOptions)
+	cmd.SetHelpTemplate(helpTemplate)
+
+	nimconfig.BindOutput(cmd.Flags(), "table", "json")
+
+	return cmd
+}
+
+func (options *BenchOptions) Validate() error {
+	if options.Concurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+	if options.Requests < 1 {
+		return fmt.Errorf("--requests must be at least 1")
+	}
+	if options.Output != "table" && options.Output != "json" {
+		return fmt.Errorf("unsupported output format %q. Valid formats are: table, json", options.Output)
+	}
+	if options.Compare != "" && options.Endpoint != "" {
+		return fmt.Errorf("--endpoint cannot be combined with --compare")
+	}
+	return nil
+}
+
+func Run(ctx context.Context, options *BenchOptions, k8sClient client.Client, restConfig *rest.Config) error {
+	prompts, err := loadPrompts(options.PromptFile)
+	if err != nil {
+		return err
+	}
+
+	targets := []*infer.InferenceOptions{options.InferenceOptions}
+	if options.Compare != "" {
+		targets = append(targets, withResourceName(options.InferenceOptions, options.Compare))
+	}
+
+	var results []Result
+	for _, target := range targets {
+		fmt.Fprintf(options.IoStreams.ErrOut, "Benchmarking NIMService %s/%s: %d requests, concurrency %d...\n",
+			target.Namespace, target.ResourceName, options.Requests, options.Concurrency)
+		result, err := benchmark(ctx, options, target, prompts, k8sClient, restConfig)
+		if err != nil {
+			return err
+		}
+		results = append(results, *result)
+	}
+
+	if options.Output == "json" {
+		encoder := json.NewEncoder(options.IoStreams.Out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(results)
+	}
+	return printResults(results, options.IoStreams.Out)
+}
+
+// Returns a copy of options that targets the NIMService with the given name.
+func withResourceName(options *infer.InferenceOptions, name string) *infer.InferenceOptions {
+	fetch := *options.FetchResourceOptions
+	fetch.ResourceName = name
+	target := *options
+	target.FetchResourceOptions = &fetch
+	return &target
+}
+
+// Reads one prompt per non-empty line of path, or returns the default prompt if path is empty.
+func loadPrompts(path string) ([]string, error) {
+	if path == "" {
+		return []string{defaultPrompt}, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open prompt file: %w", err)
+	}
+	defer f.Close()
+
+	var prompts []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			prompts = append(prompts, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read prompt file: %w", err)
+	}
+	if len(prompts) == 0 {
+		return nil, fmt.Errorf("prompt file %s does not contain any prompts", path)
+	}
+	return prompts, nil
+}
+
+// Runs the workload against one NIMService and aggregates the results.
+func benchmark(ctx context.Context, options *BenchOptions, target *infer.InferenceOptions, prompts []string, k8sClient client.Client, restConfig *rest.Config) (*Result, error) {
+	c, model, stop, err := infer.Connect(ctx, target, k8sClient, restConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer stop()
+
+	// Workers pull request indexes from a channel so exactly options.Requests requests are sent.
+	jobs := make(chan int)
+	results := make([]requestResult, options.Requests)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for w := 0; w < options.Concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				messages := infer.InitialMessages(target)
+				messages = append(messages, openai.ChatMessage{Role: "user", Content: prompts[i%len(prompts)]})
+				results[i] = sendRequest(ctx, c, infer.NewChatRequest(target, model, messages))
+			}
+		}()
+	}
+	sent := 0
+	for ; sent < options.Requests && ctx.Err() == nil; sent++ {
+		jobs <- sent
+	}
+	close(jobs)
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("benchmark interrupted after %d of %d requests", sent, options.Requests)
+	}
+
+	result := summarize(results, elapsed)
+	result.NIMService = target.ResourceName
+	result.Namespace = target.Namespace
+	result.Model = model
+	result.Concurrency = options.Concurrency
+	return &result, nil
+}
+
+// Sends a single streamed request and records when each content delta arrived.
+func sendRequest(ctx context.Context, c *openai.Client, request openai.ChatCompletionRequest) requestResult {
+	var result requestResult
+	start := time.Now()
+	last := start
+	completion, err := c.StreamChatCompletion(ctx, request, func(string) error {
+		now := time.Now()
+		if result.TTFT == 0 {
+			result.TTFT = now.Sub(start)
+		} else {
+			result.InterTokens = append(result.InterTokens, now.Sub(last))
+		}
+		last = now
+		return nil
+	})
+	result.Latency = time.Since(start)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	// Servers may batch several tokens into one event; prefer the reported usage when available.
+	result.OutputTokens = completion.Chunks
+	if completion.Usage != nil && completion.Usage.CompletionTokens > 0 {
+		result.OutputTokens = completion.Usage.CompletionTokens
+	}
+	return result
+}
+
+func printResults(results []Result, output io.Writer) error {
+	resultTablePrinter := printers.NewTablePrinter(printers.PrintOptions{})
+
+	resTable := &v1.Table{
+		ColumnDefinitions: []v1.TableColumnDefinition{{Name: "Metric", Type: "string"}},
+	}
+	for _, result := range results {
+		resTable.ColumnDefinitions = append(resTable.ColumnDefinitions, v1.TableColumnDefinition{Name: result.NIMService, Type: "string"})
+	}
+
+	rows := []struct {
+		name  string
+		value func(Result) string
+	}{
+		{"Model", func(r Result) string { return r.Model }},
+		{"Requests", func(r Result) string { return fmt.Sprintf("%d", r.Requests) }},
+		{"Errors", func(r Result) string { return fmt.Sprintf("%d (%.1f%%)", r.Errors, r.ErrorRate*100) }},
+		{"Duration", func(r Result) string { return fmt.Sprintf("%.2fs", r.DurationSeconds) }},
+		{"Requests/s", func(r Result) string { return fmt.Sprintf("%.2f", r.RequestsPerSecond) }},
+		{"Output tokens/s", func(r Result) string { return fmt.Sprintf("%.1f", r.TokensPerSecond) }},
+		{"TTFT mean/p50/p90/p99", func(r Result) string { return formatDistribution(r.TimeToFirstTokenMs) }},
+		{"ITL mean/p50/p90/p99", func(r Result) string { return formatDistribution(r.InterTokenMs) }},
+		{"Latency mean/p50/p90/p99", func(r Result) string { return formatDistribution(r.LatencyMs) }},
+	}
+	for _, row := range rows {
+		cells := []interface{}{row.name}
+		for _, result := range results {
+			cells = append(cells, row.value(result))
+		}
+		resTable.Rows = append(resTable.Rows, v1.TableRow{Cells: cells})
+	}
+
+	if err := resultTablePrinter.PrintObj(resTable, output); err != nil {
+		return err
+	}
+	for _, result := range results {
+		if result.FirstError != "" {
+			fmt.Fprintf(output, "\nFirst error from %s: %s\n", result.NIMService, result.FirstError)
+		}
+	}
+	return nil
+}
+
+func formatDistribution(d Distribution) string {
+	return fmt.Sprintf("%.1f/%.1f/%.1f/%.1f ms", d.Mean, d.P50, d.P90, d.P99)
+}
+
+// Custom help message template. Needed to show supported resource types as a custom category to be consistent with "Available Commands" for get and status.
+const helpTemplate = `{{- if .Long }}{{ .Long }}{{- else }}{{ .Short }}{{- end }}
+
+Usage:{{if .Runnable}}
+  {{.UseLine}}{{end}}
+
+Supported RESOURCE types:
+  nimservice   Run a load test against a NIMService.
+
+{{if .HasExample}}Examples:
+{{ .Example }}
+
+{{end}}{{if .HasAvailableLocalFlags}}Flags:
+{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}
+
+{{end}}{{if .HasAvailableInheritedFlags}}Global Flags:
+{{.InheritedFlags.FlagUsages | trimTrailingWhitespaces}}
+
+{{end}}`
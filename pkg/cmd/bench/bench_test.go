@@ -0,0 +1,152 @@
+package bench
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"k8s-nim-operator-cli/pkg/cmd/infer"
+	"k8s-nim-operator-cli/pkg/util/client/fake"
+)
+
+// newStreamServer streams three tokens per request and fails every failEvery-th request, if set.
+func newStreamServer(failEvery int32) (*httptest.Server, *int32) {
+	var count int32
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&count, 1)
+		if failEvery > 0 && n%failEvery == 0 {
+			http.Error(w, "overloaded", http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, token := range []string{"a", "b", "c"} {
+			fmt.Fprintf(w, "data: {\"choices\":[{\"delta\":{\"content\":%q}}]}\n\n", token)
+			w.(http.Flusher).Flush()
+			time.Sleep(time.Millisecond)
+		}
+		fmt.Fprint(w, "data: {\"choices\":[],\"usage\":{\"completion_tokens\":3}}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	})), &count
+}
+
+func newNIMService(name, endpoint string) *appsv1alpha1.NIMService {
+	nimservice := &appsv1alpha1.NIMService{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "nim"}}
+	nimservice.Status.Model = &appsv1alpha1.ModelStatus{Name: "model-" + name, ExternalEndpoint: endpoint}
+	return nimservice
+}
+
+func newTestOptions(out *bytes.Buffer) *BenchOptions {
+	options := &BenchOptions{
+		InferenceOptions: infer.NewInferenceOptions(nil, genericclioptions.IOStreams{Out: out, ErrOut: &bytes.Buffer{}}),
+		Concurrency:      3,
+		Requests:         10,
+		Output:           "json",
+	}
+	options.Namespace = "nim"
+	options.ResourceName = "fast"
+	options.MaxTokens = 16
+	options.Temperature = -1
+	return options
+}
+
+func Test_Run_Compare(t *testing.T) {
+	fast, fastCount := newStreamServer(0)
+	defer fast.Close()
+	flaky, flakyCount := newStreamServer(5)
+	defer flaky.Close()
+
+	k8sClient := fake.NewFakeClient(nil, []runtime.Object{newNIMService("fast", fast.URL), newNIMService("flaky", strings.TrimPrefix(flaky.URL, "http://"))})
+
+	promptFile := filepath.Join(t.TempDir(), "prompts.txt")
+	if err := os.WriteFile(promptFile, []byte("first\n\nsecond\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := &bytes.Buffer{}
+	options := newTestOptions(out)
+	options.PromptFile = promptFile
+	options.Compare = "flaky"
+
+	if err := Run(context.Background(), options, k8sClient, nil); err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	if *fastCount != 10 || *flakyCount != 10 {
+		t.Fatalf("expected 10 requests per NIMService, got %d and %d", *fastCount, *flakyCount)
+	}
+
+	var results []Result
+	if err := json.Unmarshal(out.Bytes(), &results); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out.String())
+	}
+	if len(results) != 2 || results[0].NIMService != "fast" || results[1].NIMService != "flaky" {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if r := results[0]; r.Model != "model-fast" || r.Errors != 0 || r.OutputTokens != 30 || r.TimeToFirstTokenMs.P50 <= 0 || r.InterTokenMs.P99 <= 0 {
+		t.Fatalf("unexpected result for fast: %+v", r)
+	}
+	if r := results[1]; r.Errors != 2 || r.ErrorRate != 0.2 || !strings.Contains(r.FirstError, "429") {
+		t.Fatalf("unexpected result for flaky: %+v", r)
+	}
+}
+
+func Test_Run_Table(t *testing.T) {
+	server, _ := newStreamServer(0)
+	defer server.Close()
+
+	k8sClient := fake.NewFakeClient(nil, []runtime.Object{newNIMService("fast", "")})
+	out := &bytes.Buffer{}
+	options := newTestOptions(out)
+	options.Output = "table"
+	options.Endpoint = server.URL
+
+	if err := Run(context.Background(), options, k8sClient, nil); err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	for _, want := range []string{"METRIC", "FAST", "model-fast", "Errors", "0 (0.0%)", "TTFT mean/p50/p90/p99", "Output tokens/s"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in output:\n%s", want, out.String())
+		}
+	}
+}
+
+func Test_Validate(t *testing.T) {
+	options := newTestOptions(&bytes.Buffer{})
+	options.Compare = "other"
+	options.Endpoint = "http://localhost:8000"
+	if err := options.Validate(); err == nil {
+		t.Fatalf("expected --endpoint with --compare to be rejected")
+	}
+	options = newTestOptions(&bytes.Buffer{})
+	options.Output = "yaml"
+	if err := options.Validate(); err == nil {
+		t.Fatalf("expected unsupported output to be rejected")
+	}
+}
+
+func Test_percentile(t *testing.T) {
+	var values []time.Duration
+	for i := 1; i <= 100; i++ {
+		values = append(values, time.Duration(i)*time.Millisecond)
+	}
+	d := distribution(values)
+	if d.P50 != 50 || d.P90 != 90 || d.P99 != 99 || d.Mean != 50.5 {
+		t.Fatalf("unexpected distribution: %+v", d)
+	}
+	if got := distribution(nil); got != (Distribution{}) {
+		t.Fatalf("expected zero distribution, got %+v", got)
+	}
+}
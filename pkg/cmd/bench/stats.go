@@ -0,0 +1,114 @@
+package bench
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// requestResult holds the timings of a single streamed chat completion.
+type requestResult struct {
+	Err     error
+	Latency time.Duration
+	// TTFT is the time to the first content delta; zero if none arrived.
+	TTFT time.Duration
+	// InterTokens are the gaps between consecutive content deltas.
+	InterTokens  []time.Duration
+	OutputTokens int
+}
+
+// Distribution summarizes a set of durations in milliseconds.
+type Distribution struct {
+	Mean float64 `json:"mean"`
+	P50  float64 `json:"p50"`
+	P90  float64 `json:"p90"`
+	P99  float64 `json:"p99"`
+}
+
+// Result is the aggregated outcome of a benchmark against one NIMService.
+type Result struct {
+	NIMService         string       `json:"nimservice"`
+	Namespace          string       `json:"namespace"`
+	Model              string       `json:"model"`
+	Concurrency        int          `json:"concurrency"`
+	Requests           int          `json:"requests"`
+	Errors             int          `json:"errors"`
+	ErrorRate          float64      `json:"errorRate"`
+	DurationSeconds    float64      `json:"durationSeconds"`
+	RequestsPerSecond  float64      `json:"requestsPerSecond"`
+	OutputTokens       int          `json:"outputTokens"`
+	TokensPerSecond    float64      `json:"tokensPerSecond"`
+	TimeToFirstTokenMs Distribution `json:"timeToFirstTokenMs"`
+	InterTokenMs       Distribution `json:"interTokenLatencyMs"`
+	LatencyMs          Distribution `json:"latencyMs"`
+	// FirstError is an example error message, to make failures actionable without a separate run.
+	FirstError string `json:"firstError,omitempty"`
+}
+
+// Aggregates per-request results measured over the given wall-clock duration. Failed requests only count towards
+// the error rate.
+func summarize(results []requestResult, elapsed time.Duration) Result {
+	summary := Result{Requests: len(results)}
+
+	var ttft, interTokens, latency []time.Duration
+	for _, r := range results {
+		if r.Err != nil {
+			summary.Errors++
+			if summary.FirstError == "" {
+				summary.FirstError = r.Err.Error()
+			}
+			continue
+		}
+		latency = append(latency, r.Latency)
+		if r.TTFT > 0 {
+			ttft = append(ttft, r.TTFT)
+		}
+		interTokens = append(interTokens, r.InterTokens...)
+		summary.OutputTokens += r.OutputTokens
+	}
+
+	if summary.Requests > 0 {
+		summary.ErrorRate = float64(summary.Errors) / float64(summary.Requests)
+	}
+	if seconds := elapsed.Seconds(); seconds > 0 {
+		summary.DurationSeconds = seconds
+		summary.RequestsPerSecond = float64(summary.Requests-summary.Errors) / seconds
+		summary.TokensPerSecond = float64(summary.OutputTokens) / seconds
+	}
+	summary.TimeToFirstTokenMs = distribution(ttft)
+	summary.InterTokenMs = distribution(interTokens)
+	summary.LatencyMs = distribution(latency)
+	return summary
+}
+
+func distribution(values []time.Duration) Distribution {
+	if len(values) == 0 {
+		return Distribution{}
+	}
+	sorted := append([]time.Duration(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, v := range sorted {
+		total += v
+	}
+	return Distribution{
+		Mean: milliseconds(total / time.Duration(len(sorted))),
+		P50:  milliseconds(percentile(sorted, 50)),
+		P90:  milliseconds(percentile(sorted, 90)),
+		P99:  milliseconds(percentile(sorted, 99)),
+	}
+}
+
+// Returns the nearest-rank percentile of an ascending, non-empty slice.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func milliseconds(d time.Duration) float64 {
+	return math.Round(float64(d.Microseconds())/10) / 100
+}
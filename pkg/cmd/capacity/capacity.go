@@ -0,0 +1,333 @@
+package capacity
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	"k8s-nim-operator-cli/pkg/util"
+	"k8s-nim-operator-cli/pkg/util/client"
+)
+
+const (
+	gpuResource = corev1.ResourceName("nvidia.com/gpu")
+
+	// Labels set by GPU feature discovery.
+	gpuPresentLabel = "nvidia.com/gpu.present"
+	gpuProductLabel = "nvidia.com/gpu.product"
+	gpuMemoryLabel  = "nvidia.com/gpu.memory"
+	migConfigLabel  = "nvidia.com/mig.config"
+	migCapableLabel = "nvidia.com/mig.capable"
+)
+
+type CapacityOptions struct {
+	*util.FetchResourceOptions
+	GPUs     int
+	Product  string
+	NIMCache string
+	Profile  string
+}
+
+func NewCapacityCommand(cmdFactory cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	options := &CapacityOptions{FetchResourceOptions: util.NewFetchResourceOptions(cmdFactory, streams)}
+
+	cmd := &cobra.Command{
+		Use:   "capacity",
+		Short: "Show GPU capacity per node and where a NIMService would fit",
+		Long: `List the GPU nodes with their GPU product, memory and MIG configuration as labelled by GPU feature discovery, their
+allocatable and allocated nvidia.com/gpu, and the NIMService and NIMCache pods running on them.
+
+With --gpus and/or --product, or with --nimcache and --profile, also report on which nodes a NIMService with that
+nvidia.com/gpu limit can be scheduled right now. A profile's GPU tag gives the product and its tensor parallelism the
+GPU count; --gpus and --product override them. A NIMCache holding a single profile implies it. The product matches the
+node's nvidia.com/gpu.product label case-insensitively on substrings, so h100 matches NVIDIA-H100-80GB-HBM3.
+
+Nodes that are not ready or are cordoned do not fit. Taints are reported, since the NIMService needs tolerations for
+them. The command exits with an error when no node fits.`,
+		Example: `  nim capacity
+  nim capacity --gpus 2 --product h100
+  nim capacity --nimcache llama3-nimcache --profile <profile-id> -n nim`,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := options.CompleteNamespace(args, cmd); err != nil {
+				return err
+			}
+			if options.Profile != "" && options.NIMCache == "" {
+				return fmt.Errorf("--profile requires --nimcache")
+			}
+			if cmd.Flags().Changed("gpus") && options.GPUs < 1 {
+				return fmt.Errorf("--gpus must be at least 1, got %d", options.GPUs)
+			}
+			k8sClient, err := client.NewClient(cmdFactory)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			return Run(cmd.Context(), options, k8sClient)
+		},
+	}
+
+	cmd.Flags().IntVar(&options.GPUs, "gpus", 0, "GPU limit of the NIMService to place, in nvidia.com/gpu.")
+	cmd.Flags().StringVar(&options.Product, "product", "", "GPU product the NIMService needs, e.g. h100. Matches nvidia.com/gpu.product on substrings.")
+	cmd.Flags().StringVar(&options.NIMCache, "nimcache", "", "NIMCache whose profile gives the GPU product and count, in the namespace given by -n.")
+	cmd.Flags().StringVar(&options.Profile, "profile", "", "ID of the cached profile to place. Optional when the NIMCache caches a single profile.")
+
+	return cmd
+}
+
+// A GPU node and what runs on it.
+type gpuNode struct {
+	name        string
+	product     string
+	memory      string
+	mig         string
+	status      string
+	schedulable bool
+	allocatable int64
+	allocated   int64
+	nimPods     []string
+	taints      []corev1.Taint
+}
+
+func (n *gpuNode) free() int64 {
+	return max(n.allocatable-n.allocated, 0)
+}
+
+func Run(ctx context.Context, options *CapacityOptions, k8sClient client.Client) error {
+	req, err := resolveRequirement(ctx, options, k8sClient)
+	if err != nil {
+		return err
+	}
+
+	nodes, err := listGPUNodes(ctx, k8sClient, options.IoStreams.ErrOut)
+	if err != nil {
+		return err
+	}
+	if len(nodes) == 0 {
+		return fmt.Errorf("no GPU nodes found: no node is labelled %s=true or advertises %s", gpuPresentLabel, gpuResource)
+	}
+
+	out := options.IoStreams.Out
+	if err := printNodes(nodes, out); err != nil {
+		return err
+	}
+	if req == nil {
+		return nil
+	}
+
+	fmt.Fprintf(out, "\nPlacement of a NIMService with %s:\n", req)
+	placements := place(req, nodes)
+	if err := printPlacements(placements, out); err != nil {
+		return err
+	}
+	var fits []string
+	for _, p := range placements {
+		if p.fits {
+			fits = append(fits, p.node.name)
+		}
+	}
+	if len(fits) == 0 {
+		return fmt.Errorf("a NIMService with %s cannot be scheduled right now", req)
+	}
+	fmt.Fprintf(out, "\nSchedulable now on %d node(s): %s\n", len(fits), strings.Join(fits, ", "))
+	return nil
+}
+
+// Lists the nodes labelled as GPU nodes or advertising nvidia.com/gpu, with the GPUs requested by the pods bound to
+// them and the NIM pods among those.
+func listGPUNodes(ctx context.Context, k8sClient client.Client, warnings io.Writer) ([]*gpuNode, error) {
+	kube := k8sClient.KubernetesClient()
+	nodeList, err := kube.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	nodes := map[string]*gpuNode{}
+	var names []string
+	for i := range nodeList.Items {
+		node := &nodeList.Items[i]
+		capacity := node.Status.Capacity[gpuResource]
+		if node.Labels[gpuPresentLabel] != "true" && node.Labels[gpuProductLabel] == "" && capacity.Value() == 0 {
+			continue
+		}
+		allocatable := node.Status.Allocatable[gpuResource]
+		status, schedulable := nodeStatus(node)
+		nodes[node.Name] = &gpuNode{
+			name:        node.Name,
+			product:     node.Labels[gpuProductLabel],
+			memory:      formatMemory(node.Labels[gpuMemoryLabel]),
+			mig:         migMode(node.Labels),
+			status:      status,
+			schedulable: schedulable,
+			allocatable: allocatable.Value(),
+			taints:      node.Spec.Taints,
+		}
+		names = append(names, node.Name)
+	}
+	if len(nodes) == 0 {
+		return nil, nil
+	}
+
+	podList, err := kube.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	isNIMPod := nimPodMatcher(ctx, k8sClient, warnings)
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		node := nodes[pod.Spec.NodeName]
+		if node == nil || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		node.allocated += podGPUs(pod)
+		if isNIMPod(pod) {
+			node.nimPods = append(node.nimPods, pod.Namespace+"/"+pod.Name)
+		}
+	}
+
+	sort.Strings(names)
+	sorted := make([]*gpuNode, 0, len(names))
+	for _, name := range names {
+		sort.Strings(nodes[name].nimPods)
+		sorted = append(sorted, nodes[name])
+	}
+	return sorted, nil
+}
+
+// Returns a function telling whether a pod belongs to a NIMService or NIMCache of any namespace. Pods are matched by
+// the labels util.PodOwner reads, and for multi-node NIMServices by the LeaderWorkerSet label. If the NIM resources cannot be
+// listed, a warning is printed and no pod matches.
+func nimPodMatcher(ctx context.Context, k8sClient client.Client, warnings io.Writer) func(*corev1.Pod) bool {
+	owners := map[string]bool{}
+	lwsNames := map[string]bool{}
+	nimClient := k8sClient.NIMClient().AppsV1alpha1()
+
+	nimServices, err := nimClient.NIMServices("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		fmt.Fprintf(warnings, "Warning: unable to list NIMServices, their pods are not shown: %v\n", err)
+	} else {
+		for i := range nimServices.Items {
+			nimService := &nimServices.Items[i]
+			owners[ownerKey(util.NIMService, nimService.Namespace, nimService.Name)] = true
+			if util.IsMultiNode(nimService) {
+				lwsNames[nimService.Namespace+"/"+nimService.GetLWSName()] = true
+			}
+		}
+	}
+	nimCaches, err := nimClient.NIMCaches("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		fmt.Fprintf(warnings, "Warning: unable to list NIMCaches, their pods are not shown: %v\n", err)
+	} else {
+		for i := range nimCaches.Items {
+			owners[ownerKey(util.NIMCache, nimCaches.Items[i].Namespace, nimCaches.Items[i].Name)] = true
+		}
+	}
+
+	return func(pod *corev1.Pod) bool {
+		if lws := pod.Labels[util.LWSNameLabel]; lws != "" && lwsNames[pod.Namespace+"/"+lws] {
+			return true
+		}
+		resourceType, name := util.PodOwner(pod)
+		return name != "" && owners[ownerKey(resourceType, pod.Namespace, name)]
+	}
+}
+
+func ownerKey(resourceType util.ResourceType, namespace, name string) string {
+	return string(resourceType) + "/" + namespace + "/" + name
+}
+
+// The nvidia.com/gpu the scheduler accounts to the pod: the larger of the sum over its containers and the largest init
+// container. Extended resources need limits, so requests are only a fallback.
+func podGPUs(pod *corev1.Pod) int64 {
+	var containers, initContainers int64
+	for _, container := range pod.Spec.Containers {
+		containers += containerGPUs(container)
+	}
+	for _, container := range pod.Spec.InitContainers {
+		initContainers = max(initContainers, containerGPUs(container))
+	}
+	return max(containers, initContainers)
+}
+
+func containerGPUs(container corev1.Container) int64 {
+	gpus, ok := container.Resources.Limits[gpuResource]
+	if !ok {
+		gpus = container.Resources.Requests[gpuResource]
+	}
+	return gpus.Value()
+}
+
+// Like kubectl get nodes: Ready or NotReady, with SchedulingDisabled for cordoned nodes.
+func nodeStatus(node *corev1.Node) (string, bool) {
+	status := "NotReady"
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue {
+			status = "Ready"
+		}
+	}
+	schedulable := status == "Ready" && !node.Spec.Unschedulable
+	if node.Spec.Unschedulable {
+		status += ",SchedulingDisabled"
+	}
+	return status, schedulable
+}
+
+// GPU feature discovery labels the memory of each GPU in MiB.
+func formatMemory(mib string) string {
+	if mib == "" {
+		return "<none>"
+	}
+	value, err := strconv.ParseInt(mib, 10, 64)
+	if err != nil {
+		return mib
+	}
+	return fmt.Sprintf("%dGi", (value+512)/1024)
+}
+
+// The MIG configuration applied by the MIG manager, or whether the GPUs support MIG at all.
+func migMode(labels map[string]string) string {
+	if config := labels[migConfigLabel]; config != "" {
+		return config
+	}
+	if labels[migCapableLabel] == "true" {
+		return "capable"
+	}
+	return "<none>"
+}
+
+func printNodes(nodes []*gpuNode, output io.Writer) error {
+	table := &metav1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Node", Type: "string"},
+			{Name: "Status", Type: "string"},
+			{Name: "Product", Type: "string"},
+			{Name: "GPU Memory", Type: "string"},
+			{Name: "MIG", Type: "string"},
+			{Name: "Allocatable", Type: "string"},
+			{Name: "Allocated", Type: "string"},
+			{Name: "Free", Type: "string"},
+			{Name: "NIM Pods", Type: "string"},
+		},
+	}
+	for _, node := range nodes {
+		nimPods := "<none>"
+		if len(node.nimPods) > 0 {
+			nimPods = strings.Join(node.nimPods, ", ")
+		}
+		table.Rows = append(table.Rows, metav1.TableRow{Cells: []interface{}{
+			node.name, node.status, util.OrNone(node.product), node.memory, node.mig,
+			fmt.Sprintf("%d", node.allocatable), fmt.Sprintf("%d", node.allocated), fmt.Sprintf("%d", node.free()),
+			nimPods,
+		}})
+	}
+	return printers.NewTablePrinter(printers.PrintOptions{}).PrintObj(table, output)
+}
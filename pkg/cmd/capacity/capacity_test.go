@@ -0,0 +1,207 @@
+package capacity
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"k8s-nim-operator-cli/pkg/util"
+	"k8s-nim-operator-cli/pkg/util/client/fake"
+)
+
+func gpuNodeObject(name, product, memory string, gpus int64) *corev1.Node {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{gpuPresentLabel: "true", gpuProductLabel: product}},
+		Status: corev1.NodeStatus{
+			Capacity:    corev1.ResourceList{gpuResource: *resource.NewQuantity(gpus, resource.DecimalSI)},
+			Allocatable: corev1.ResourceList{gpuResource: *resource.NewQuantity(gpus, resource.DecimalSI)},
+			Conditions:  []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	if memory != "" {
+		node.Labels[gpuMemoryLabel] = memory
+	}
+	return node
+}
+
+func podObject(namespace, name, instance, node string, gpus int64, phase corev1.PodPhase) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{}},
+		Spec:       corev1.PodSpec{NodeName: node, Containers: []corev1.Container{{Name: "main"}}},
+		Status:     corev1.PodStatus{Phase: phase},
+	}
+	if instance != "" {
+		pod.Labels[util.InstanceLabel] = instance
+	}
+	if gpus > 0 {
+		pod.Spec.Containers[0].Resources.Limits = corev1.ResourceList{gpuResource: *resource.NewQuantity(gpus, resource.DecimalSI)}
+	}
+	return pod
+}
+
+func newTestClient() *fake.Client {
+	tainted := gpuNodeObject("gpu-a", "NVIDIA-H100-80GB-HBM3", "81559", 8)
+	tainted.Labels[migConfigLabel] = "all-disabled"
+	tainted.Spec.Taints = []corev1.Taint{{Key: "nvidia.com/gpu", Effect: corev1.TaintEffectNoSchedule}}
+	cordoned := gpuNodeObject("gpu-b", "NVIDIA-H100-80GB-HBM3", "81559", 8)
+	cordoned.Spec.Unschedulable = true
+	l40s := gpuNodeObject("gpu-c", "NVIDIA-L40S", "46068", 4)
+	l40s.Labels[migCapableLabel] = "false"
+	cpu := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "cpu-1"}}
+
+	objects := []runtime.Object{
+		tainted, cordoned, l40s, cpu,
+		podObject("nim", "llama3-0", "llama3", "gpu-a", 4, corev1.PodRunning),
+		podObject("ml", "training-0", "", "gpu-a", 2, corev1.PodRunning),
+		podObject("nim", "llama3-cache-job", "llama3-cache", "gpu-c", 1, corev1.PodSucceeded),
+		podObject("nim", "mistral-0", "mistral", "gpu-c", 1, corev1.PodPending),
+		podObject("nim", "web-0", "web", "cpu-1", 0, corev1.PodRunning),
+	}
+
+	nimcache := &appsv1alpha1.NIMCache{ObjectMeta: metav1.ObjectMeta{Name: "llama3-cache", Namespace: "nim"}}
+	nimcache.Status.Profiles = []appsv1alpha1.NIMProfile{
+		{Name: "trt-fp8-tp2-h100", Config: map[string]string{"engine": "tensorrt_llm", "precision": "fp8", "tp": "2", "gpu": "H100"}},
+		{Name: "trt-fp8-tp1-l40s", Config: map[string]string{"engine": "tensorrt_llm", "precision": "fp8", "tp": "1", "gpu": "L40S"}},
+	}
+	return fake.NewFakeClient(objects, []runtime.Object{
+		nimcache,
+		&appsv1alpha1.NIMService{ObjectMeta: metav1.ObjectMeta{Name: "llama3", Namespace: "nim"}},
+		&appsv1alpha1.NIMService{ObjectMeta: metav1.ObjectMeta{Name: "mistral", Namespace: "nim"}},
+	})
+}
+
+func newTestOptions(out, errOut *bytes.Buffer) *CapacityOptions {
+	streams := genericclioptions.IOStreams{In: &bytes.Buffer{}, Out: out, ErrOut: errOut}
+	options := &CapacityOptions{FetchResourceOptions: util.NewFetchResourceOptions(nil, streams)}
+	options.Namespace = "nim"
+	return options
+}
+
+func TestRunListsGPUNodes(t *testing.T) {
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	if err := Run(context.Background(), newTestOptions(out, errOut), newTestClient()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	want := `NODE    STATUS                     PRODUCT                 GPU MEMORY   MIG            ALLOCATABLE   ALLOCATED   FREE   NIM PODS
+gpu-a   Ready                      NVIDIA-H100-80GB-HBM3   80Gi         all-disabled   8             6           2      nim/llama3-0
+gpu-b   Ready,SchedulingDisabled   NVIDIA-H100-80GB-HBM3   80Gi         <none>         8             0           8      <none>
+gpu-c   Ready                      NVIDIA-L40S             45Gi         <none>         4             1           3      nim/mistral-0
+`
+	if out.String() != want {
+		t.Errorf("output:\n%s\nwant:\n%s", out.String(), want)
+	}
+	if errOut.Len() != 0 {
+		t.Errorf("unexpected warnings: %s", errOut.String())
+	}
+}
+
+func TestRunListsNIMCachePods(t *testing.T) {
+	// The operator labels caching job pods with the NIMCache's name only.
+	job := podObject("nim", "llama3-cache-job-x", "", "gpu-c", 1, corev1.PodRunning)
+	job.Labels[util.NameLabel] = "llama3-cache"
+	other := podObject("nim", "llama3-cache-web", "", "gpu-c", 0, corev1.PodRunning)
+	other.Labels[util.NameLabel] = "unrelated"
+	k8sClient := fake.NewFakeClient(
+		[]runtime.Object{gpuNodeObject("gpu-c", "NVIDIA-L40S", "46068", 4), job, other},
+		[]runtime.Object{&appsv1alpha1.NIMCache{ObjectMeta: metav1.ObjectMeta{Name: "llama3-cache", Namespace: "nim"}}},
+	)
+
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	if err := Run(context.Background(), newTestOptions(out, errOut), k8sClient); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !strings.Contains(out.String(), "nim/llama3-cache-job-x\n") {
+		t.Errorf("expected the caching pod under NIM pods, got:\n%s", out.String())
+	}
+}
+
+func TestRunPlacement(t *testing.T) {
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	options := newTestOptions(out, errOut)
+	options.GPUs = 2
+	options.Product = "h100"
+
+	if err := Run(context.Background(), options, newTestClient()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	want := `Placement of a NIMService with 2 nvidia.com/gpu of product h100:
+NODE    FREE   FITS   REASON
+gpu-a   2      yes    needs tolerations for nvidia.com/gpu:NoSchedule
+gpu-b   8      no     node is Ready,SchedulingDisabled
+gpu-c   3      no     product NVIDIA-L40S
+
+Schedulable now on 1 node(s): gpu-a
+`
+	if !strings.HasSuffix(out.String(), want) {
+		t.Errorf("output:\n%s\nwant suffix:\n%s", out.String(), want)
+	}
+}
+
+func TestRunPlacementDoesNotFit(t *testing.T) {
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	options := newTestOptions(out, errOut)
+	options.GPUs = 4
+	options.Product = "h100"
+
+	err := Run(context.Background(), options, newTestClient())
+	if err == nil || err.Error() != "a NIMService with 4 nvidia.com/gpu of product h100 cannot be scheduled right now" {
+		t.Fatalf("expected a cannot be scheduled error, got %v", err)
+	}
+	if !strings.Contains(out.String(), "gpu-a   2      no     2 of 8 GPU(s) free") {
+		t.Errorf("expected gpu-a to lack free GPUs, got:\n%s", out.String())
+	}
+}
+
+func TestRunPlacementFromProfile(t *testing.T) {
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	options := newTestOptions(out, errOut)
+	options.NIMCache = "llama3-cache"
+
+	err := Run(context.Background(), options, newTestClient())
+	if err == nil || !strings.Contains(err.Error(), "caches 2 profiles, choose one with --profile: trt-fp8-tp2-h100, trt-fp8-tp1-l40s") {
+		t.Fatalf("expected a profile choice error, got %v", err)
+	}
+
+	out.Reset()
+	options.Profile = "trt-fp8-tp1-l40s"
+	if err := Run(context.Background(), options, newTestClient()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	for _, line := range []string{
+		"Placement of a NIMService with 1 nvidia.com/gpu of product L40S (profile trt-fp8-tp1-l40s):",
+		"Schedulable now on 1 node(s): gpu-c",
+	} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("expected %q in output:\n%s", line, out.String())
+		}
+	}
+
+	options.Profile = "missing"
+	if err := Run(context.Background(), options, newTestClient()); err == nil || !strings.Contains(err.Error(), `profile "missing" is not cached`) {
+		t.Errorf("expected a not cached error, got %v", err)
+	}
+}
+
+func TestProductMatches(t *testing.T) {
+	for _, tc := range []struct {
+		label, product string
+		want           bool
+	}{
+		{"NVIDIA-H100-80GB-HBM3", "h100", true},
+		{"NVIDIA-H100-NVL", "H100_NVL", true},
+		{"NVIDIA-A100-SXM4-80GB", "h100", false},
+		{"", "l40s", false},
+	} {
+		if got := productMatches(tc.label, tc.product); got != tc.want {
+			t.Errorf("productMatches(%q, %q) = %v, want %v", tc.label, tc.product, got, tc.want)
+		}
+	}
+}
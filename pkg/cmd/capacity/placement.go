@@ -0,0 +1,172 @@
+package capacity
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/printers"
+
+	"k8s-nim-operator-cli/pkg/util"
+	"k8s-nim-operator-cli/pkg/util/client"
+)
+
+// The GPUs a NIMService to place asks for.
+type requirement struct {
+	gpus    int64
+	product string
+	profile string
+}
+
+func (r *requirement) String() string {
+	s := fmt.Sprintf("%d %s", r.gpus, gpuResource)
+	if r.product != "" {
+		s += fmt.Sprintf(" of product %s", r.product)
+	}
+	if r.profile != "" {
+		s += fmt.Sprintf(" (profile %s)", r.profile)
+	}
+	return s
+}
+
+// Builds the requirement from the flags and the NIMCache profile, or returns nil when none were given.
+func resolveRequirement(ctx context.Context, options *CapacityOptions, k8sClient client.Client) (*requirement, error) {
+	if options.GPUs == 0 && options.Product == "" && options.NIMCache == "" {
+		return nil, nil
+	}
+	req := &requirement{}
+	if options.NIMCache != "" {
+		profile, err := fetchProfile(ctx, options, k8sClient)
+		if err != nil {
+			return nil, err
+		}
+		info := util.NewProfileInfo(*profile)
+		req.profile = profile.Name
+		req.product = info.GPU
+		if info.TP != "" {
+			tp, err := strconv.ParseInt(info.TP, 10, 64)
+			if err != nil || tp < 1 {
+				return nil, fmt.Errorf("profile %s has an invalid tensor parallelism %q; set --gpus", profile.Name, info.TP)
+			}
+			req.gpus = tp
+		}
+	}
+	if options.GPUs > 0 {
+		req.gpus = int64(options.GPUs)
+	}
+	if options.Product != "" {
+		req.product = options.Product
+	}
+	if req.gpus == 0 {
+		req.gpus = 1
+	}
+	return req, nil
+}
+
+// Returns the profile named by --profile, or the only profile the NIMCache caches.
+func fetchProfile(ctx context.Context, options *CapacityOptions, k8sClient client.Client) (*appsv1alpha1.NIMProfile, error) {
+	fetchOptions := *options.FetchResourceOptions
+	fetchOptions.ResourceName = options.NIMCache
+	nimcache, err := util.FetchNIMCache(ctx, &fetchOptions, k8sClient)
+	if err != nil {
+		return nil, err
+	}
+	profiles := nimcache.Status.Profiles
+	if len(profiles) == 0 {
+		return nil, fmt.Errorf("NIMCache %s/%s does not report any cached profiles yet (state: %s)", nimcache.Namespace, nimcache.Name, nimcache.Status.State)
+	}
+	ids := make([]string, 0, len(profiles))
+	for i := range profiles {
+		ids = append(ids, profiles[i].Name)
+		if profiles[i].Name == options.Profile {
+			return &profiles[i], nil
+		}
+	}
+	if options.Profile != "" {
+		return nil, fmt.Errorf("profile %q is not cached by NIMCache %s/%s; cached profiles: %s", options.Profile, nimcache.Namespace, nimcache.Name, strings.Join(ids, ", "))
+	}
+	if len(profiles) > 1 {
+		return nil, fmt.Errorf("NIMCache %s/%s caches %d profiles, choose one with --profile: %s", nimcache.Namespace, nimcache.Name, len(profiles), strings.Join(ids, ", "))
+	}
+	return &profiles[0], nil
+}
+
+// Whether the NIMService fits on a node, and why not or under which condition.
+type placement struct {
+	node   *gpuNode
+	fits   bool
+	reason string
+}
+
+// Checks each node against the requirement the way the scheduler would for the GPU limit: the product, the node's
+// readiness and the free nvidia.com/gpu. Taints do not rule a node out, but the NIMService needs tolerations for them.
+func place(req *requirement, nodes []*gpuNode) []placement {
+	placements := make([]placement, 0, len(nodes))
+	for _, node := range nodes {
+		p := placement{node: node}
+		switch {
+		case req.product != "" && !productMatches(node.product, req.product):
+			p.reason = fmt.Sprintf("product %s", util.OrNone(node.product))
+		case !node.schedulable:
+			p.reason = fmt.Sprintf("node is %s", node.status)
+		case node.free() < req.gpus:
+			p.reason = fmt.Sprintf("%d of %d GPU(s) free", node.free(), node.allocatable)
+		default:
+			p.fits = true
+			if taints := scheduleTaints(node.taints); len(taints) > 0 {
+				p.reason = "needs tolerations for " + strings.Join(taints, ", ")
+			}
+		}
+		placements = append(placements, p)
+	}
+	return placements
+}
+
+// Compares products case-insensitively on substrings, treating "_" and " " like "-", so the h100_nvl tag of a profile
+// matches the NVIDIA-H100-NVL label.
+func productMatches(label, product string) bool {
+	normalize := strings.NewReplacer("_", "-", " ", "-")
+	return strings.Contains(normalize.Replace(strings.ToLower(label)), normalize.Replace(strings.ToLower(product)))
+}
+
+// The taints that keep pods without a matching toleration off the node, as KEY[=VALUE]:EFFECT.
+func scheduleTaints(taints []corev1.Taint) []string {
+	var result []string
+	for _, taint := range taints {
+		if taint.Effect != corev1.TaintEffectNoSchedule && taint.Effect != corev1.TaintEffectNoExecute {
+			continue
+		}
+		s := taint.Key
+		if taint.Value != "" {
+			s += "=" + taint.Value
+		}
+		result = append(result, s+":"+string(taint.Effect))
+	}
+	return result
+}
+
+func printPlacements(placements []placement, output io.Writer) error {
+	table := &metav1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Node", Type: "string"},
+			{Name: "Free", Type: "string"},
+			{Name: "Fits", Type: "string"},
+			{Name: "Reason", Type: "string"},
+		},
+	}
+	for _, p := range placements {
+		fits := "no"
+		if p.fits {
+			fits = "yes"
+		}
+		table.Rows = append(table.Rows, metav1.TableRow{Cells: []interface{}{
+			p.node.name, fmt.Sprintf("%d", p.node.free()), fits, util.OrNone(p.reason),
+		}})
+	}
+	return printers.NewTablePrinter(printers.PrintOptions{}).PrintObj(table, output)
+}
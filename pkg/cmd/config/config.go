@@ -0,0 +1,246 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+
+	"k8s-nim-operator-cli/pkg/util"
+	nimconfig "k8s-nim-operator-cli/pkg/util/config"
+)
+
+type ConfigOptions struct {
+	IoStreams *genericclioptions.IOStreams
+	Loader    *nimconfig.Loader
+	// Kube context and namespace the configuration is resolved for.
+	Context   string
+	Namespace string
+
+	// Scope written by set.
+	Project     bool
+	InContext   string
+	InNamespace string
+}
+
+func NewConfigCommand(configFlags *genericclioptions.ConfigFlags, streams genericclioptions.IOStreams) *cobra.Command {
+	options := &ConfigOptions{IoStreams: &streams}
+
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "View and set the defaults of nim flags",
+		Long: `View and set the defaults of nim flags, per user, project, kube context or namespace.
+
+Defaults are read, lowest precedence first, from the built-in defaults, the user config
+($XDG_CONFIG_HOME or ~/.config)/kubectl-nim/config.yaml, the nearest .nim.yaml at or above the working directory, and
+NIM_* environment variables. Flags set on the command line always win. Within a file, settings for the current kube
+context override the file's defaults, and settings for the current namespace override both:
+
+  authSecret: ngc-api-secret
+  namespaces:
+    team-a:
+      storageClass: fast-rwx
+  contexts:
+    prod:
+      serviceType: LoadBalancer
+      namespaces:
+        team-a:
+          gpuLimit: "2"`,
+		SilenceUsage: true,
+		// Skip applying the config, so a broken file can still be viewed and fixed.
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			options.Loader = nimconfig.NewLoader()
+			options.Context, options.Namespace = nimconfig.Current(configFlags)
+			return nil
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) > 0 {
+				fmt.Println(fmt.Errorf("unknown command(s) %q", strings.Join(args, " ")))
+			}
+			cmd.HelpFunc()(cmd, args)
+		},
+	}
+
+	cmd.AddCommand(newViewCommand(options))
+	cmd.AddCommand(newGetCommand(options))
+	cmd.AddCommand(newSetCommand(options))
+	return cmd
+}
+
+func newViewCommand(options *ConfigOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "view",
+		Short: "Show every default for the current context and namespace, and where it comes from",
+		Example: `  nim config view
+  nim config view --context prod -n team-a`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunView(options)
+		},
+	}
+}
+
+func newGetCommand(options *ConfigOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:          "get KEY",
+		Short:        "Print the default of a key for the current context and namespace",
+		Example:      `  nim config get storageClass`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunGet(options, args[0])
+		},
+	}
+}
+
+func newSetCommand(options *ConfigOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set KEY VALUE",
+		Short: "Set a default in the user or project config",
+		Long: `Set a default in the user config, or with --project in the nearest .nim.yaml, creating .nim.yaml in the working
+directory if there is none. --in-context and --in-namespace limit the setting to a kube context or namespace. An empty
+value removes the setting.
+
+Keys:
+` + keyHelp(),
+		Example: `  nim config set storageClass fast-rwx
+  nim config set gpuLimit 2 --in-context prod --in-namespace team-a
+  nim config set output yaml --project
+  nim config set storageClass ""`,
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunSet(options, args[0], args[1])
+		},
+	}
+
+	cmd.Flags().BoolVar(&options.Project, "project", false, "Write the project .nim.yaml instead of the user config.")
+	cmd.Flags().StringVar(&options.InContext, "in-context", "", "Only apply the setting in this kube context.")
+	cmd.Flags().StringVar(&options.InNamespace, "in-namespace", "", "Only apply the setting in this namespace.")
+
+	return cmd
+}
+
+func keyHelp() string {
+	var help strings.Builder
+	for _, key := range nimconfig.Keys {
+		fmt.Fprintf(&help, "  %-20s %s Env: %s.\n", key.Name, key.Description, key.Env)
+	}
+	return strings.TrimSuffix(help.String(), "\n")
+}
+
+func RunView(options *ConfigOptions) error {
+	config, err := options.Loader.Load(options.Context, options.Namespace)
+	if err != nil {
+		return err
+	}
+	out := options.IoStreams.Out
+	fmt.Fprintf(out, "Context: %s\nNamespace: %s\n\n", util.OrNone(config.Context), util.OrNone(config.Namespace))
+	return printValues(config, out)
+}
+
+func printValues(config *nimconfig.Config, output io.Writer) error {
+	resultTablePrinter := printers.NewTablePrinter(printers.PrintOptions{})
+
+	resTable := &v1.Table{
+		ColumnDefinitions: []v1.TableColumnDefinition{
+			{Name: "Key", Type: "string"},
+			{Name: "Value", Type: "string"},
+			{Name: "Source", Type: "string"},
+			{Name: "Env", Type: "string"},
+		},
+	}
+
+	for _, key := range nimconfig.Keys {
+		value := config.Values[key.Name]
+		resTable.Rows = append(resTable.Rows, v1.TableRow{
+			Cells: []interface{}{
+				key.Name,
+				util.OrNone(value.Value),
+				value.Source,
+				key.Env,
+			},
+		})
+	}
+
+	return resultTablePrinter.PrintObj(resTable, output)
+}
+
+func RunGet(options *ConfigOptions, name string) error {
+	if _, err := nimconfig.LookupKey(name); err != nil {
+		return err
+	}
+	config, err := options.Loader.Load(options.Context, options.Namespace)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(options.IoStreams.Out, config.Get(name))
+	return nil
+}
+
+func RunSet(options *ConfigOptions, name, value string) error {
+	key, err := nimconfig.LookupKey(name)
+	if err != nil {
+		return err
+	}
+	if err := key.Validate(value); err != nil {
+		return err
+	}
+
+	path, err := options.path()
+	if err != nil {
+		return err
+	}
+	file, err := nimconfig.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if value == "" {
+		delete(file.Settings(options.InContext, options.InNamespace, false), name)
+		file.Prune()
+	} else {
+		file.Settings(options.InContext, options.InNamespace, true)[name] = value
+	}
+	if err := file.WriteFile(path); err != nil {
+		return fmt.Errorf("failed to write config %s: %w", path, err)
+	}
+
+	scope := ""
+	if options.InContext != "" {
+		scope += fmt.Sprintf(" in context %s", options.InContext)
+	}
+	if options.InNamespace != "" {
+		scope += fmt.Sprintf(" in namespace %s", options.InNamespace)
+	}
+	if value == "" {
+		fmt.Fprintf(options.IoStreams.Out, "Removed %s%s from %s\n", name, scope, path)
+	} else {
+		fmt.Fprintf(options.IoStreams.Out, "Set %s to %q%s in %s\n", name, value, scope, path)
+	}
+	return nil
+}
+
+// Returns the file set writes.
+func (options *ConfigOptions) path() (string, error) {
+	if !options.Project {
+		if options.Loader.UserFile == "" {
+			return "", fmt.Errorf("failed to find the user config directory; set XDG_CONFIG_HOME or HOME")
+		}
+		return options.Loader.UserFile, nil
+	}
+	if options.Loader.ProjectFile != "" {
+		return options.Loader.ProjectFile, nil
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, nimconfig.ProjectFileName), nil
+}
@@ -0,0 +1,234 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	nimconfig "k8s-nim-operator-cli/pkg/util/config"
+)
+
+const userConfig = `authSecret: user-secret
+storageClass: standard
+namespaces:
+  team-a:
+    storageClass: team-a-rwx
+contexts:
+  prod:
+    serviceType: LoadBalancer
+    storageClass: prod-rwx
+    namespaces:
+      team-a:
+        gpuLimit: "2"
+`
+
+const projectConfig = `serviceType: NodePort
+output: yaml
+`
+
+func newTestOptions(t *testing.T, env map[string]string) (*ConfigOptions, *bytes.Buffer) {
+	t.Helper()
+	dir := t.TempDir()
+	out := &bytes.Buffer{}
+	streams := genericclioptions.IOStreams{In: strings.NewReader(""), Out: out, ErrOut: &bytes.Buffer{}}
+	loader := &nimconfig.Loader{
+		UserFile:    filepath.Join(dir, "user", "config.yaml"),
+		ProjectFile: filepath.Join(dir, "project", nimconfig.ProjectFileName),
+		Getenv:      func(name string) string { return env[name] },
+	}
+	return &ConfigOptions{IoStreams: &streams, Loader: loader, Context: "prod", Namespace: "team-a"}, out
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_Load_Precedence(t *testing.T) {
+	options, _ := newTestOptions(t, map[string]string{"NIM_OPERATOR_NAMESPACE": "nim-operator"})
+	writeFile(t, options.Loader.UserFile, userConfig)
+	writeFile(t, options.Loader.ProjectFile, projectConfig)
+
+	cases := map[string]struct {
+		context, namespace string
+		want               map[string]string
+	}{
+		"context and namespace": {"prod", "team-a", map[string]string{
+			nimconfig.AuthSecret:          "user-secret",
+			nimconfig.StorageClass:        "team-a-rwx",
+			nimconfig.ServiceType:         "NodePort",
+			nimconfig.GPULimit:            "2",
+			nimconfig.Output:              "yaml",
+			nimconfig.OperatorNamespace:   "nim-operator",
+			nimconfig.PVCVolumeAccessMode: "ReadWriteMany",
+		}},
+		"context only": {"prod", "team-b", map[string]string{
+			nimconfig.StorageClass: "prod-rwx",
+			nimconfig.GPULimit:     "1",
+		}},
+		"namespace only": {"dev", "team-a", map[string]string{
+			nimconfig.StorageClass: "team-a-rwx",
+			nimconfig.GPULimit:     "1",
+		}},
+		"no kubeconfig": {"", "default", map[string]string{
+			nimconfig.StorageClass: "standard",
+			nimconfig.PullSecrets:  "ngc-secret",
+		}},
+	}
+	for name, tc := range cases {
+		config, err := options.Loader.Load(tc.context, tc.namespace)
+		if err != nil {
+			t.Fatalf("%s: Load error: %v", name, err)
+		}
+		for key, want := range tc.want {
+			if got := config.Get(key); got != want {
+				t.Errorf("%s: %s = %q, want %q", name, key, got, want)
+			}
+		}
+	}
+
+	config, _ := options.Loader.Load("prod", "team-a")
+	if source := config.Values[nimconfig.GPULimit].Source; !strings.Contains(source, "context prod, namespace team-a") {
+		t.Errorf("unexpected gpuLimit source %q", source)
+	}
+	if source := config.Values[nimconfig.OperatorNamespace].Source; source != "env NIM_OPERATOR_NAMESPACE" {
+		t.Errorf("unexpected operatorNamespace source %q", source)
+	}
+}
+
+func Test_Load_Invalid(t *testing.T) {
+	options, _ := newTestOptions(t, map[string]string{"NIM_GPU_LIMIT": "0.5"})
+	if _, err := options.Loader.Load("", ""); err == nil || !strings.Contains(err.Error(), "NIM_GPU_LIMIT") {
+		t.Errorf("expected an error for NIM_GPU_LIMIT, got %v", err)
+	}
+
+	options, _ = newTestOptions(t, nil)
+	writeFile(t, options.Loader.UserFile, "storageclass: standard\n")
+	if _, err := options.Loader.Load("", ""); err == nil || !strings.Contains(err.Error(), `unknown key "storageclass"`) {
+		t.Errorf("expected an error for the unknown key, got %v", err)
+	}
+}
+
+func Test_RunSet(t *testing.T) {
+	options, out := newTestOptions(t, nil)
+
+	options.InContext, options.InNamespace = "prod", "team-a"
+	if err := RunSet(options, nimconfig.GPULimit, "4"); err != nil {
+		t.Fatalf("RunSet error: %v", err)
+	}
+	options.InContext, options.InNamespace = "", ""
+	if err := RunSet(options, nimconfig.StorageClass, "fast-rwx"); err != nil {
+		t.Fatalf("RunSet error: %v", err)
+	}
+	options.Project = true
+	if err := RunSet(options, nimconfig.Output, "yaml"); err != nil {
+		t.Fatalf("RunSet error: %v", err)
+	}
+	if !strings.Contains(out.String(), `Set gpuLimit to "4" in context prod in namespace team-a`) {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+
+	out.Reset()
+	if err := RunGet(options, nimconfig.GPULimit); err != nil || out.String() != "4\n" {
+		t.Errorf("RunGet = %q, %v", out.String(), err)
+	}
+	out.Reset()
+	if err := RunView(options); err != nil {
+		t.Fatalf("RunView error: %v", err)
+	}
+	for _, want := range []string{"fast-rwx", options.Loader.UserFile, "yaml", options.Loader.ProjectFile} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in view:\n%s", want, out.String())
+		}
+	}
+
+	// An empty value removes the setting and the emptied scopes.
+	options.Project = false
+	options.InContext, options.InNamespace = "prod", "team-a"
+	if err := RunSet(options, nimconfig.GPULimit, ""); err != nil {
+		t.Fatalf("RunSet error: %v", err)
+	}
+	data, err := os.ReadFile(options.Loader.UserFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "storageClass: fast-rwx\n" {
+		t.Errorf("unexpected user config:\n%s", data)
+	}
+}
+
+func Test_RunSet_Invalid(t *testing.T) {
+	options, _ := newTestOptions(t, nil)
+	for key, value := range map[string]string{
+		"storage-class":               "fast",
+		nimconfig.ServiceType:         "Internal",
+		nimconfig.PVCVolumeAccessMode: "RWX",
+		nimconfig.GPULimit:            "two",
+	} {
+		if err := RunSet(options, key, value); err == nil {
+			t.Errorf("expected an error setting %s to %q", key, value)
+		}
+	}
+	if _, err := os.Stat(options.Loader.UserFile); !os.IsNotExist(err) {
+		t.Errorf("invalid settings should not write the config: %v", err)
+	}
+	if err := RunGet(options, "gpu"); err == nil {
+		t.Errorf("expected an error getting an unknown key")
+	}
+}
+
+func Test_Apply(t *testing.T) {
+	options, _ := newTestOptions(t, nil)
+	writeFile(t, options.Loader.UserFile, userConfig+"pullSecrets: first,second\noutput: json\n")
+	config, err := options.Loader.Load("prod", "team-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var storageClass, serviceType, gpuLimit, pullSecret, output string
+	var pullSecrets []string
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.StringVar(&storageClass, "pvc-storage-class", "", "")
+	flags.StringVar(&serviceType, "service-type", "ClusterIP", "")
+	flags.StringVar(&gpuLimit, "gpu-limit", "1", "")
+	flags.StringVar(&pullSecret, "pull-secret", "ngc-secret", "")
+	flags.StringSliceVar(&pullSecrets, "pull-secrets", []string{"ngc-secret"}, "")
+	flags.StringVarP(&output, "output", "o", "table", "")
+	nimconfig.Bind(flags, "pvc-storage-class", nimconfig.StorageClass)
+	nimconfig.Bind(flags, "service-type", nimconfig.ServiceType)
+	nimconfig.Bind(flags, "gpu-limit", nimconfig.GPULimit)
+	nimconfig.Bind(flags, "pull-secret", nimconfig.PullSecrets)
+	nimconfig.Bind(flags, "pull-secrets", nimconfig.PullSecrets)
+	// json is not a format of this command, so the preferred format does not apply.
+	nimconfig.BindOutput(flags, "table", "yaml")
+
+	if err := flags.Parse([]string{"--service-type=NodePort"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := nimconfig.Apply(flags, config); err != nil {
+		t.Fatalf("Apply error: %v", err)
+	}
+
+	if storageClass != "team-a-rwx" || gpuLimit != "2" || output != "table" {
+		t.Errorf("unexpected values: storageClass %q, gpuLimit %q, output %q", storageClass, gpuLimit, output)
+	}
+	if serviceType != "NodePort" {
+		t.Errorf("a flag set on the command line should win, got %q", serviceType)
+	}
+	if pullSecret != "first" || strings.Join(pullSecrets, ",") != "first,second" {
+		t.Errorf("unexpected pull secrets %q, %q", pullSecret, pullSecrets)
+	}
+	if flags.Changed("gpu-limit") || flags.Lookup("gpu-limit").DefValue != "2" {
+		t.Errorf("configured flags should change their default, not be marked as set")
+	}
+}
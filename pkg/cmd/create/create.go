@@ -30,5 +30,6 @@ func NewCreateCommand(cmdFactory cmdutil.Factory, streams genericclioptions.IOSt
 
 	cmd.AddCommand(NewCreateNIMCacheCommand(cmdFactory, streams))
 	cmd.AddCommand(NewCreateNIMServiceCommand(cmdFactory, streams))
+	cmd.AddCommand(NewCreateSecretCommand(cmdFactory, streams))
 	return cmd
 }
\ No newline at end of file
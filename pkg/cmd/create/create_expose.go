@@ -0,0 +1,248 @@
+package create
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"k8s-nim-operator-cli/pkg/util"
+	"k8s-nim-operator-cli/pkg/util/client"
+)
+
+// Ways to expose a NIMService outside the cluster. Ingress and HTTPRoute are managed by the operator through
+// Spec.Expose; OpenShift Routes are not part of the NIMService spec, so the CLI creates the Route itself.
+const (
+	ExposeIngress   = "ingress"
+	ExposeHTTPRoute = "httproute"
+	ExposeRoute     = "route"
+)
+
+var routeGVR = schema.GroupVersionResource{Group: "route.openshift.io", Version: "v1", Resource: "routes"}
+
+// Returns how the NIMService should be exposed. Setting any ingress flag without --expose-via implies an Ingress.
+func exposeMode(options *NIMServiceOptions) (string, error) {
+	switch options.ExposeVia {
+	case "":
+		if options.IngressHost != "" || options.IngressClass != "" || options.IngressTLSSecret != "" {
+			return ExposeIngress, nil
+		}
+		return "", nil
+	case ExposeIngress, ExposeHTTPRoute, ExposeRoute:
+		return options.ExposeVia, nil
+	default:
+		return "", fmt.Errorf("invalid expose-via: %q, must be one of 'ingress', 'httproute', 'route'", options.ExposeVia)
+	}
+}
+
+// Fills Spec.Expose.Ingress or Spec.Expose.HTTPRoute from the ingress flags. Nothing is set for OpenShift Routes.
+func fillOutExpose(nimservice *appsv1alpha1.NIMService, options *NIMServiceOptions) field.ErrorList {
+	fldPath := field.NewPath("spec", "expose")
+	mode, err := exposeMode(options)
+	if err != nil {
+		return field.ErrorList{field.Invalid(fldPath, options.ExposeVia, err.Error())}
+	}
+	path := options.IngressPath
+	if path == "" {
+		path = "/"
+	}
+
+	switch mode {
+	case ExposeIngress:
+		if options.IngressHost == "" {
+			return field.ErrorList{field.Required(fldPath.Child("ingress", "spec", "rules").Index(0).Child("host"), "--ingress-host is required to expose the NIMService through an Ingress")}
+		}
+		port := options.ServicePort
+		if port == 0 {
+			port = appsv1alpha1.DefaultAPIPort
+		}
+		rule := networkingv1.IngressRule{
+			Host: options.IngressHost,
+			IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+				Paths: []networkingv1.HTTPIngressPath{{
+					Path:     path,
+					PathType: ptr.To(networkingv1.PathTypePrefix),
+					Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+						Name: options.ResourceName,
+						Port: networkingv1.ServiceBackendPort{Number: port},
+					}},
+				}},
+			}},
+		}
+		nimservice.Spec.Expose.Ingress.Enabled = ptr.To(true)
+		nimservice.Spec.Expose.Ingress.Spec.Rules = []networkingv1.IngressRule{rule}
+		if options.IngressClass != "" {
+			nimservice.Spec.Expose.Ingress.Spec.IngressClassName = ptr.To(options.IngressClass)
+		}
+		if options.IngressTLSSecret != "" {
+			nimservice.Spec.Expose.Ingress.Spec.TLS = []networkingv1.IngressTLS{{Hosts: []string{options.IngressHost}, SecretName: options.IngressTLSSecret}}
+		}
+	case ExposeHTTPRoute:
+		errs := field.ErrorList{}
+		if options.Gateway == "" {
+			errs = append(errs, field.Required(fldPath.Child("httpRoute", "spec", "parentRefs"), "--gateway is required to expose the NIMService through an HTTPRoute"))
+		}
+		if options.IngressClass != "" {
+			errs = append(errs, field.Invalid(fldPath.Child("ingress", "spec", "ingressClassName"), options.IngressClass, "--ingress-class does not apply to HTTPRoutes"))
+		}
+		if options.IngressTLSSecret != "" {
+			errs = append(errs, field.Invalid(fldPath.Child("ingress", "spec", "tls"), options.IngressTLSSecret, "--ingress-tls-secret does not apply to HTTPRoutes; TLS is configured on the Gateway"))
+		}
+		if len(errs) > 0 {
+			return errs
+		}
+		parent := gatewayv1.ParentReference{}
+		if namespace, name, ok := strings.Cut(options.Gateway, "/"); ok {
+			parent.Namespace = ptr.To(gatewayv1.Namespace(namespace))
+			parent.Name = gatewayv1.ObjectName(name)
+		} else {
+			parent.Name = gatewayv1.ObjectName(options.Gateway)
+		}
+		nimservice.Spec.Expose.HTTPRoute.Enabled = ptr.To(true)
+		nimservice.Spec.Expose.HTTPRoute.Spec = &appsv1alpha1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{parent}},
+			Host:            gatewayv1.Hostname(options.IngressHost),
+			Paths: []appsv1alpha1.HTTPPathMatch{{
+				Type:  ptr.To(gatewayv1.PathMatchPathPrefix),
+				Value: ptr.To(path),
+			}},
+		}
+	case ExposeRoute:
+		if options.IngressClass != "" {
+			return field.ErrorList{field.Invalid(fldPath.Child("ingress", "spec", "ingressClassName"), options.IngressClass, "--ingress-class does not apply to OpenShift Routes")}
+		}
+	}
+	return nil
+}
+
+// NewOpenShiftRoute returns a route.openshift.io/v1 Route to the NIMService's API port. Without a host, OpenShift
+// generates one. With --ingress-tls-secret the Route terminates TLS at the router: it references the secret through
+// spec.tls.externalCertificate, or, when tlsSecret is given, carries a copy of the secret's certificate and key for
+// clusters whose Routes cannot reference secrets.
+func NewOpenShiftRoute(options *NIMServiceOptions, tlsSecret *corev1.Secret) *unstructured.Unstructured {
+	spec := map[string]interface{}{
+		"to":   map[string]interface{}{"kind": "Service", "name": options.ResourceName},
+		"port": map[string]interface{}{"targetPort": appsv1alpha1.DefaultNamedPortAPI},
+	}
+	if options.IngressHost != "" {
+		spec["host"] = options.IngressHost
+	}
+	if options.IngressPath != "" && options.IngressPath != "/" {
+		spec["path"] = options.IngressPath
+	}
+	if options.IngressTLSSecret != "" {
+		tls := map[string]interface{}{
+			"termination":                   "edge",
+			"insecureEdgeTerminationPolicy": "Redirect",
+		}
+		if tlsSecret != nil {
+			tls["certificate"] = string(tlsSecret.Data[corev1.TLSCertKey])
+			tls["key"] = string(tlsSecret.Data[corev1.TLSPrivateKeyKey])
+		} else {
+			tls["externalCertificate"] = map[string]interface{}{"name": options.IngressTLSSecret}
+		}
+		spec["tls"] = tls
+	}
+
+	route := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	route.SetAPIVersion(routeGVR.GroupVersion().String())
+	route.SetKind("Route")
+	route.SetName(options.ResourceName)
+	route.SetNamespace(options.Namespace)
+	return route
+}
+
+// Creates the OpenShift Route for the NIMService, owned by it so that deleting the NIMService removes the Route.
+// Returns the Route's URL, including the host OpenShift generated when none was given.
+//
+// A TLS secret is referenced through spec.tls.externalCertificate. Clusters that do not know the field, or whose
+// router may not read the secret, reject that Route; the secret's certificate and key are then copied into the Route
+// instead, with a warning since the private key is stored in the Route from then on.
+func createOpenShiftRoute(ctx context.Context, dynamicClient dynamic.Interface, kube kubernetes.Interface, options *NIMServiceOptions, nimservice *appsv1alpha1.NIMService) (string, error) {
+	var tlsSecret *corev1.Secret
+	if options.IngressTLSSecret != "" {
+		secret, err := kube.CoreV1().Secrets(options.Namespace).Get(ctx, options.IngressTLSSecret, v1.GetOptions{})
+		if err != nil {
+			return "", fmt.Errorf("failed to get TLS secret %s/%s: %w", options.Namespace, options.IngressTLSSecret, err)
+		}
+		if len(secret.Data[corev1.TLSCertKey]) == 0 || len(secret.Data[corev1.TLSPrivateKeyKey]) == 0 {
+			return "", fmt.Errorf("secret %s/%s has no %s and %s", options.Namespace, options.IngressTLSSecret, corev1.TLSCertKey, corev1.TLSPrivateKeyKey)
+		}
+		tlsSecret = secret
+	}
+	routes := dynamicClient.Resource(routeGVR).Namespace(options.Namespace)
+	newRoute := func(tlsSecret *corev1.Secret) *unstructured.Unstructured {
+		route := NewOpenShiftRoute(options, tlsSecret)
+		route.SetOwnerReferences([]v1.OwnerReference{{
+			APIVersion: appsv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "NIMService",
+			Name:       nimservice.GetName(),
+			UID:        nimservice.GetUID(),
+		}})
+		return route
+	}
+
+	// Strict field validation makes servers that do not know externalCertificate reject it instead of dropping it.
+	created, err := routes.Create(ctx, newRoute(nil), v1.CreateOptions{FieldValidation: "Strict"})
+	if err != nil && tlsSecret != nil && (apierrors.IsBadRequest(err) || apierrors.IsInvalid(err)) {
+		fmt.Fprintf(options.IoStreams.ErrOut, "Warning: the Route cannot reference secret %s/%s (%v); copying its certificate and private key into the Route instead.\n", options.Namespace, options.IngressTLSSecret, err)
+		created, err = routes.Create(ctx, newRoute(tlsSecret), v1.CreateOptions{})
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to create Route %s/%s: %w", options.Namespace, options.ResourceName, err)
+	}
+	host, _, _ := unstructured.NestedString(created.Object, "spec", "host")
+	if host == "" {
+		return "", nil
+	}
+	scheme := "http"
+	if _, ok, _ := unstructured.NestedMap(created.Object, "spec", "tls"); ok {
+		scheme = "https"
+	}
+	path, _, _ := unstructured.NestedString(created.Object, "spec", "path")
+	return scheme + "://" + host + path, nil
+}
+
+// Creates the Route of a NIMService that was just created and records the Route's URL on the NIMService, so that nim get
+// shows it. If the Route cannot be created, the NIMService is deleted again so that the command can simply be retried.
+func exposeViaRoute(ctx context.Context, dynamicClient dynamic.Interface, k8sClient client.Client, options *NIMServiceOptions, nimservice *appsv1alpha1.NIMService) error {
+	nimServices := k8sClient.NIMClient().AppsV1alpha1().NIMServices(nimservice.GetNamespace())
+	url, err := createOpenShiftRoute(ctx, dynamicClient, k8sClient.KubernetesClient(), options, nimservice)
+	if err != nil {
+		if deleteErr := nimServices.Delete(ctx, nimservice.GetName(), v1.DeleteOptions{}); deleteErr != nil {
+			return fmt.Errorf("%w; NIMService %s/%s was left behind without a Route and could not be deleted: %v", err, nimservice.GetNamespace(), nimservice.GetName(), deleteErr)
+		}
+		fmt.Fprintf(options.IoStreams.ErrOut, "NIMService %q deleted from namespace %q again since its Route could not be created.\n", nimservice.GetName(), nimservice.GetNamespace())
+		return err
+	}
+	fmt.Fprintf(options.IoStreams.Out, "Route %q created in namespace %q\n", options.ResourceName, options.Namespace)
+	if url == "" {
+		return nil
+	}
+	fmt.Fprintf(options.IoStreams.Out, "NIMService will be reachable at %s\n", url)
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": map[string]string{util.RouteURLAnnotation: url}},
+	})
+	if err == nil {
+		_, err = nimServices.Patch(ctx, nimservice.GetName(), types.MergePatchType, patch, v1.PatchOptions{})
+	}
+	if err != nil {
+		fmt.Fprintf(options.IoStreams.ErrOut, "Warning: failed to record the Route URL on NIMService %s/%s, nim get will not show it: %v\n", nimservice.GetNamespace(), nimservice.GetName(), err)
+	}
+	return nil
+}
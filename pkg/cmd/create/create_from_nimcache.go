@@ -0,0 +1,91 @@
+package create
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	util "k8s-nim-operator-cli/pkg/util"
+	"k8s-nim-operator-cli/pkg/util/client"
+)
+
+// Splits an image reference into repository and tag. Digests cannot be expressed as a NIMService image tag.
+func splitImage(image string) (string, string, error) {
+	if strings.Contains(image, "@") {
+		return "", "", fmt.Errorf("image %q is pinned by digest; set --image-repository and --tag", image)
+	}
+	// A colon before the last slash belongs to a registry port, not a tag.
+	colon := strings.LastIndex(image, ":")
+	if colon == -1 || colon < strings.LastIndex(image, "/") {
+		return "", "", fmt.Errorf("image %q has no tag; set --image-repository and --tag", image)
+	}
+	return image[:colon], image[colon+1:], nil
+}
+
+// Completes the options from the NIMCache named by --from-nimcache: the NIMCache becomes the storage, the image
+// defaults to the NGC model puller, which is the NIM image itself, and the GPU limit defaults to the tensor
+// parallelism of the profile. The NIMCache must be Ready, and the profile must be one it cached.
+func completeFromNIMCache(ctx context.Context, options *NIMServiceOptions, k8sClient client.Client) error {
+	nimcache, err := k8sClient.NIMClient().AppsV1alpha1().NIMCaches(options.Namespace).Get(ctx, options.FromNIMCache, v1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get NIMCache %s/%s: %w", options.Namespace, options.FromNIMCache, err)
+	}
+	if nimcache.Status.State != appsv1alpha1.NimCacheStatusReady {
+		return fmt.Errorf("NIMCache %s/%s is not ready (state %q); wait for it to finish caching", options.Namespace, options.FromNIMCache, nimcache.Status.State)
+	}
+
+	if options.NIMCacheStorageName != "" && options.NIMCacheStorageName != options.FromNIMCache {
+		return fmt.Errorf("--nimcache-storage-name %q conflicts with --from-nimcache %q", options.NIMCacheStorageName, options.FromNIMCache)
+	}
+	options.NIMCacheStorageName = options.FromNIMCache
+
+	// Explicit image flags win over the model puller.
+	if options.ImageRepository == "" || options.Tag == "" {
+		if nimcache.Spec.Source.NGC == nil {
+			return fmt.Errorf("NIMCache %s/%s does not cache from NGC, so the NIM image cannot be derived; set --image-repository and --tag", options.Namespace, options.FromNIMCache)
+		}
+		repository, tag, err := splitImage(nimcache.Spec.Source.NGC.ModelPuller)
+		if err != nil {
+			return err
+		}
+		if options.ImageRepository == "" {
+			options.ImageRepository = repository
+		}
+		if options.Tag == "" {
+			options.Tag = tag
+		}
+	}
+
+	// Without a profile, a NIMCache holding a single profile implies it.
+	profiles := nimcache.Status.Profiles
+	if options.NIMCacheStorageProfile == "" {
+		if len(profiles) != 1 {
+			return nil
+		}
+		options.NIMCacheStorageProfile = profiles[0].Name
+	}
+	var profile *appsv1alpha1.NIMProfile
+	ids := make([]string, 0, len(profiles))
+	for i := range profiles {
+		ids = append(ids, profiles[i].Name)
+		if profiles[i].Name == options.NIMCacheStorageProfile {
+			profile = &profiles[i]
+		}
+	}
+	if profile == nil {
+		return fmt.Errorf("profile %q is not cached by NIMCache %s/%s; cached profiles: %s", options.NIMCacheStorageProfile, options.Namespace, options.FromNIMCache, util.OrNone(strings.Join(ids, ", ")))
+	}
+
+	tp := util.NewProfileInfo(*profile).TP
+	switch {
+	case tp == "":
+	case options.GPULimit == options.defaultGPULimit():
+		options.GPULimit = tp
+	case options.GPULimit != tp:
+		fmt.Fprintf(options.IoStreams.ErrOut, "Warning: --gpu-limit %s differs from the tensor parallelism %s of profile %s.\n", options.GPULimit, tp, profile.Name)
+	}
+	return nil
+}
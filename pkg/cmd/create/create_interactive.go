@@ -0,0 +1,513 @@
+package create
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/yaml"
+
+	util "k8s-nim-operator-cli/pkg/util"
+	"k8s-nim-operator-cli/pkg/util/client"
+)
+
+const (
+	// Node label set by GPU feature discovery.
+	gpuProductLabel = "nvidia.com/gpu.product"
+	defaultClassKey = "storageclass.kubernetes.io/is-default-class"
+	// Answer for the GPU product question that leaves the node selector unset.
+	anyGPU = "any"
+
+	storageNIMCache  = "nimcache"
+	storagePVC       = "pvc"
+	storagePVCCreate = "pvc-create"
+	storageHostPath  = "hostpath"
+)
+
+var errInputEnded = errors.New("no answer: standard input ended")
+
+type choice struct {
+	value string
+	help  string
+}
+
+func choicesOf(values []string) []choice {
+	choices := make([]choice, 0, len(values))
+	for _, value := range values {
+		choices = append(choices, choice{value: value})
+	}
+	return choices
+}
+
+// Asks questions on IoStreams.Out and reads the answers line by line from IoStreams.In. An empty answer accepts the
+// default in brackets, which comes from the flags, so flags given with --interactive pre-fill the wizard.
+type prompter struct {
+	scanner *bufio.Scanner
+	out     io.Writer
+}
+
+func newPrompter(streams *genericclioptions.IOStreams) *prompter {
+	return &prompter{scanner: bufio.NewScanner(streams.In), out: streams.Out}
+}
+
+func (p *prompter) ask(question, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", question)
+	}
+	if !p.scanner.Scan() {
+		fmt.Fprintln(p.out)
+		if err := p.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", errInputEnded
+	}
+	if answer := strings.TrimSpace(p.scanner.Text()); answer != "" {
+		return answer, nil
+	}
+	return def, nil
+}
+
+// Asks until the answer is not empty.
+func (p *prompter) require(question, def string) (string, error) {
+	for {
+		answer, err := p.ask(question, def)
+		if err != nil || answer != "" {
+			return answer, err
+		}
+		fmt.Fprintln(p.out, "  A value is required.")
+	}
+}
+
+// Lists the choices numbered and accepts a number or a value. With other set, values that are not listed are accepted
+// too, for objects the cluster did not let us list.
+func (p *prompter) choose(question string, choices []choice, def string, other bool) (string, error) {
+	for i, c := range choices {
+		if c.help != "" {
+			fmt.Fprintf(p.out, "  %d) %-12s %s\n", i+1, c.value, c.help)
+		} else {
+			fmt.Fprintf(p.out, "  %d) %s\n", i+1, c.value)
+		}
+	}
+	for {
+		answer, err := p.ask(question, def)
+		if err != nil {
+			return "", err
+		}
+		for _, c := range choices {
+			if c.value == answer {
+				return answer, nil
+			}
+		}
+		n, err := strconv.Atoi(answer)
+		switch {
+		case err == nil && n >= 1 && n <= len(choices):
+			return choices[n-1].value, nil
+		// A number that is not in the list is a typo rather than a name.
+		case other && answer != "" && (err != nil || len(choices) == 0):
+			return answer, nil
+		}
+		fmt.Fprintln(p.out, "  Choose one of the numbers or values above.")
+	}
+}
+
+func (p *prompter) confirm(question string) (bool, error) {
+	for {
+		answer, err := p.ask(question+" (y/N)", "")
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "y", "yes":
+			return true, nil
+		case "", "n", "no":
+			return false, nil
+		}
+		fmt.Fprintln(p.out, "  Answer y or n.")
+	}
+}
+
+// Prints the object as YAML and asks before it is created.
+func (p *prompter) confirmCreate(obj interface{}, kind, namespace, name string) (bool, error) {
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return false, fmt.Errorf("failed to print %s: %w", kind, err)
+	}
+	fmt.Fprintf(p.out, "\n---\n%s---\n", data)
+	return p.confirm(fmt.Sprintf("Create %s %s/%s?", kind, namespace, name))
+}
+
+// The listings below fill the choices. Listing may be forbidden, e.g. for StorageClasses or nodes, in which case the
+// question takes any value instead.
+
+func listPVCs(ctx context.Context, k8sClient client.Client, namespace string) []string {
+	pvcs, err := k8sClient.KubernetesClient().CoreV1().PersistentVolumeClaims(namespace).List(ctx, v1.ListOptions{})
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, pvc := range pvcs.Items {
+		names = append(names, pvc.Name)
+	}
+	return names
+}
+
+// Returns the StorageClass names and the cluster's default class.
+func listStorageClasses(ctx context.Context, k8sClient client.Client) ([]string, string) {
+	classes, err := k8sClient.KubernetesClient().StorageV1().StorageClasses().List(ctx, v1.ListOptions{})
+	if err != nil {
+		return nil, ""
+	}
+	var names []string
+	def := ""
+	for _, class := range classes.Items {
+		names = append(names, class.Name)
+		if class.Annotations[defaultClassKey] == "true" {
+			def = class.Name
+		}
+	}
+	return names, def
+}
+
+func listSecrets(ctx context.Context, k8sClient client.Client, namespace string, secretType corev1.SecretType) []string {
+	secrets, err := k8sClient.KubernetesClient().CoreV1().Secrets(namespace).List(ctx, v1.ListOptions{})
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, secret := range secrets.Items {
+		if secret.Type == secretType {
+			names = append(names, secret.Name)
+		}
+	}
+	return names
+}
+
+func listReadyNIMCaches(ctx context.Context, k8sClient client.Client, namespace string) []appsv1alpha1.NIMCache {
+	nimcaches, err := k8sClient.NIMClient().AppsV1alpha1().NIMCaches(namespace).List(ctx, v1.ListOptions{})
+	if err != nil {
+		return nil
+	}
+	var ready []appsv1alpha1.NIMCache
+	for _, nimcache := range nimcaches.Items {
+		if nimcache.Status.State == appsv1alpha1.NimCacheStatusReady {
+			ready = append(ready, nimcache)
+		}
+	}
+	return ready
+}
+
+// Returns the GPU products of the cluster's nodes with the number of nodes carrying each.
+func listGPUProducts(ctx context.Context, k8sClient client.Client) []choice {
+	nodes, err := k8sClient.KubernetesClient().CoreV1().Nodes().List(ctx, v1.ListOptions{LabelSelector: gpuProductLabel})
+	if err != nil {
+		return nil
+	}
+	counts := map[string]int{}
+	for _, node := range nodes.Items {
+		if product := node.Labels[gpuProductLabel]; product != "" {
+			counts[product]++
+		}
+	}
+	products := make([]string, 0, len(counts))
+	for product := range counts {
+		products = append(products, product)
+	}
+	sort.Strings(products)
+	choices := make([]choice, 0, len(products))
+	for _, product := range products {
+		choices = append(choices, choice{value: product, help: fmt.Sprintf("%d node(s)", counts[product])})
+	}
+	return choices
+}
+
+// Summarizes a profile's tags, e.g. "tensorrt_llm fp8 H100 tp2".
+func describeProfile(profile util.ProfileInfo) string {
+	var tags []string
+	for _, tag := range []string{profile.LLMEngine, profile.Precision, profile.GPU} {
+		if tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	if profile.TP != "" {
+		tags = append(tags, "tp"+profile.TP)
+	}
+	return strings.Join(tags, " ")
+}
+
+// Asks for the name, size, StorageClass and access mode of a new PVC.
+func promptNewPVC(ctx context.Context, p *prompter, k8sClient client.Client, resourceName string, name, size, storageClass, accessMode *string) error {
+	var err error
+	def := *name
+	if def == "" {
+		def = resourceName + "-pvc"
+	}
+	if *name, err = p.require("PVC name", def); err != nil {
+		return err
+	}
+	if *size, err = p.require("PVC size, e.g. 50Gi", *size); err != nil {
+		return err
+	}
+	classes, defaultClass := listStorageClasses(ctx, k8sClient)
+	if *storageClass == "" {
+		*storageClass = defaultClass
+	}
+	fmt.Fprintln(p.out, "StorageClasses:")
+	if *storageClass, err = p.choose("StorageClass", choicesOf(classes), *storageClass, true); err != nil {
+		return err
+	}
+	fmt.Fprintln(p.out, "Access modes (ReadWriteMany lets replicas on several nodes share the model):")
+	modes := []string{string(corev1.ReadWriteOnce), string(corev1.ReadWriteMany), string(corev1.ReadOnlyMany), string(corev1.ReadWriteOncePod)}
+	*accessMode, err = p.choose("Access mode", choicesOf(modes), *accessMode, false)
+	return err
+}
+
+// Asks for an existing secret of the given type, listing the namespace's secrets of that type.
+func promptSecret(ctx context.Context, p *prompter, k8sClient client.Client, namespace, question string, secretType corev1.SecretType, def string) (string, error) {
+	fmt.Fprintf(p.out, "%s secrets in %s:\n", secretType, namespace)
+	return p.choose(question, choicesOf(listSecrets(ctx, k8sClient, namespace, secretType)), def, true)
+}
+
+// Asks for the GPU product to schedule on and returns "" for any product.
+func promptGPUProduct(ctx context.Context, p *prompter, k8sClient client.Client, def string) (string, error) {
+	if def == "" {
+		def = anyGPU
+	}
+	fmt.Fprintln(p.out, "GPU products on the cluster's nodes:")
+	choices := append([]choice{{value: anyGPU, help: "do not pin a GPU product"}}, listGPUProducts(ctx, k8sClient)...)
+	product, err := p.choose("GPU product", choices, def, true)
+	if product == anyGPU {
+		product = ""
+	}
+	return product, err
+}
+
+// Fills the NIMService options from the answers. The NIMService is only created after the generated YAML is confirmed.
+func promptNIMService(ctx context.Context, p *prompter, options *NIMServiceOptions, k8sClient client.Client) error {
+	fmt.Fprintf(p.out, "Creating NIMService %s/%s. Press Enter to accept the value in brackets.\n\n", options.Namespace, options.ResourceName)
+
+	nimcaches := listReadyNIMCaches(ctx, k8sClient, options.Namespace)
+	mode := storagePVCCreate
+	switch {
+	case options.FromNIMCache != "" || options.NIMCacheStorageName != "":
+		mode = storageNIMCache
+	case options.HostPath != "":
+		mode = storageHostPath
+	case options.PVCStorageName != "" && !options.PVCCreate:
+		mode = storagePVC
+	case !options.PVCCreate && len(nimcaches) > 0:
+		mode = storageNIMCache
+	}
+	fmt.Fprintln(p.out, "Model storage:")
+	mode, err := p.choose("Storage", []choice{
+		{storageNIMCache, "a Ready NIMCache, which also provides the image and profile"},
+		{storagePVC, "an existing PersistentVolumeClaim holding the model"},
+		{storagePVCCreate, "a new PersistentVolumeClaim the NIM downloads the model to"},
+		{storageHostPath, "a directory on the node"},
+	}, mode, false)
+	if err != nil {
+		return err
+	}
+
+	switch mode {
+	case storageNIMCache:
+		names := make([]string, 0, len(nimcaches))
+		for _, nimcache := range nimcaches {
+			names = append(names, nimcache.Name)
+		}
+		def := options.FromNIMCache
+		if def == "" {
+			def = options.NIMCacheStorageName
+		}
+		fmt.Fprintf(p.out, "Ready NIMCaches in %s:\n", options.Namespace)
+		if options.FromNIMCache, err = p.choose("NIMCache", choicesOf(names), def, true); err != nil {
+			return err
+		}
+		options.NIMCacheStorageName = ""
+		for _, nimcache := range nimcaches {
+			if nimcache.Name != options.FromNIMCache || len(nimcache.Status.Profiles) < 2 {
+				continue
+			}
+			profiles := make([]choice, 0, len(nimcache.Status.Profiles))
+			for _, profile := range nimcache.Status.Profiles {
+				profiles = append(profiles, choice{value: profile.Name, help: describeProfile(util.NewProfileInfo(profile))})
+			}
+			fmt.Fprintln(p.out, "Cached profiles:")
+			if options.NIMCacheStorageProfile, err = p.choose("Profile", profiles, options.NIMCacheStorageProfile, false); err != nil {
+				return err
+			}
+		}
+	case storagePVC:
+		fmt.Fprintf(p.out, "PersistentVolumeClaims in %s:\n", options.Namespace)
+		if options.PVCStorageName, err = p.choose("PVC", choicesOf(listPVCs(ctx, k8sClient, options.Namespace)), options.PVCStorageName, true); err != nil {
+			return err
+		}
+		options.PVCCreate = false
+	case storagePVCCreate:
+		options.PVCCreate = true
+		if err := promptNewPVC(ctx, p, k8sClient, options.ResourceName, &options.PVCStorageName, &options.PVCSize, &options.PVCStorageClass, &options.PVCVolumeAccessMode); err != nil {
+			return err
+		}
+	case storageHostPath:
+		if options.HostPath, err = p.require("Host path", options.HostPath); err != nil {
+			return err
+		}
+	}
+
+	// A NIMCache provides the image through its model puller.
+	if mode == storageNIMCache {
+		if options.ImageRepository, err = p.ask("Image repository (empty to use the NIMCache's model puller)", options.ImageRepository); err != nil {
+			return err
+		}
+		if options.ImageRepository != "" {
+			if options.Tag, err = p.require("Image tag", options.Tag); err != nil {
+				return err
+			}
+		}
+	} else {
+		if options.ImageRepository, err = p.require("Image repository, e.g. nvcr.io/nim/meta/llama-3.1-8b-instruct", options.ImageRepository); err != nil {
+			return err
+		}
+		if options.Tag, err = p.require("Image tag", options.Tag); err != nil {
+			return err
+		}
+	}
+
+	if options.AuthSecret, err = promptSecret(ctx, p, k8sClient, options.Namespace, "NGC API key secret", corev1.SecretTypeOpaque, options.AuthSecret); err != nil {
+		return err
+	}
+	pullSecret := ""
+	if len(options.PullSecrets) > 0 {
+		pullSecret = options.PullSecrets[0]
+	}
+	if pullSecret, err = promptSecret(ctx, p, k8sClient, options.Namespace, "Image pull secret", corev1.SecretTypeDockerConfigJson, pullSecret); err != nil {
+		return err
+	}
+	options.PullSecrets = []string{pullSecret}
+
+	product, err := promptGPUProduct(ctx, p, k8sClient, options.NodeSelector[gpuProductLabel])
+	if err != nil {
+		return err
+	}
+	if product != "" {
+		if options.NodeSelector == nil {
+			options.NodeSelector = map[string]string{}
+		}
+		options.NodeSelector[gpuProductLabel] = product
+	}
+	question := "GPUs per replica"
+	if mode == storageNIMCache {
+		question += " (the default follows the profile's tensor parallelism)"
+	}
+	options.GPULimit, err = p.require(question, options.GPULimit)
+	return err
+}
+
+// Fills the NIMCache options from the answers. The NIMCache is only created after the generated YAML is confirmed.
+func promptNIMCache(ctx context.Context, p *prompter, options *NIMCacheOptions, k8sClient client.Client) error {
+	fmt.Fprintf(p.out, "Creating NIMCache %s/%s. Press Enter to accept the value in brackets.\n\n", options.Namespace, options.ResourceName)
+
+	source := strings.ToLower(options.SourceConfiguration)
+	if source == "" {
+		source = "ngc"
+	}
+	fmt.Fprintln(p.out, "Model source:")
+	source, err := p.choose("Source", []choice{
+		{"ngc", "a NIM model from NGC"},
+		{"huggingface", "a model or dataset from a Hugging Face Hub"},
+		{"nemodatastore", "a model or dataset from NeMo DataStore"},
+	}, source, false)
+	if err != nil {
+		return err
+	}
+	options.SourceConfiguration = source
+
+	if source == "ngc" {
+		if options.ModelPuller, err = p.require("NIM image to pull the model with, e.g. nvcr.io/nim/meta/llama-3.1-8b-instruct:1.3.3", options.ModelPuller); err != nil {
+			return err
+		}
+		if options.AuthSecret, err = promptSecret(ctx, p, k8sClient, options.Namespace, "NGC API key secret", corev1.SecretTypeOpaque, options.AuthSecret); err != nil {
+			return err
+		}
+	} else {
+		endpoint := options.AltEndpoint
+		if endpoint == "" && source == "huggingface" {
+			endpoint = "https://huggingface.co"
+		}
+		if options.AltEndpoint, err = p.require("Endpoint", endpoint); err != nil {
+			return err
+		}
+		if options.AltNamespace, err = p.require("Namespace within the hub", options.AltNamespace); err != nil {
+			return err
+		}
+		if options.ModelName, err = p.ask("Model name (empty to cache a dataset)", options.ModelName); err != nil {
+			return err
+		}
+		if options.ModelName == "" {
+			if options.DatasetName, err = p.require("Dataset name", options.DatasetName); err != nil {
+				return err
+			}
+		}
+		if options.ModelPuller, err = p.require("Image with huggingface-cli to pull the model with", options.ModelPuller); err != nil {
+			return err
+		}
+		authSecret := options.AuthSecret
+		if authSecret == util.AuthSecret {
+			authSecret = util.HFAuthSecret
+		}
+		if options.AuthSecret, err = promptSecret(ctx, p, k8sClient, options.Namespace, "Token secret", corev1.SecretTypeOpaque, authSecret); err != nil {
+			return err
+		}
+	}
+	if options.PullSecret, err = promptSecret(ctx, p, k8sClient, options.Namespace, "Image pull secret", corev1.SecretTypeDockerConfigJson, options.PullSecret); err != nil {
+		return err
+	}
+
+	if source == "ngc" {
+		def := ""
+		if len(options.GPUs) > 0 {
+			def = options.GPUs[0]
+		}
+		product, err := promptGPUProduct(ctx, p, k8sClient, def)
+		if err != nil {
+			return err
+		}
+		options.GPUs = nil
+		if product != "" {
+			options.GPUs = []string{product}
+		}
+		if options.TensorParallelism, err = p.ask("GPUs per replica (tensor parallelism, empty for any)", options.TensorParallelism); err != nil {
+			return err
+		}
+	}
+
+	mode := storagePVCCreate
+	if options.PVCStorageName != "" && !options.PVCCreate {
+		mode = storagePVC
+	}
+	fmt.Fprintln(p.out, "Storage for the cache:")
+	if mode, err = p.choose("Storage", []choice{
+		{storagePVC, "an existing PersistentVolumeClaim"},
+		{storagePVCCreate, "a new PersistentVolumeClaim"},
+	}, mode, false); err != nil {
+		return err
+	}
+	if mode == storagePVC {
+		options.PVCCreate = false
+		fmt.Fprintf(p.out, "PersistentVolumeClaims in %s:\n", options.Namespace)
+		options.PVCStorageName, err = p.choose("PVC", choicesOf(listPVCs(ctx, k8sClient, options.Namespace)), options.PVCStorageName, true)
+		return err
+	}
+	options.PVCCreate = true
+	return promptNewPVC(ctx, p, k8sClient, options.ResourceName, &options.PVCStorageName, &options.PVCSize, &options.PVCStorageClass, &options.PVCVolumeAccessMode)
+}
@@ -0,0 +1,82 @@
+package create
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+
+	util "k8s-nim-operator-cli/pkg/util"
+)
+
+// Duration format accepted by Prometheus, e.g. 30s or 1m30s. ServiceMonitors with other values are rejected.
+var prometheusDuration = regexp.MustCompile(`^(0|(([0-9]+)y)?(([0-9]+)w)?(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?)$`)
+
+// Fills Spec.Metrics from the metrics flags. ServiceMonitor settings imply --metrics.
+func fillOutMetrics(nimservice *appsv1alpha1.NIMService, options *NIMServiceOptions) field.ErrorList {
+	if !options.Metrics && options.ServiceMonitorInterval == "" && len(options.ServiceMonitorLabels) == 0 {
+		return nil
+	}
+	nimservice.Spec.Metrics.Enabled = ptr.To(true)
+
+	// The operator rejects enabled metrics with an empty ServiceMonitor, so the interval always gets a value.
+	interval := options.ServiceMonitorInterval
+	if interval == "" {
+		interval = util.DefaultServiceMonitorInterval
+	}
+	if !prometheusDuration.MatchString(interval) {
+		return field.ErrorList{field.Invalid(field.NewPath("spec", "metrics", "serviceMonitor", "interval"), interval, "invalid service-monitor-interval, must be a Prometheus duration such as 30s or 1m")}
+	}
+	nimservice.Spec.Metrics.ServiceMonitor.Interval = promv1.Duration(interval)
+	if len(options.ServiceMonitorLabels) > 0 {
+		nimservice.Spec.Metrics.ServiceMonitor.AdditionalLabels = options.ServiceMonitorLabels
+	}
+	return nil
+}
+
+// Returns the HPA metrics for the autoscaling flags: a CPU utilization target and per-pod custom metrics given as
+// METRIC=TARGET, e.g. gpu_cache_usage_perc=0.75 or num_requests_running=10. Pod metrics are averaged across pods and
+// need a metrics adapter, such as prometheus-adapter, serving them through custom.metrics.k8s.io.
+func parseScaleMetrics(options *NIMServiceOptions) ([]autoscalingv2.MetricSpec, error) {
+	var metrics []autoscalingv2.MetricSpec
+	if options.ScaleCPUUtilization > 0 {
+		metrics = append(metrics, autoscalingv2.MetricSpec{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name: corev1.ResourceCPU,
+				Target: autoscalingv2.MetricTarget{
+					Type:               autoscalingv2.UtilizationMetricType,
+					AverageUtilization: ptr.To(options.ScaleCPUUtilization),
+				},
+			},
+		})
+	}
+	for _, value := range options.ScaleMetrics {
+		name, target, ok := strings.Cut(value, "=")
+		if !ok || name == "" || target == "" {
+			return nil, fmt.Errorf("invalid --scale-metric %q, must be METRIC=TARGET", value)
+		}
+		quantity, err := resource.ParseQuantity(target)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --scale-metric %q target: %w", value, err)
+		}
+		metrics = append(metrics, autoscalingv2.MetricSpec{
+			Type: autoscalingv2.PodsMetricSourceType,
+			Pods: &autoscalingv2.PodsMetricSource{
+				Metric: autoscalingv2.MetricIdentifier{Name: name},
+				Target: autoscalingv2.MetricTarget{
+					Type:         autoscalingv2.AverageValueMetricType,
+					AverageValue: &quantity,
+				},
+			},
+		})
+	}
+	return metrics, nil
+}
@@ -0,0 +1,99 @@
+package create
+
+import (
+	"fmt"
+	"strconv"
+
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	util "k8s-nim-operator-cli/pkg/util"
+)
+
+// Returns whether any multi-node flag is set. The backend alone does not enable multi-node, since it has a default.
+func isMultiNode(options *NIMServiceOptions) bool {
+	return options.MultiNodeSize > 0 || options.PipelineParallelism > 0 || options.GPUsPerPod > 0 || options.MPIStartTimeout > 0
+}
+
+// Fills Spec.MultiNode from the multi-node flags and returns the GPU limit each pod should get.
+// The operator runs a LeaderWorkerSet of MultiNodeSize pods per replica and sets NIM_PIPELINE_PARALLEL_SIZE to the size
+// and NIM_TENSOR_PARALLEL_SIZE to the GPUs per pod, so --pipeline-parallelism is another name for --multi-node-size and
+// --gpus-per-pod must match the pod's GPU limit.
+func fillOutMultiNode(nimservice *appsv1alpha1.NIMService, options *NIMServiceOptions) (string, field.ErrorList) {
+	errs := field.ErrorList{}
+	fldPath := field.NewPath("spec", "multiNode")
+	for _, value := range []struct {
+		path  *field.Path
+		flag  string
+		value int
+	}{
+		{fldPath.Child("size"), "multi-node-size", options.MultiNodeSize},
+		{fldPath.Child("size"), "pipeline-parallelism", options.PipelineParallelism},
+		{fldPath.Child("gpusPerPod"), "gpus-per-pod", options.GPUsPerPod},
+		{fldPath.Child("mpi", "mpiStartTimeout"), "mpi-start-timeout", options.MPIStartTimeout},
+	} {
+		if value.value < 0 {
+			errs = append(errs, field.Invalid(value.path, value.value, fmt.Sprintf("invalid %s, must not be negative", value.flag)))
+		}
+	}
+	backend := options.MultiNodeBackend
+	if backend == "" {
+		backend = util.MultiNodeBackend
+	}
+	if backend != string(appsv1alpha1.NIMBackendTypeLWS) {
+		errs = append(errs, field.Invalid(fldPath.Child("backendType"), backend, "invalid multi-node-backend, must be 'lws'"))
+	}
+	if len(errs) > 0 {
+		return "", errs
+	}
+	if !isMultiNode(options) {
+		return options.GPULimit, nil
+	}
+	if options.InferencePlatform == string(appsv1alpha1.PlatformTypeKServe) {
+		errs = append(errs, field.Invalid(fldPath, options.InferencePlatform, fmt.Sprintf("multi-node NIMServices are not supported with inference-platform %q", options.InferencePlatform)))
+	}
+	// The CRD rejects autoscaling for LeaderWorkerSets; scale them with --replicas instead.
+	if options.ScaleMaxReplicas != -1 {
+		errs = append(errs, field.Invalid(field.NewPath("spec", "scale", "hpa", "maxReplicas"), options.ScaleMaxReplicas, "--scale-max-replicas cannot be used with multi-node NIMServices; set --replicas instead"))
+	}
+
+	size := options.MultiNodeSize
+	if options.PipelineParallelism > 0 {
+		if size > 0 && size != options.PipelineParallelism {
+			errs = append(errs, field.Invalid(fldPath.Child("size"), options.PipelineParallelism, fmt.Sprintf("--multi-node-size %d and --pipeline-parallelism %d must match; each pod of the group runs one pipeline stage", size, options.PipelineParallelism)))
+		}
+		size = options.PipelineParallelism
+	}
+	if size < 1 {
+		size = 1
+	}
+
+	gpuLimit := options.GPULimit
+	gpusPerPod := options.GPUsPerPod
+	if gpusPerPod > 0 {
+		// An explicit --gpu-limit has to agree; the default is replaced by the GPUs per pod.
+		if gpuLimit != options.defaultGPULimit() && gpuLimit != strconv.Itoa(gpusPerPod) {
+			errs = append(errs, field.Invalid(fldPath.Child("gpusPerPod"), gpusPerPod, fmt.Sprintf("--gpu-limit %s and --gpus-per-pod %d must match; the GPUs per pod set the tensor parallelism", gpuLimit, gpusPerPod)))
+		}
+		gpuLimit = strconv.Itoa(gpusPerPod)
+	} else {
+		parsed, err := strconv.Atoi(gpuLimit)
+		if err != nil || parsed < 1 {
+			errs = append(errs, field.Invalid(field.NewPath("spec", "resources", "limits").Key("nvidia.com/gpu"), gpuLimit, "invalid gpu-limit, multi-node NIMServices need a whole number of GPUs per pod"))
+		}
+		gpusPerPod = parsed
+	}
+	if len(errs) > 0 {
+		return "", errs
+	}
+
+	nimservice.Spec.MultiNode = &appsv1alpha1.NimServiceMultiNodeConfig{
+		BackendType: appsv1alpha1.NIMBackendType(backend),
+		Size:        size,
+		GPUSPerPod:  gpusPerPod,
+	}
+	if options.MPIStartTimeout > 0 {
+		nimservice.Spec.MultiNode.MPI = &appsv1alpha1.MultiNodeMPIConfig{MPIStartTimeout: options.MPIStartTimeout}
+	}
+	return gpuLimit, nil
+}
@@ -2,6 +2,7 @@ package create
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 	corev1 "k8s.io/api/core/v1"
 
@@ -14,6 +15,8 @@ import (
 	"context"
 	util "k8s-nim-operator-cli/pkg/util"
 	"k8s-nim-operator-cli/pkg/util/client"
+	nimconfig "k8s-nim-operator-cli/pkg/util/config"
+	"k8s-nim-operator-cli/pkg/util/validation"
 
 	"strconv"
 
@@ -57,12 +60,27 @@ type NIMCacheOptions struct {
 	ModelName           string
 	DatasetName         string
 	Revision            string
+	// Caching job settings.
+	NodeSelector map[string]string
+	Tolerations  []string
+	Env          []string
+	ProxyHTTP    string
+	ProxyHTTPS   string
+	NoProxy      string
+	CAConfigMap  string
+	UserID       int64
+	GroupID      int64
+	Labels       map[string]string
+	Annotations  map[string]string
+	Interactive  bool
 }
 
 func NewNIMCacheOptions(cmdFactory cmdutil.Factory, streams genericclioptions.IOStreams) *NIMCacheOptions {
 	return &NIMCacheOptions{
 		cmdFactory: cmdFactory,
 		IoStreams:  &streams,
+		UserID:     util.UserID,
+		GroupID:    util.GroupID,
 	}
 }
 
@@ -89,7 +107,8 @@ func NewCreateNIMCacheCommand(cmdFactory cmdutil.Factory, streams genericcliopti
 		Use: "nimcache [NAME]",
 		Short: "Create new NIMCache with specified information",
 		Long: `Create new NIMCache with specified parameters.
-Must specify --nim-source and storage: reference an existing/create new PVC.`,
+Must specify --nim-source and storage: reference an existing/create new PVC.
+With --interactive, the command asks for these instead, listing the cluster's PVCs, StorageClasses, secrets and GPU products, and shows the NIMCache before creating it.`,
 		SilenceUsage: true,
 		// ValidArgsFunction: completion.RayClusterCompletionFunc(cmdFactory),
 		Args: cobra.MaximumNArgs(1),
@@ -98,8 +117,11 @@ Must specify --nim-source and storage: reference an existing/create new PVC.`,
 				cmd.HelpFunc()(cmd, args)
 				return nil
 			} else {
-				if err := Validate(options); err != nil {
-					return err
+				// The wizard asks for the source, so it is validated after prompting.
+				if !options.Interactive {
+					if err := Validate(options); err != nil {
+						return err
+					}
 				}
 				if err := options.CompleteNamespace(args, cmd); err != nil {
 					return err
@@ -119,6 +141,8 @@ Must specify --nim-source and storage: reference an existing/create new PVC.`,
 		"  kl nim create nimcache my-nimcache --nim-source=ngc --model-puller=nvcr.io/nim/meta/llama-3.1-8b-instruct:1.3.3 --pull-secret=ngc-secret --auth-secret=ngc-api-secret --engine=tensorrt_llm --tensorParallelism=1 --pvc-storage-name=nim-pvc",
 		"",
 		"  kl nim create nimcache my-nimcache  --alt-endpoint=<hf-endpoint> --alt-namespace=main --auth-secret=<hf-secret> model-puller=<model-puller> --pull-secret=<hf-pullsecret> --pvc-create=true --pvc-size=20Gi --pvc-volume-access-mode=ReadWriteMany --pvc-storage-class=<storage-class-name>",
+		"",
+		"  kl nim create nimcache my-nimcache --interactive",
 	  }, "\n")
 
 	// The first argument will be name. Other arguments will be specified as flags.
@@ -147,16 +171,35 @@ Must specify --nim-source and storage: reference an existing/create new PVC.`,
 	cmd.Flags().StringVar(&options.PVCStorageName, "pvc-storage-name", util.PVCStorageName, "PVC name to use for storage. Can be used to specify existing PVC as well as creating new PVC")
 	cmd.Flags().StringVar(&options.PVCVolumeAccessMode, "pvc-volume-access-mode", util.PVCVolumeAccessMode, "Volume access mode for PVC creation. Must provide if creating new PVC.")
 	cmd.Flags().StringVar(&options.PVCSize, "pvc-size", util.PVCSize, "Size for PVC creation. Must provide if creating new PVC.")
-	cmd.Flags().StringVar(&options.PVCStorageClass, "pvc-storage-class", util.PVCStorageClass, "Storage class for PVC creation. Optional.")
+	cmd.Flags().StringVar(&options.PVCStorageClass, "pvc-storage-class", util.PVCStorageClass, "Storage class for PVC creation. Must provide if creating new PVC.")
 	cmd.Flags().StringVar(&options.AuthSecret, "auth-secret", util.AuthSecret, "Auth secret to use for accessing NGC/HF/NemoDataStore.")
 
+	// Caching job flags.
+	cmd.Flags().StringToStringVar(&options.NodeSelector, "node-selector", nil, "Comma-separated node labels the caching job must be scheduled on, e.g. nvidia.com/gpu.product=NVIDIA-H100-80GB-HBM3.")
+	cmd.Flags().StringArrayVar(&options.Tolerations, "toleration", nil, "Toleration for the caching job as KEY[=VALUE][:EFFECT], e.g. nvidia.com/gpu:NoSchedule. Can be repeated.")
+	cmd.Flags().StringArrayVar(&options.Env, "env", nil, "Environment variable for the caching job as KEY=VALUE. Can be repeated.")
+	cmd.Flags().StringVar(&options.ProxyHTTP, "proxy-http", util.ProxyHTTP, "HTTP proxy the caching job downloads through, e.g. http://proxy.example.com:3128.")
+	cmd.Flags().StringVar(&options.ProxyHTTPS, "proxy-https", util.ProxyHTTPS, "HTTPS proxy the caching job downloads through.")
+	cmd.Flags().StringVar(&options.NoProxy, "no-proxy", util.NoProxy, "Comma-separated hosts and domains the caching job reaches without the proxy.")
+	cmd.Flags().StringVar(&options.CAConfigMap, "ca-configmap", util.CAConfigMap, "ConfigMap with custom CA certificates, e.g. a corporate CA bundle, added to the caching job's trust store.")
+	cmd.Flags().Int64Var(&options.UserID, "user-id", util.UserID, "User ID the caching job runs as. Uses the operator default when unset.")
+	cmd.Flags().Int64Var(&options.GroupID, "group-id", util.GroupID, "Group ID the caching job runs as. Uses the operator default when unset.")
+	cmd.Flags().StringToStringVar(&options.Labels, "labels", nil, "Comma-separated labels to add to the NIMCache.")
+	cmd.Flags().StringToStringVar(&options.Annotations, "annotations", nil, "Comma-separated annotations to add to the NIMCache.")
+	cmd.Flags().BoolVar(&options.Interactive, "interactive", util.Interactive, "Ask for the source, model, secrets, GPUs and storage, listing what the cluster has, then show the NIMCache and ask before creating it. Flags set the default answers.")
+
+	// Defaults that can be set per context or namespace with nim config.
+	nimconfig.Bind(cmd.Flags(), "auth-secret", nimconfig.AuthSecret)
+	nimconfig.Bind(cmd.Flags(), "pull-secret", nimconfig.PullSecrets)
+	nimconfig.Bind(cmd.Flags(), "pvc-volume-access-mode", nimconfig.PVCVolumeAccessMode)
+	nimconfig.Bind(cmd.Flags(), "pvc-storage-class", nimconfig.StorageClass)
+
 	return cmd
 }
 
 // Ensure the source is defined. This is because there are common fields across all three sources, making it challenging to decide which one the user intends to set.
 func Validate(options *NIMCacheOptions) error {
 	if strings.ToLower(options.SourceConfiguration) != "ngc" && strings.ToLower(options.SourceConfiguration) != "huggingface" && strings.ToLower(options.SourceConfiguration) != "nemodatastore" {
-		print(options.SourceConfiguration)
 		return fmt.Errorf("--nim-source must be set to one of 'ngc', 'huggingface', 'nemodatastore'. is %q", options.SourceConfiguration)
 	}
 	return nil
@@ -165,6 +208,17 @@ func Validate(options *NIMCacheOptions) error {
 // Will need different Run commands for NewCreateNIMCacheCommand and nimservice command.
 func RunCreateNIMCache(ctx context.Context, options *NIMCacheOptions, k8sClient client.Client) error {
 
+	var p *prompter
+	if options.Interactive {
+		p = newPrompter(options.IoStreams)
+		if err := promptNIMCache(ctx, p, options, k8sClient); err != nil {
+			return err
+		}
+		if err := Validate(options); err != nil {
+			return err
+		}
+	}
+
 	// Fill out NIMCache Spec.
 	nimcache, err := FillOutNIMCacheSpec(options)
 	if err != nil {
@@ -175,6 +229,22 @@ func RunCreateNIMCache(ctx context.Context, options *NIMCacheOptions, k8sClient
 	nimcache.Name = options.ResourceName
 	nimcache.Namespace = options.Namespace
 
+	// Check the whole object, so every problem is reported at once rather than by the webhook one at a time.
+	if err := validation.Error("NIMCache", nimcache.Name, validation.ValidateNIMCache(nimcache)); err != nil {
+		return err
+	}
+
+	if p != nil {
+		nimcache.APIVersion = appsv1alpha1.SchemeGroupVersion.String()
+		nimcache.Kind = "NIMCache"
+		if ok, err := p.confirmCreate(nimcache, "NIMCache", options.Namespace, options.ResourceName); err != nil || !ok {
+			if err == nil {
+				fmt.Fprintln(options.IoStreams.Out, "Not created.")
+			}
+			return err
+		}
+	}
+
 	// Create the NIMCache CR.
 	if _, err := k8sClient.NIMClient().AppsV1alpha1().NIMCaches(options.Namespace).Create(ctx, nimcache, v1.CreateOptions{}); err != nil {
 		return fmt.Errorf("failed to create NIMCache %s/%s: %w", options.Namespace, options.ResourceName, err)
@@ -283,12 +353,7 @@ func FillOutNIMCacheSpec(options *NIMCacheOptions) (*appsv1alpha1.NIMCache, erro
 	nimcache.Spec.Storage.PVC.Create = ptr.To(options.PVCCreate)
 
 	if *nimcache.Spec.Storage.PVC.Create {
-		switch options.PVCVolumeAccessMode {
-		case string(corev1.ReadWriteOnce), string(corev1.ReadOnlyMany), string(corev1.ReadWriteMany), string(corev1.ReadWriteOncePod):
-			nimcache.Spec.Storage.PVC.VolumeAccessMode = corev1.PersistentVolumeAccessMode(options.PVCVolumeAccessMode)
-		default:
-			return nil, fmt.Errorf("invalid pvc-volume-access-mode: %q", options.PVCVolumeAccessMode)
-		}
+		nimcache.Spec.Storage.PVC.VolumeAccessMode = corev1.PersistentVolumeAccessMode(options.PVCVolumeAccessMode)
 	}
 
 	if options.PVCStorageClass != "" {
@@ -299,9 +364,65 @@ func FillOutNIMCacheSpec(options *NIMCacheOptions) (*appsv1alpha1.NIMCache, erro
 		nimcache.Spec.Storage.PVC.Size = options.PVCSize
 	}
 
+	if err := fillOutNIMCacheJob(&nimcache, options); err != nil {
+		return nil, err
+	}
+
 	return &nimcache, nil
 }
 
+// Fills the settings of the caching job: scheduling, environment, proxy and security context.
+// The NIMCache spec has no labels or annotations, so those are set on the NIMCache itself.
+func fillOutNIMCacheJob(nimcache *appsv1alpha1.NIMCache, options *NIMCacheOptions) error {
+	tolerations, err := parseTolerations(options.Tolerations)
+	if err != nil {
+		return err
+	}
+	nimcache.Spec.Tolerations = tolerations
+	if len(options.NodeSelector) > 0 {
+		nimcache.Spec.NodeSelector = options.NodeSelector
+	}
+	env, err := parseEnvVars(options.Env)
+	if err != nil {
+		return err
+	}
+	nimcache.Spec.Env = env
+
+	// The operator passes the proxy to the job as HTTP_PROXY, HTTPS_PROXY and NO_PROXY, and installs the CA
+	// certificates from the ConfigMap with an init container.
+	if options.ProxyHTTP != "" || options.ProxyHTTPS != "" || options.NoProxy != "" || options.CAConfigMap != "" {
+		for _, proxy := range []struct{ flag, value string }{{"proxy-http", options.ProxyHTTP}, {"proxy-https", options.ProxyHTTPS}} {
+			if proxy.value == "" {
+				continue
+			}
+			if parsed, err := url.Parse(proxy.value); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+				return fmt.Errorf("invalid %s: %q, must be a URL such as http://proxy.example.com:3128", proxy.flag, proxy.value)
+			}
+		}
+		nimcache.Spec.Proxy = &appsv1alpha1.ProxySpec{
+			HttpProxy:     options.ProxyHTTP,
+			HttpsProxy:    options.ProxyHTTPS,
+			NoProxy:       options.NoProxy,
+			CertConfigMap: options.CAConfigMap,
+		}
+	}
+
+	// Negative IDs mean unset, leaving the operator's default in place.
+	if options.UserID >= 0 {
+		nimcache.Spec.UserID = ptr.To(options.UserID)
+	}
+	if options.GroupID >= 0 {
+		nimcache.Spec.GroupID = ptr.To(options.GroupID)
+	}
+	if len(options.Labels) > 0 {
+		nimcache.Labels = options.Labels
+	}
+	if len(options.Annotations) > 0 {
+		nimcache.Annotations = options.Annotations
+	}
+	return nil
+}
+
 func fillOutDSHF(nimcache *appsv1alpha1.NIMCache, options *NIMCacheOptions) {
 	if options.SourceConfiguration == "huggingface" {
 		if options.ModelName != "" {
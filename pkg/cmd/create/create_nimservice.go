@@ -8,12 +8,16 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 
 	"context"
 	util "k8s-nim-operator-cli/pkg/util"
 	"k8s-nim-operator-cli/pkg/util/client"
+	nimconfig "k8s-nim-operator-cli/pkg/util/config"
+	"k8s-nim-operator-cli/pkg/util/validation"
 
 	"k8s.io/utils/ptr"
 
@@ -47,12 +51,50 @@ type NIMServiceOptions struct {
 	ScaleMinReplicas       int32
 	InferencePlatform      string
 	HostPath               string
+	SharedMemorySize       string
+	Env                    []string
+	EnvFromSecrets         []string
+	CPURequest             string
+	CPULimit               string
+	MemoryRequest          string
+	MemoryLimit            string
+	NodeSelector           map[string]string
+	Tolerations            []string
+	Labels                 map[string]string
+	Annotations            map[string]string
+	RuntimeClass           string
+	UserID                 int64
+	GroupID                int64
+	ExposeVia              string
+	IngressHost            string
+	IngressClass           string
+	IngressTLSSecret       string
+	IngressPath            string
+	Gateway                string
+	Metrics                bool
+	ServiceMonitorInterval string
+	ServiceMonitorLabels   map[string]string
+	ScaleMetrics           []string
+	ScaleCPUUtilization    int32
+	MultiNodeSize          int
+	PipelineParallelism    int
+	GPUsPerPod             int
+	MultiNodeBackend       string
+	MPIStartTimeout        int
+	FromNIMCache           string
+	Interactive            bool
+	// The --gpu-limit default, which may come from the config. A GPU limit equal to it was not set on the command line.
+	DefaultGPULimit        string
+	// The --pvc-storage-class default, which may come from the config. It only applies to a PVC created with --pvc-create.
+	DefaultStorageClass    string
 }
 
 func NewNIMServiceOptions(cmdFactory cmdutil.Factory, streams genericclioptions.IOStreams) *NIMServiceOptions {
 	return &NIMServiceOptions{
 		cmdFactory: cmdFactory,
 		IoStreams:  &streams,
+		UserID:     util.UserID,
+		GroupID:    util.GroupID,
 	}
 }
 
@@ -68,10 +110,23 @@ func (options *NIMServiceOptions) CompleteNamespace(args []string, cmd *cobra.Co
 	}
 
 	options.ResourceName = args[0]
+	if flag := cmd.Flags().Lookup("gpu-limit"); flag != nil {
+		options.DefaultGPULimit = flag.DefValue
+	}
+	if flag := cmd.Flags().Lookup("pvc-storage-class"); flag != nil {
+		options.DefaultStorageClass = flag.DefValue
+	}
 
 	return nil
 }
 
+func (options *NIMServiceOptions) defaultGPULimit() string {
+	if options.DefaultGPULimit == "" {
+		return util.GPULimit
+	}
+	return options.DefaultGPULimit
+}
+
 func NewCreateNIMServiceCommand(cmdFactory cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
 	options := NewNIMServiceOptions(cmdFactory, streams)
 
@@ -80,9 +135,13 @@ func NewCreateNIMServiceCommand(cmdFactory cmdutil.Factory, streams genericcliop
 		Short: "Create new NIMService with specified information",
 		Long: `Create new NIMService with specified parameters. 
 
-Minimum required flags are --image-repository, --tag, and storage: reference either an existing NIMCache with --nimcache-storage-name, or reference an existing/create new PVC. 
+Minimum required flags are --image-repository, --tag, and storage: reference either an existing NIMCache with --nimcache-storage-name, a node directory with --host-path, or reference an existing/create new PVC. 
 	- If using existing PVC, minimum required flags are pvc-storage-name. 
-	- If creating new PVC, minimum required flags are pvc-create, pvc-size, pvc-volume-access-mode, pvc-storage-class.`,
+	- If creating new PVC, minimum required flags are pvc-create, pvc-size, pvc-volume-access-mode, pvc-storage-class.
+
+The NIMService CRD has no emptyDir model store, so there is no emptyDir storage option. --shared-memory-size only sizes the in-memory volume at /dev/shm and does not hold the model.
+
+With --interactive, the command asks for these instead, listing the cluster's NIMCaches, PVCs, StorageClasses, secrets and GPU products, and shows the NIMService before creating it.`,
 		SilenceUsage: true,
 		// ValidArgsFunction: completion.RayClusterCompletionFunc(cmdFactory),
 		Args: cobra.MaximumNArgs(1),
@@ -114,18 +173,41 @@ Minimum required flags are --image-repository, --tag, and storage: reference eit
 		"",
 		"  Creating NIMService with existing NIMCache as storage.",
 		"    kl nim create nimservice llama3-nimservice --image-repository=nvcr.io/nim/meta/llama-3.1-8b-instruct --tag=1.3.3 --nimcache-storage-name=<nimcache-name>",
+		"",
+		"  Creating NIMService with environment, resources and scheduling constraints.",
+		"    kl nim create nimservice llama3-nimservice --image-repository=nvcr.io/nim/meta/llama-3.1-8b-instruct --tag=1.3.3 --nimcache-storage-name=<nimcache-name> --env=NIM_MAX_MODEL_LEN=8192 --env-from-secret=HF_TOKEN=hf-api-secret --cpu-request=4 --memory-limit=32Gi --node-selector=nvidia.com/gpu.product=NVIDIA-H100-80GB-HBM3 --toleration=nvidia.com/gpu:NoSchedule",
+		"",
+		"  Creating NIMService exposed through an Ingress with TLS.",
+		"    kl nim create nimservice llama3-nimservice --image-repository=nvcr.io/nim/meta/llama-3.1-8b-instruct --tag=1.3.3 --nimcache-storage-name=<nimcache-name> --ingress-host=llama3.example.com --ingress-class=nginx --ingress-tls-secret=llama3-tls",
+		"",
+		"  Creating NIMService exposed through a Gateway API HTTPRoute or an OpenShift Route.",
+		"    kl nim create nimservice llama3-nimservice --image-repository=nvcr.io/nim/meta/llama-3.1-8b-instruct --tag=1.3.3 --nimcache-storage-name=<nimcache-name> --expose-via=httproute --gateway=gateways/public --ingress-host=llama3.example.com",
+		"    kl nim create nimservice llama3-nimservice --image-repository=nvcr.io/nim/meta/llama-3.1-8b-instruct --tag=1.3.3 --nimcache-storage-name=<nimcache-name> --expose-via=route",
+		"",
+		"  Creating NIMService with a ServiceMonitor and an HPA scaling on KV cache usage.",
+		"    kl nim create nimservice llama3-nimservice --image-repository=nvcr.io/nim/meta/llama-3.1-8b-instruct --tag=1.3.3 --nimcache-storage-name=<nimcache-name> --metrics --service-monitor-interval=30s --service-monitor-labels=release=prometheus --scale-max-replicas=4 --scale-metric=gpu_cache_usage_perc=0.75",
+		"",
+		"  Creating NIMService from a Ready NIMCache, taking the image from its model puller and the GPU limit from the profile.",
+		"    kl nim create nimservice llama3-nimservice --from-nimcache=<nimcache-name> --nimcache-storage-profile=<profile-id>",
+		"",
+		"  Creating NIMService by answering questions, picking PVCs, StorageClasses, secrets and GPUs from the cluster.",
+		"    kl nim create nimservice llama3-nimservice --interactive",
 	  }, "\n")
 
 	// The first argument will be name. Other arguments will be specified as flags.
-	cmd.Flags().StringVar(&options.ImageRepository, "image-repository", util.ImageRepository, "Repository to pull image from. Required")
-	cmd.Flags().StringVar(&options.Tag, "tag", util.Tag, "Image tag. Required")
+	cmd.Flags().StringVar(&options.ImageRepository, "image-repository", util.ImageRepository, "Repository to pull image from. Required unless --from-nimcache is set")
+	cmd.Flags().StringVar(&options.Tag, "tag", util.Tag, "Image tag. Required unless --from-nimcache is set")
 	cmd.Flags().StringVar(&options.NIMCacheStorageName, "nimcache-storage-name", util.NIMCacheStorageName, "Nimcache name to use for storage.")
+	cmd.Flags().BoolVar(&options.Interactive, "interactive", util.Interactive, "Ask for the storage, image, secrets and GPUs, listing what the cluster has, then show the NIMService and ask before creating it. Flags set the default answers.")
+	cmd.Flags().StringVar(&options.FromNIMCache, "from-nimcache", util.FromNIMCache, "Ready NIMCache to serve from. Uses it as storage, its NGC model puller as the image unless --image-repository and --tag are set, and the profile's tensor parallelism as the GPU limit.")
 	cmd.Flags().StringVar(&options.NIMCacheStorageProfile, "nimcache-storage-profile", util.NIMCacheStorageProfile, "Nimcache profile to use for storage.")
 	cmd.Flags().BoolVar(&options.PVCCreate, "pvc-create", util.PVCCreate, "Specify as true to create a new PVC. Default is false.")
 	cmd.Flags().StringVar(&options.PVCStorageName, "pvc-storage-name", util.PVCStorageName, "PVC name to use for storage. Can be used to specify existing PVC as well as creating new PVC")
 	cmd.Flags().StringVar(&options.PVCVolumeAccessMode, "pvc-volume-access-mode", util.PVCVolumeAccessMode, "Volume access mode for PVC creation. Must provide if creating new PVC.")
 	cmd.Flags().StringVar(&options.PVCSize, "pvc-size", util.PVCSize, "Size for PVC creation. Must provide if creating new PVC.")
-	cmd.Flags().StringVar(&options.PVCStorageClass, "pvc-storage-class", util.PVCStorageClass, "Storage class for PVC creation.")
+	cmd.Flags().StringVar(&options.PVCStorageClass, "pvc-storage-class", util.PVCStorageClass, "Storage class for PVC creation. Must provide if creating new PVC.")
+	cmd.Flags().StringVar(&options.HostPath, "host-path", util.HostPath, "Absolute path on the node to cache the model in, instead of a NIMCache or PVC.")
+	cmd.Flags().StringVar(&options.SharedMemorySize, "shared-memory-size", util.SharedMemorySize, "Size limit of the in-memory volume mounted at /dev/shm, e.g. 16Gi. Uses the operator default when unset.")
 	cmd.Flags().StringVar(&options.PullPolicy, "pull-policy", util.PullPolicy, "Pull policy to use while pulling image.")
 	cmd.Flags().StringVar(&options.AuthSecret, "auth-secret", util.AuthSecret, "Auth secret to use for accessing NGC.")
 	cmd.Flags().StringSliceVar(&options.PullSecrets, "pull-secrets", util.PullSecrets, "Comma-separated list of image pull secrets.")
@@ -135,9 +217,44 @@ Minimum required flags are --image-repository, --tag, and storage: reference eit
 	cmd.Flags().StringVar(&options.GPULimit, "gpu-limit", util.GPULimit, "Maximum number of GPUs the NIMService can use.")
 	cmd.Flags().Int32Var(&options.ScaleMaxReplicas, "scale-max-replicas", util.ScaleMaxReplicas, "Maximum number of replicas for the NIMService's HorizontalPodAutoscaler.")
 	cmd.Flags().Int32Var(&options.ScaleMinReplicas, "scale-min-replicas", util.ScaleMinReplicas, "Minimum number of replicas for the NIMService's HorizontalPodAutoscaler.")
+	cmd.Flags().StringArrayVar(&options.ScaleMetrics, "scale-metric", nil, "Per-pod custom metric target for the HorizontalPodAutoscaler as METRIC=TARGET, e.g. gpu_cache_usage_perc=0.75 or num_requests_running=10. Needs a custom metrics adapter. Can be repeated.")
+	cmd.Flags().Int32Var(&options.ScaleCPUUtilization, "scale-cpu-utilization", util.ScaleCPUUtilization, "Target average CPU utilization in percent for the HorizontalPodAutoscaler.")
+	cmd.Flags().BoolVar(&options.Metrics, "metrics", util.Metrics, "Enable metrics collection and create a ServiceMonitor for the Prometheus Operator.")
+	cmd.Flags().StringVar(&options.ServiceMonitorInterval, "service-monitor-interval", util.ServiceMonitorInterval, "Scrape interval of the ServiceMonitor, e.g. 1m. Implies --metrics. Defaults to 30s.")
+	cmd.Flags().StringToStringVar(&options.ServiceMonitorLabels, "service-monitor-labels", nil, "Comma-separated labels to add to the ServiceMonitor so a Prometheus instance selects it, e.g. release=prometheus. Implies --metrics.")
 	cmd.Flags().StringVar(&options.InferencePlatform, "inference-platform", util.InferencePlatform, "Inference platform to use for this service. Valid values are 'standalone' (default) and 'kserve.'")
-
-	// add CPU/Memory resource limits?
+	cmd.Flags().StringArrayVar(&options.Env, "env", nil, "Environment variable for the NIM container as KEY=VALUE. Can be repeated.")
+	cmd.Flags().StringArrayVar(&options.EnvFromSecrets, "env-from-secret", nil, "Environment variable read from a secret as KEY=SECRET[:SECRET_KEY]. SECRET_KEY defaults to KEY. Can be repeated.")
+	cmd.Flags().StringVar(&options.CPURequest, "cpu-request", util.CPURequest, "CPU request for the NIM container, e.g. 4 or 500m.")
+	cmd.Flags().StringVar(&options.CPULimit, "cpu-limit", util.CPULimit, "CPU limit for the NIM container.")
+	cmd.Flags().StringVar(&options.MemoryRequest, "memory-request", util.MemoryRequest, "Memory request for the NIM container, e.g. 16Gi.")
+	cmd.Flags().StringVar(&options.MemoryLimit, "memory-limit", util.MemoryLimit, "Memory limit for the NIM container.")
+	cmd.Flags().StringToStringVar(&options.NodeSelector, "node-selector", nil, "Comma-separated node labels the NIMService pods must be scheduled on, e.g. nvidia.com/gpu.product=NVIDIA-H100-80GB-HBM3.")
+	cmd.Flags().StringArrayVar(&options.Tolerations, "toleration", nil, "Toleration for the NIMService pods as KEY[=VALUE][:EFFECT], e.g. nvidia.com/gpu:NoSchedule. Can be repeated.")
+	cmd.Flags().StringToStringVar(&options.Labels, "labels", nil, "Comma-separated labels to add to the NIMService's pods and resources, e.g. team=ml,env=prod.")
+	cmd.Flags().StringToStringVar(&options.Annotations, "annotations", nil, "Comma-separated annotations to add to the NIMService's pods and resources.")
+	cmd.Flags().StringVar(&options.RuntimeClass, "runtime-class", util.RuntimeClass, "RuntimeClass for the NIMService pods, e.g. nvidia.")
+	cmd.Flags().Int64Var(&options.UserID, "user-id", util.UserID, "User ID the NIM container runs as. Uses the operator default when unset.")
+	cmd.Flags().Int64Var(&options.GroupID, "group-id", util.GroupID, "Group ID the NIM container runs as. Uses the operator default when unset.")
+	cmd.Flags().StringVar(&options.ExposeVia, "expose-via", util.ExposeVia, "How to expose the NIMService outside the cluster: 'ingress', 'httproute' (Gateway API) or 'route' (OpenShift). Defaults to 'ingress' when --ingress-host is set.")
+	cmd.Flags().StringVar(&options.IngressHost, "ingress-host", util.IngressHost, "Host name the NIMService is reachable at. Required for Ingresses; optional for HTTPRoutes and OpenShift Routes.")
+	cmd.Flags().StringVar(&options.IngressClass, "ingress-class", util.IngressClass, "IngressClass of the Ingress, e.g. nginx. Uses the cluster default when unset.")
+	cmd.Flags().StringVar(&options.IngressTLSSecret, "ingress-tls-secret", util.IngressTLSSecret, "kubernetes.io/tls secret with the certificate for --ingress-host. Enables TLS on the Ingress or OpenShift Route. Routes reference the secret through spec.tls.externalCertificate; where the cluster does not support that, the certificate and private key are copied into the Route.")
+	cmd.Flags().StringVar(&options.IngressPath, "ingress-path", util.IngressPath, "Path prefix routed to the NIMService.")
+	cmd.Flags().StringVar(&options.Gateway, "gateway", util.Gateway, "Gateway the HTTPRoute attaches to, as NAME or NAMESPACE/NAME. Required with --expose-via=httproute.")
+	cmd.Flags().IntVar(&options.MultiNodeSize, "multi-node-size", util.MultiNodeSize, "Number of pods, usually one per node, that serve each replica as a LeaderWorkerSet. Enables multi-node deployment.")
+	cmd.Flags().IntVar(&options.PipelineParallelism, "pipeline-parallelism", util.PipelineParallelism, "Pipeline parallel size of a multi-node NIMService. Each pod runs one stage, so this is the same as --multi-node-size.")
+	cmd.Flags().IntVar(&options.GPUsPerPod, "gpus-per-pod", util.GPUsPerPod, "GPUs of each multi-node pod, which sets the tensor parallel size. Defaults to --gpu-limit.")
+	cmd.Flags().StringVar(&options.MultiNodeBackend, "multi-node-backend", util.MultiNodeBackend, "Backend running multi-node NIMServices. Only 'lws' (LeaderWorkerSet) is supported.")
+	cmd.Flags().IntVar(&options.MPIStartTimeout, "mpi-start-timeout", util.MPIStartTimeout, "Seconds to wait for the MPI cluster of a multi-node NIMService to start. Uses the operator default when unset.")
+
+	// Defaults that can be set per context or namespace with nim config.
+	nimconfig.Bind(cmd.Flags(), "auth-secret", nimconfig.AuthSecret)
+	nimconfig.Bind(cmd.Flags(), "pull-secrets", nimconfig.PullSecrets)
+	nimconfig.Bind(cmd.Flags(), "pvc-volume-access-mode", nimconfig.PVCVolumeAccessMode)
+	nimconfig.Bind(cmd.Flags(), "pvc-storage-class", nimconfig.StorageClass)
+	nimconfig.Bind(cmd.Flags(), "service-type", nimconfig.ServiceType)
+	nimconfig.Bind(cmd.Flags(), "gpu-limit", nimconfig.GPULimit)
 
 	return cmd
 }
@@ -145,6 +262,20 @@ Minimum required flags are --image-repository, --tag, and storage: reference eit
 // Will need different Run commands for NewCreateNIMCacheCommand and nimservice command.
 func RunCreateNIMService(ctx context.Context, options *NIMServiceOptions, k8sClient client.Client) error {
 
+	var p *prompter
+	if options.Interactive {
+		p = newPrompter(options.IoStreams)
+		if err := promptNIMService(ctx, p, options, k8sClient); err != nil {
+			return err
+		}
+	}
+
+	if options.FromNIMCache != "" {
+		if err := completeFromNIMCache(ctx, options, k8sClient); err != nil {
+			return err
+		}
+	}
+
 	// Fill out NIMService Spec.
 	nimservice, err := FillOutNIMServiceSpec(options)
 	if err != nil {
@@ -155,12 +286,44 @@ func RunCreateNIMService(ctx context.Context, options *NIMServiceOptions, k8sCli
 	nimservice.Name = options.ResourceName
 	nimservice.Namespace = options.Namespace
 
+	if p != nil {
+		nimservice.APIVersion = appsv1alpha1.SchemeGroupVersion.String()
+		nimservice.Kind = "NIMService"
+		if ok, err := p.confirmCreate(nimservice, "NIMService", options.Namespace, options.ResourceName); err != nil || !ok {
+			if err == nil {
+				fmt.Fprintln(options.IoStreams.Out, "Not created.")
+			}
+			return err
+		}
+	}
+
+	// OpenShift Routes are not managed by the operator, so the CLI creates one next to the NIMService. The client is set
+	// up first so that a missing cluster connection does not leave a NIMService without its Route.
+	var dynamicClient dynamic.Interface
+	if options.ExposeVia == ExposeRoute {
+		if options.cmdFactory == nil {
+			return fmt.Errorf("cannot create the OpenShift Route without a cluster connection")
+		}
+		dynamicClient, err = options.cmdFactory.DynamicClient()
+		if err != nil {
+			return fmt.Errorf("failed to create dynamic client: %w", err)
+		}
+	}
+
 	// Create the NIMService CR.
-	if _, err := k8sClient.NIMClient().AppsV1alpha1().NIMServices(options.Namespace).Create(ctx, nimservice, v1.CreateOptions{}); err != nil {
+	created, err := k8sClient.NIMClient().AppsV1alpha1().NIMServices(options.Namespace).Create(ctx, nimservice, v1.CreateOptions{})
+	if err != nil {
 		return fmt.Errorf("failed to create NIMService %s/%s: %w", options.Namespace, options.ResourceName, err)
 	}
 
 	fmt.Fprintf(options.IoStreams.Out, "NIMService %q created in namespace %q\n", options.ResourceName, options.Namespace)
+	if nimservice.Spec.Scale.Enabled != nil && *nimservice.Spec.Scale.Enabled && len(nimservice.Spec.Scale.HPA.Metrics) == 0 {
+		fmt.Fprintln(options.IoStreams.ErrOut, "Warning: the HorizontalPodAutoscaler has no metrics; set --scale-metric or --scale-cpu-utilization so it can scale.")
+	}
+
+	if dynamicClient != nil {
+		return exposeViaRoute(ctx, dynamicClient, k8sClient, options, created)
+	}
 	return nil
 }
 
@@ -173,63 +336,92 @@ func FillOutNIMServiceSpec(options *NIMServiceOptions) (*appsv1alpha1.NIMService
 	nimservice.Spec.Image.Repository = options.ImageRepository
 	nimservice.Spec.Image.Tag = options.Tag
 
-	// Complete Storage. Webhooks will do validation.
-	if options.HostPath != "" {
-		nimservice.Spec.Storage.HostPath = ptr.To(options.HostPath)
+	// Complete Storage. Errors in the flags are collected and reported together with the spec's below.
+	errs := fillOutStorage(&nimservice, options)
+
+	nimservice.Spec.AuthSecret = options.AuthSecret
+	nimservice.Spec.Image.PullSecrets = options.PullSecrets
+	nimservice.Spec.Image.PullPolicy = options.PullPolicy
+	nimservice.Spec.Expose.Service.Port = ptr.To(options.ServicePort)
+
+	nimservice.Spec.Expose.Service.Type = corev1.ServiceType(options.ServiceType)
+
+	errs = append(errs, fillOutExpose(&nimservice, options)...)
+
+	resourcesPath := field.NewPath("spec", "resources")
+	requirements := &corev1.ResourceRequirements{}
+	gpuLimit, multiNodeErrs := fillOutMultiNode(&nimservice, options)
+	errs = append(errs, multiNodeErrs...)
+	if len(multiNodeErrs) == 0 {
+		parsedLimit, err := resource.ParseQuantity(gpuLimit)
+		if err != nil {
+			errs = append(errs, field.Invalid(resourcesPath.Child("limits").Key("nvidia.com/gpu"), gpuLimit, "invalid --gpu-limit: "+err.Error()))
+		} else {
+			requirements.Limits = corev1.ResourceList{corev1.ResourceName("nvidia.com/gpu"): parsedLimit}
+		}
 	}
-	if options.NIMCacheStorageName != "" {
-		nimservice.Spec.Storage.NIMCache.Name = options.NIMCacheStorageName
-		if options.NIMCacheStorageProfile != "" {
-			nimservice.Spec.Storage.NIMCache.Profile = options.NIMCacheStorageProfile
+	for _, quantity := range []struct {
+		list *corev1.ResourceList
+		path *field.Path
+		name corev1.ResourceName
+		value, flag string
+	}{
+		{&requirements.Requests, resourcesPath.Child("requests"), corev1.ResourceCPU, options.CPURequest, "cpu-request"},
+		{&requirements.Limits, resourcesPath.Child("limits"), corev1.ResourceCPU, options.CPULimit, "cpu-limit"},
+		{&requirements.Requests, resourcesPath.Child("requests"), corev1.ResourceMemory, options.MemoryRequest, "memory-request"},
+		{&requirements.Limits, resourcesPath.Child("limits"), corev1.ResourceMemory, options.MemoryLimit, "memory-limit"},
+	} {
+		list, err := addQuantity(*quantity.list, quantity.name, quantity.value, quantity.flag)
+		if err != nil {
+			errs = append(errs, field.Invalid(quantity.path.Key(string(quantity.name)), quantity.value, err.Error()))
+			continue
 		}
+		*quantity.list = list
 	}
+	nimservice.Spec.Resources = requirements
 
-	if options.PVCStorageName != "" {
-		nimservice.Spec.Storage.PVC.Name = options.PVCStorageName
+	// Plain values come first, so a secret reference for the same key is what the container sees.
+	env, err := parseEnvVars(options.Env)
+	if err != nil {
+		errs = append(errs, field.Invalid(field.NewPath("spec", "env"), options.Env, err.Error()))
 	}
-	nimservice.Spec.Storage.PVC.Create = ptr.To(options.PVCCreate)
-
-	switch options.PVCVolumeAccessMode {
-	case string(corev1.ReadWriteOnce), string(corev1.ReadOnlyMany), string(corev1.ReadWriteMany), string(corev1.ReadWriteOncePod):
-		nimservice.Spec.Storage.PVC.VolumeAccessMode = corev1.PersistentVolumeAccessMode(options.PVCVolumeAccessMode)
-	default:
-		return nil, fmt.Errorf("invalid pvc-volume-access-mode: %q", options.PVCVolumeAccessMode)
+	secretEnv, err := parseEnvFromSecrets(options.EnvFromSecrets)
+	if err != nil {
+		errs = append(errs, field.Invalid(field.NewPath("spec", "env"), options.EnvFromSecrets, err.Error()))
 	}
+	nimservice.Spec.Env = append(env, secretEnv...)
 
-	if options.PVCStorageClass != "" {
-		nimservice.Spec.Storage.PVC.StorageClass = options.PVCStorageClass
+	tolerations, err := parseTolerations(options.Tolerations)
+	if err != nil {
+		errs = append(errs, field.Invalid(field.NewPath("spec", "tolerations"), options.Tolerations, err.Error()))
 	}
-
-	if options.PVCSize != "" {
-		nimservice.Spec.Storage.PVC.Size = options.PVCSize
+	nimservice.Spec.Tolerations = tolerations
+	if len(options.NodeSelector) > 0 {
+		nimservice.Spec.NodeSelector = options.NodeSelector
 	}
-
-	nimservice.Spec.AuthSecret = options.AuthSecret
-	nimservice.Spec.Image.PullSecrets = options.PullSecrets
-	nimservice.Spec.Image.PullPolicy = options.PullPolicy
-	nimservice.Spec.Expose.Service.Port = ptr.To(options.ServicePort)
-
-	switch options.ServiceType {
-	case string(corev1.ServiceTypeClusterIP), string(corev1.ServiceTypeNodePort), string(corev1.ServiceTypeLoadBalancer):
-		nimservice.Spec.Expose.Service.Type = corev1.ServiceType(options.ServiceType)
-	default:
-		return nil, fmt.Errorf("invalid service-type: %q", options.ServiceType)
+	if len(options.Labels) > 0 {
+		nimservice.Spec.Labels = options.Labels
 	}
-
-	parsedLimit, err := resource.ParseQuantity(options.GPULimit)
-	if err != nil {
-		return nil, err
+	if len(options.Annotations) > 0 {
+		nimservice.Spec.Annotations = options.Annotations
 	}
-	requirements := &corev1.ResourceRequirements{
-		Limits: corev1.ResourceList{
-			corev1.ResourceName("nvidia.com/gpu"): parsedLimit,
-		},
+	nimservice.Spec.RuntimeClassName = options.RuntimeClass
+	// Negative IDs mean unset, leaving the operator's default in place.
+	if options.UserID >= 0 {
+		nimservice.Spec.UserID = ptr.To(options.UserID)
+	}
+	if options.GroupID >= 0 {
+		nimservice.Spec.GroupID = ptr.To(options.GroupID)
 	}
-	nimservice.Spec.Resources = requirements
 
 	nimservice.Spec.Replicas = options.Replicas
 
 	// If ScaleMaxReplicas is defined, autoscaling is enabled. ScaleMaxReplicas not being defined but ScaleMaxReplicas being defined will be taken care of by apiserver.
+	hpaPath := field.NewPath("spec", "scale", "hpa")
+	scaleMetrics, err := parseScaleMetrics(options)
+	if err != nil {
+		errs = append(errs, field.Invalid(hpaPath.Child("metrics"), options.ScaleMetrics, err.Error()))
+	}
 	if options.ScaleMaxReplicas != -1 {
 		nimservice.Spec.Scale.Enabled = ptr.To(true)
 		nimservice.Spec.Scale.HPA.MaxReplicas = int32(options.ScaleMaxReplicas)
@@ -237,14 +429,19 @@ func FillOutNIMServiceSpec(options *NIMServiceOptions) (*appsv1alpha1.NIMService
 		if options.ScaleMinReplicas != -1 {
 			nimservice.Spec.Scale.HPA.MinReplicas = ptr.To(options.ScaleMinReplicas)
 		}
+		nimservice.Spec.Scale.HPA.Metrics = scaleMetrics
+	} else if len(scaleMetrics) > 0 {
+		errs = append(errs, field.Required(hpaPath.Child("maxReplicas"), "--scale-metric and --scale-cpu-utilization require --scale-max-replicas"))
 	}
 
-	switch options.InferencePlatform {
-	case string(appsv1alpha1.PlatformTypeKServe), string(appsv1alpha1.PlatformTypeStandalone):
-		nimservice.Spec.InferencePlatform = appsv1alpha1.PlatformType(options.InferencePlatform)
-	default:
-		return nil, fmt.Errorf("invalid inference-platform: %q, must be one of 'kserve,' 'standalone'", options.InferencePlatform)
-	}
+	errs = append(errs, fillOutMetrics(&nimservice, options)...)
+
+	nimservice.Spec.InferencePlatform = appsv1alpha1.PlatformType(options.InferencePlatform)
 
+	// Enums and cross-field rules are checked on the finished spec, so every problem is reported at once.
+	errs = append(errs, validation.ValidateNIMServiceSpec(&nimservice.Spec, field.NewPath("spec"))...)
+	if err := validation.Error("NIMService", options.ResourceName, errs); err != nil {
+		return nil, err
+	}
 	return &nimservice, nil
 }
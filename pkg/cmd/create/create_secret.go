@@ -0,0 +1,294 @@
+package create
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	util "k8s-nim-operator-cli/pkg/util"
+	"k8s-nim-operator-cli/pkg/util/client"
+	nimconfig "k8s-nim-operator-cli/pkg/util/config"
+)
+
+const (
+	// Username nvcr.io expects when authenticating with an NGC API key.
+	ngcRegistryUsername = "$oauthtoken"
+	ngcAPIKeyKey        = "NGC_API_KEY"
+	hfTokenKey          = "HF_TOKEN"
+)
+
+type SecretOptions struct {
+	cmdFactory cmdutil.Factory
+	IoStreams  *genericclioptions.IOStreams
+	Namespace  string
+	// The key is read from exactly one of these sources. It is never accepted as a flag value.
+	KeyFromEnv   string
+	KeyFromFile  string
+	KeyFromStdin bool
+	// Name of the opaque secret holding the key.
+	AuthSecret string
+	// Name of the image pull secret. Only used for NGC.
+	PullSecret string
+	Registry   string
+	Overwrite  bool
+}
+
+func NewSecretOptions(cmdFactory cmdutil.Factory, streams genericclioptions.IOStreams) *SecretOptions {
+	return &SecretOptions{
+		cmdFactory: cmdFactory,
+		IoStreams:  &streams,
+	}
+}
+
+// Populates SecretOptions with the namespace.
+func (options *SecretOptions) CompleteNamespace(cmd *cobra.Command) error {
+	namespace, err := cmd.Flags().GetString("namespace")
+	if err != nil {
+		return fmt.Errorf("failed to get namespace: %w", err)
+	}
+	options.Namespace = namespace
+	if options.Namespace == "" {
+		options.Namespace = "default"
+	}
+	return nil
+}
+
+func NewCreateSecretCommand(cmdFactory cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "secret",
+		Short:        "Create the secrets NIM Operator resources expect",
+		Long:         `Create the NGC or HuggingFace secrets referenced by default by NIMServices and NIMCaches.`,
+		SilenceUsage: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) > 0 {
+				fmt.Println(fmt.Errorf("unknown command(s) %q", strings.Join(args, " ")))
+			}
+			cmd.HelpFunc()(cmd, args)
+		},
+	}
+
+	cmd.AddCommand(NewCreateNGCSecretCommand(cmdFactory, streams))
+	cmd.AddCommand(NewCreateHFSecretCommand(cmdFactory, streams))
+	return cmd
+}
+
+func NewCreateNGCSecretCommand(cmdFactory cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	options := NewSecretOptions(cmdFactory, streams)
+
+	cmd := &cobra.Command{
+		Use:   "ngc",
+		Short: "Create the NGC image pull secret and API key secret",
+		Long: `Create the kubernetes.io/dockerconfigjson pull secret for nvcr.io and the opaque secret holding NGC_API_KEY.
+
+The API key is read from an environment variable, a file or stdin; it cannot be passed as a flag value so it does not
+end up in shell history or process listings.`,
+		Example: `  nim create secret ngc --api-key-from-env=NGC_API_KEY -n nim-service
+  nim create secret ngc --api-key-from-file=./ngc-key.txt
+  cat ngc-key.txt | nim create secret ngc --api-key-stdin --overwrite`,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := options.CompleteNamespace(cmd); err != nil {
+				return err
+			}
+			k8sClient, err := client.NewClient(cmdFactory)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			return RunCreateNGCSecret(cmd.Context(), options, k8sClient)
+		},
+	}
+
+	cmd.Flags().StringVar(&options.KeyFromEnv, "api-key-from-env", "", "Name of the environment variable holding the NGC API key, e.g. NGC_API_KEY.")
+	cmd.Flags().StringVar(&options.KeyFromFile, "api-key-from-file", "", "Path of a file holding the NGC API key.")
+	cmd.Flags().BoolVar(&options.KeyFromStdin, "api-key-stdin", false, "Read the NGC API key from stdin.")
+	cmd.Flags().StringVar(&options.AuthSecret, "auth-secret", util.AuthSecret, "Name of the opaque secret holding NGC_API_KEY.")
+	cmd.Flags().StringVar(&options.PullSecret, "pull-secret", util.PullSecret, "Name of the image pull secret for the registry.")
+	cmd.Flags().StringVar(&options.Registry, "registry", util.NGCRegistry, "Registry the pull secret authenticates against.")
+	cmd.Flags().BoolVar(&options.Overwrite, "overwrite", false, "Replace the secrets if they already exist.")
+
+	nimconfig.Bind(cmd.Flags(), "auth-secret", nimconfig.AuthSecret)
+	nimconfig.Bind(cmd.Flags(), "pull-secret", nimconfig.PullSecrets)
+
+	return cmd
+}
+
+func NewCreateHFSecretCommand(cmdFactory cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	options := NewSecretOptions(cmdFactory, streams)
+
+	cmd := &cobra.Command{
+		Use:   "hf",
+		Short: "Create the HuggingFace token secret",
+		Long: `Create the opaque secret holding HF_TOKEN, used as --auth-secret for NIMCaches with --nim-source=huggingface.
+
+The token is read from an environment variable, a file or stdin; it cannot be passed as a flag value.`,
+		Example: `  nim create secret hf --token-from-env=HF_TOKEN -n nim-service
+  nim create secret hf --token-from-file=./hf-token.txt --auth-secret=my-hf-secret`,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := options.CompleteNamespace(cmd); err != nil {
+				return err
+			}
+			k8sClient, err := client.NewClient(cmdFactory)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			return RunCreateHFSecret(cmd.Context(), options, k8sClient)
+		},
+	}
+
+	cmd.Flags().StringVar(&options.KeyFromEnv, "token-from-env", "", "Name of the environment variable holding the HuggingFace token, e.g. HF_TOKEN.")
+	cmd.Flags().StringVar(&options.KeyFromFile, "token-from-file", "", "Path of a file holding the HuggingFace token.")
+	cmd.Flags().BoolVar(&options.KeyFromStdin, "token-stdin", false, "Read the HuggingFace token from stdin.")
+	cmd.Flags().StringVar(&options.AuthSecret, "auth-secret", util.HFAuthSecret, "Name of the opaque secret holding HF_TOKEN.")
+	cmd.Flags().BoolVar(&options.Overwrite, "overwrite", false, "Replace the secret if it already exists.")
+
+	return cmd
+}
+
+// Returns the key from the single configured source, with surrounding whitespace removed.
+func readKey(options *SecretOptions) (string, error) {
+	sources := 0
+	for _, set := range []bool{options.KeyFromEnv != "", options.KeyFromFile != "", options.KeyFromStdin} {
+		if set {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return "", fmt.Errorf("exactly one key source must be set: an environment variable, a file or stdin")
+	}
+
+	var key string
+	switch {
+	case options.KeyFromEnv != "":
+		value, ok := os.LookupEnv(options.KeyFromEnv)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", options.KeyFromEnv)
+		}
+		key = value
+	case options.KeyFromFile != "":
+		data, err := os.ReadFile(options.KeyFromFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read key file: %w", err)
+		}
+		key = string(data)
+	default:
+		data, err := io.ReadAll(options.IoStreams.In)
+		if err != nil {
+			return "", fmt.Errorf("failed to read key from stdin: %w", err)
+		}
+		key = string(data)
+	}
+
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return "", fmt.Errorf("the key is empty")
+	}
+	return key, nil
+}
+
+func RunCreateNGCSecret(ctx context.Context, options *SecretOptions, k8sClient client.Client) error {
+	key, err := readKey(options)
+	if err != nil {
+		return err
+	}
+
+	pullSecret, err := NewDockerConfigSecret(options.PullSecret, options.Namespace, options.Registry, ngcRegistryUsername, key)
+	if err != nil {
+		return err
+	}
+	authSecret := NewOpaqueSecret(options.AuthSecret, options.Namespace, ngcAPIKeyKey, key)
+
+	for _, secret := range []*corev1.Secret{pullSecret, authSecret} {
+		if err := applySecret(ctx, options, k8sClient, secret); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func RunCreateHFSecret(ctx context.Context, options *SecretOptions, k8sClient client.Client) error {
+	token, err := readKey(options)
+	if err != nil {
+		return err
+	}
+	return applySecret(ctx, options, k8sClient, NewOpaqueSecret(options.AuthSecret, options.Namespace, hfTokenKey, token))
+}
+
+// NewDockerConfigSecret returns a kubernetes.io/dockerconfigjson secret authenticating against registry.
+func NewDockerConfigSecret(name, namespace, registry, username, password string) (*corev1.Secret, error) {
+	config := map[string]map[string]map[string]string{
+		"auths": {
+			registry: {
+				"username": username,
+				"password": password,
+				"auth":     base64.StdEncoding.EncodeToString([]byte(username + ":" + password)),
+			},
+		},
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode docker config: %w", err)
+	}
+	return &corev1.Secret{
+		ObjectMeta: v1.ObjectMeta{Name: name, Namespace: namespace},
+		Type:       corev1.SecretTypeDockerConfigJson,
+		Data:       map[string][]byte{corev1.DockerConfigJsonKey: data},
+	}, nil
+}
+
+// NewOpaqueSecret returns an opaque secret with a single key.
+func NewOpaqueSecret(name, namespace, key, value string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: v1.ObjectMeta{Name: name, Namespace: namespace},
+		Type:       corev1.SecretTypeOpaque,
+		Data:       map[string][]byte{key: []byte(value)},
+	}
+}
+
+// Creates the secret, or replaces an existing one when --overwrite is set.
+func applySecret(ctx context.Context, options *SecretOptions, k8sClient client.Client, secret *corev1.Secret) error {
+	secrets := k8sClient.KubernetesClient().CoreV1().Secrets(options.Namespace)
+	_, err := secrets.Create(ctx, secret, v1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		if !options.Overwrite {
+			return fmt.Errorf("secret %s/%s already exists; use --overwrite to replace it", options.Namespace, secret.GetName())
+		}
+		existing, getErr := secrets.Get(ctx, secret.GetName(), v1.GetOptions{})
+		if getErr != nil {
+			return fmt.Errorf("failed to get secret %s/%s: %w", options.Namespace, secret.GetName(), getErr)
+		}
+		if existing.Type != secret.Type {
+			// The type of a secret is immutable, so it has to be recreated.
+			if err := secrets.Delete(ctx, secret.GetName(), v1.DeleteOptions{}); err != nil {
+				return fmt.Errorf("failed to delete secret %s/%s: %w", options.Namespace, secret.GetName(), err)
+			}
+			_, err = secrets.Create(ctx, secret, v1.CreateOptions{})
+		} else {
+			existing.Data = secret.Data
+			_, err = secrets.Update(ctx, existing, v1.UpdateOptions{})
+		}
+		if err != nil {
+			return fmt.Errorf("failed to replace secret %s/%s: %w", options.Namespace, secret.GetName(), err)
+		}
+		fmt.Fprintf(options.IoStreams.Out, "Secret %q replaced in namespace %q\n", secret.GetName(), options.Namespace)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create secret %s/%s: %w", options.Namespace, secret.GetName(), err)
+	}
+	fmt.Fprintf(options.IoStreams.Out, "Secret %q created in namespace %q\n", secret.GetName(), options.Namespace)
+	return nil
+}
@@ -0,0 +1,55 @@
+package create
+
+import (
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+)
+
+// Fills Spec.Storage from the storage flags. Every source that is set is filled in, so that ValidateNIMServiceSpec
+// reports a missing or ambiguous model store together with the rest of the spec's problems. The operator treats any
+// PVC field as choosing a PVC, so PVC fields are only set when a PVC flag is given. The returned errors cover what
+// the spec cannot express: an unparsable --shared-memory-size and PVC creation flags without --pvc-create.
+// The NIMService CRD has no emptyDir model store; /dev/shm, which is an emptyDir, is sized with --shared-memory-size.
+func fillOutStorage(nimservice *appsv1alpha1.NIMService, options *NIMServiceOptions) field.ErrorList {
+	errs := field.ErrorList{}
+	fldPath := field.NewPath("spec", "storage")
+	// A configured storage class is a default for new PVCs, so it neither chooses a PVC nor conflicts with another store.
+	storageClass := options.PVCStorageClass
+	if !options.PVCCreate && storageClass == options.DefaultStorageClass {
+		storageClass = ""
+	}
+
+	nimservice.Spec.Storage.NIMCache.Name = options.NIMCacheStorageName
+	nimservice.Spec.Storage.NIMCache.Profile = options.NIMCacheStorageProfile
+	if options.HostPath != "" {
+		nimservice.Spec.Storage.HostPath = ptr.To(options.HostPath)
+	}
+	if options.PVCStorageName != "" || options.PVCCreate || options.PVCSize != "" || storageClass != "" {
+		nimservice.Spec.Storage.PVC.Name = options.PVCStorageName
+		nimservice.Spec.Storage.PVC.Create = ptr.To(options.PVCCreate)
+		nimservice.Spec.Storage.PVC.VolumeAccessMode = corev1.PersistentVolumeAccessMode(options.PVCVolumeAccessMode)
+		nimservice.Spec.Storage.PVC.StorageClass = storageClass
+		nimservice.Spec.Storage.PVC.Size = options.PVCSize
+	}
+	if !options.PVCCreate {
+		if options.PVCSize != "" {
+			errs = append(errs, field.Invalid(fldPath.Child("pvc", "size"), options.PVCSize, "--pvc-size only applies with --pvc-create"))
+		}
+		if storageClass != "" {
+			errs = append(errs, field.Invalid(fldPath.Child("pvc", "storageClass"), storageClass, "--pvc-storage-class only applies with --pvc-create"))
+		}
+	}
+
+	if options.SharedMemorySize != "" {
+		size, err := resource.ParseQuantity(options.SharedMemorySize)
+		if err != nil {
+			errs = append(errs, field.Invalid(fldPath.Child("sharedMemorySizeLimit"), options.SharedMemorySize, "invalid --shared-memory-size: "+err.Error()))
+		} else {
+			nimservice.Spec.Storage.SharedMemorySizeLimit = &size
+		}
+	}
+	return errs
+}
@@ -1,11 +1,34 @@
 package create
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"k8s-nim-operator-cli/pkg/util"
+	"k8s-nim-operator-cli/pkg/util/client/fake"
+	nimconfig "k8s-nim-operator-cli/pkg/util/config"
 )
 
 // --- NIMService tests ---
@@ -84,6 +107,864 @@ func Test_FillOutNIMServiceSpec_InvalidServiceType(t *testing.T) {
 	}
 }
 
+func Test_FillOutNIMServiceSpec_EnvResourcesScheduling(t *testing.T) {
+	options := &NIMServiceOptions{
+		ImageRepository:     "repo",
+		Tag:                 "v1",
+		AuthSecret:          "ngc-api-secret",
+		PVCStorageName:      "nim-pvc",
+		PVCVolumeAccessMode: string(corev1.ReadWriteOnce),
+		ServiceType:         string(corev1.ServiceTypeClusterIP),
+		ServicePort:         8000,
+		GPULimit:            "2",
+		ScaleMaxReplicas:    -1,
+		ScaleMinReplicas:    -1,
+		InferencePlatform:   string(appsv1alpha1.PlatformTypeStandalone),
+		Env:                 []string{"NIM_MAX_MODEL_LEN=8192", "EXTRA_ARGS=--a=b"},
+		EnvFromSecrets:      []string{"HF_TOKEN=hf-api-secret", "NGC_API_KEY=ngc-api-secret:key"},
+		CPURequest:          "4",
+		CPULimit:            "8",
+		MemoryRequest:       "16Gi",
+		MemoryLimit:         "32Gi",
+		NodeSelector:        map[string]string{"nvidia.com/gpu.product": "H100"},
+		Tolerations:         []string{"nvidia.com/gpu:NoSchedule", "dedicated=nim:NoExecute"},
+		Labels:              map[string]string{"team": "ml"},
+		Annotations:         map[string]string{"owner": "ml-platform"},
+		RuntimeClass:        "nvidia",
+		UserID:              1000,
+		GroupID:             -1,
+	}
+
+	ns, err := FillOutNIMServiceSpec(options)
+	if err != nil {
+		t.Fatalf("FillOutNIMServiceSpec error: %v", err)
+	}
+
+	if len(ns.Spec.Env) != 4 || ns.Spec.Env[1].Value != "--a=b" {
+		t.Fatalf("env not set correctly: %+v", ns.Spec.Env)
+	}
+	if ref := ns.Spec.Env[2].ValueFrom.SecretKeyRef; ref.Name != "hf-api-secret" || ref.Key != "HF_TOKEN" {
+		t.Fatalf("secret key should default to the variable name: %+v", ref)
+	}
+	if ref := ns.Spec.Env[3].ValueFrom.SecretKeyRef; ref.Name != "ngc-api-secret" || ref.Key != "key" {
+		t.Fatalf("secret key not set: %+v", ref)
+	}
+	res := ns.Spec.Resources
+	if !res.Requests.Cpu().Equal(resource.MustParse("4")) || !res.Limits.Cpu().Equal(resource.MustParse("8")) ||
+		!res.Requests.Memory().Equal(resource.MustParse("16Gi")) || !res.Limits.Memory().Equal(resource.MustParse("32Gi")) ||
+		!res.Limits[corev1.ResourceName("nvidia.com/gpu")].Equal(resource.MustParse("2")) {
+		t.Fatalf("resources not set correctly: %+v", res)
+	}
+	if ns.Spec.NodeSelector["nvidia.com/gpu.product"] != "H100" || ns.Spec.Labels["team"] != "ml" || ns.Spec.Annotations["owner"] != "ml-platform" {
+		t.Fatalf("node selector, labels or annotations not set")
+	}
+	expectedTolerations := []corev1.Toleration{
+		{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+		{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "nim", Effect: corev1.TaintEffectNoExecute},
+	}
+	if len(ns.Spec.Tolerations) != 2 || ns.Spec.Tolerations[0] != expectedTolerations[0] || ns.Spec.Tolerations[1] != expectedTolerations[1] {
+		t.Fatalf("tolerations not set correctly: %+v", ns.Spec.Tolerations)
+	}
+	if ns.Spec.RuntimeClassName != "nvidia" || ns.Spec.UserID == nil || *ns.Spec.UserID != 1000 || ns.Spec.GroupID != nil {
+		t.Fatalf("runtime class or IDs not set correctly")
+	}
+}
+
+func Test_FillOutNIMServiceSpec_InvalidFlags(t *testing.T) {
+	cases := map[string]func(*NIMServiceOptions){
+		"env without value":      func(o *NIMServiceOptions) { o.Env = []string{"NOVALUE"} },
+		"env from secret":        func(o *NIMServiceOptions) { o.EnvFromSecrets = []string{"HF_TOKEN"} },
+		"toleration effect":      func(o *NIMServiceOptions) { o.Tolerations = []string{"gpu:Sometimes"} },
+		"toleration value alone": func(o *NIMServiceOptions) { o.Tolerations = []string{"=x:NoSchedule"} },
+		"memory quantity":        func(o *NIMServiceOptions) { o.MemoryLimit = "lots" },
+	}
+	for name, mutate := range cases {
+		options := &NIMServiceOptions{
+			PVCStorageName:      "nim-pvc",
+			PVCVolumeAccessMode: string(corev1.ReadWriteOnce),
+			ServiceType:         string(corev1.ServiceTypeClusterIP),
+			GPULimit:            "1",
+			InferencePlatform:   string(appsv1alpha1.PlatformTypeStandalone),
+		}
+		mutate(options)
+		if _, err := FillOutNIMServiceSpec(options); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func newExposeTestOptions() *NIMServiceOptions {
+	return &NIMServiceOptions{
+		ResourceName:        "llama3",
+		Namespace:           "nim",
+		ImageRepository:     "nvcr.io/nim/meta/llama3-8b-instruct",
+		Tag:                 "1.0.3",
+		AuthSecret:          "ngc-api-secret",
+		NIMCacheStorageName: "llama3-cache",
+		PVCVolumeAccessMode: string(corev1.ReadWriteOnce),
+		ServiceType:         string(corev1.ServiceTypeClusterIP),
+		ServicePort:         8000,
+		GPULimit:            "1",
+		ScaleMaxReplicas:    -1,
+		ScaleMinReplicas:    -1,
+		InferencePlatform:   string(appsv1alpha1.PlatformTypeStandalone),
+	}
+}
+
+func Test_FillOutNIMServiceSpec_Ingress(t *testing.T) {
+	options := newExposeTestOptions()
+	options.IngressHost = "llama3.example.com"
+	options.IngressClass = "nginx"
+	options.IngressTLSSecret = "llama3-tls"
+	options.IngressPath = "/v1"
+
+	ns, err := FillOutNIMServiceSpec(options)
+	if err != nil {
+		t.Fatalf("FillOutNIMServiceSpec error: %v", err)
+	}
+	if !ns.IsIngressEnabled() || ns.IsHTTPRouteEnabled() {
+		t.Fatalf("expected only the ingress to be enabled: %+v", ns.Spec.Expose)
+	}
+	spec := ns.Spec.Expose.Ingress.Spec
+	if spec.IngressClassName == nil || *spec.IngressClassName != "nginx" || len(spec.TLS) != 1 || spec.TLS[0].SecretName != "llama3-tls" {
+		t.Fatalf("ingress class or TLS not set: %+v", spec)
+	}
+	backend := spec.Rules[0].HTTP.Paths[0].Backend.Service
+	if backend.Name != "llama3" || backend.Port.Number != 8000 || spec.Rules[0].HTTP.Paths[0].Path != "/v1" {
+		t.Fatalf("ingress rule not set correctly: %+v", spec.Rules[0])
+	}
+	if url := util.ExternalURL(ns); url != "https://llama3.example.com/v1" {
+		t.Fatalf("unexpected external URL %q", url)
+	}
+}
+
+func Test_FillOutNIMServiceSpec_HTTPRoute(t *testing.T) {
+	options := newExposeTestOptions()
+	options.ExposeVia = ExposeHTTPRoute
+	options.Gateway = "gateways/public"
+	options.IngressHost = "llama3.example.com"
+	options.IngressPath = "/"
+
+	ns, err := FillOutNIMServiceSpec(options)
+	if err != nil {
+		t.Fatalf("FillOutNIMServiceSpec error: %v", err)
+	}
+	if !ns.IsHTTPRouteEnabled() || ns.IsIngressEnabled() {
+		t.Fatalf("expected only the HTTPRoute to be enabled: %+v", ns.Spec.Expose)
+	}
+	parent := ns.Spec.Expose.HTTPRoute.Spec.ParentRefs[0]
+	if parent.Name != "public" || parent.Namespace == nil || *parent.Namespace != "gateways" {
+		t.Fatalf("gateway parent not set correctly: %+v", parent)
+	}
+	if url := util.ExternalURL(ns); url != "http://llama3.example.com" {
+		t.Fatalf("unexpected external URL %q", url)
+	}
+}
+
+func Test_FillOutNIMServiceSpec_InvalidExpose(t *testing.T) {
+	cases := map[string]func(*NIMServiceOptions){
+		"unknown mode":          func(o *NIMServiceOptions) { o.ExposeVia = "loadbalancer" },
+		"ingress without host":  func(o *NIMServiceOptions) { o.IngressClass = "nginx" },
+		"httproute w/o gateway": func(o *NIMServiceOptions) { o.ExposeVia = ExposeHTTPRoute },
+		"httproute with tls": func(o *NIMServiceOptions) {
+			o.ExposeVia = ExposeHTTPRoute
+			o.Gateway = "gw"
+			o.IngressTLSSecret = "tls"
+		},
+		"route with class": func(o *NIMServiceOptions) { o.ExposeVia = ExposeRoute; o.IngressClass = "nginx" },
+	}
+	for name, mutate := range cases {
+		options := newExposeTestOptions()
+		mutate(options)
+		if _, err := FillOutNIMServiceSpec(options); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func Test_NewOpenShiftRoute(t *testing.T) {
+	options := newExposeTestOptions()
+	options.ExposeVia = ExposeRoute
+	options.IngressHost = "llama3.apps.example.com"
+	options.IngressTLSSecret = "llama3-tls"
+	tlsSecret := &corev1.Secret{Data: map[string][]byte{corev1.TLSCertKey: []byte("cert"), corev1.TLSPrivateKeyKey: []byte("key")}}
+
+	ns, err := FillOutNIMServiceSpec(options)
+	if err != nil {
+		t.Fatalf("FillOutNIMServiceSpec error: %v", err)
+	}
+	if ns.IsIngressEnabled() || ns.IsHTTPRouteEnabled() {
+		t.Fatalf("routes must not enable operator managed exposure: %+v", ns.Spec.Expose)
+	}
+
+	route := NewOpenShiftRoute(options, nil)
+	if name, _, _ := unstructured.NestedString(route.Object, "spec", "tls", "externalCertificate", "name"); name != "llama3-tls" {
+		t.Fatalf("expected the route to reference the TLS secret: %+v", route.Object)
+	}
+	if _, ok, _ := unstructured.NestedString(route.Object, "spec", "tls", "key"); ok {
+		t.Fatalf("the private key must not be copied when the secret is referenced: %+v", route.Object)
+	}
+
+	route = NewOpenShiftRoute(options, tlsSecret)
+	if route.GetAPIVersion() != "route.openshift.io/v1" || route.GetKind() != "Route" || route.GetName() != "llama3" || route.GetNamespace() != "nim" {
+		t.Fatalf("unexpected route metadata: %+v", route.Object)
+	}
+	spec := route.Object["spec"].(map[string]interface{})
+	if spec["host"] != "llama3.apps.example.com" || spec["to"].(map[string]interface{})["name"] != "llama3" || spec["port"].(map[string]interface{})["targetPort"] != "api" {
+		t.Fatalf("unexpected route spec: %+v", spec)
+	}
+	if tls := spec["tls"].(map[string]interface{}); tls["termination"] != "edge" || tls["certificate"] != "cert" || tls["key"] != "key" {
+		t.Fatalf("unexpected route TLS: %+v", tls)
+	}
+}
+
+func newRouteTestClients(t *testing.T, routeErr error) (*fake.Client, *dynamicfake.FakeDynamicClient, *appsv1alpha1.NIMService) {
+	t.Helper()
+	nimservice := &appsv1alpha1.NIMService{ObjectMeta: metav1.ObjectMeta{Name: "llama3", Namespace: "nim", UID: "llama3-uid"}}
+	client := fake.NewFakeClient(nil, []runtime.Object{nimservice})
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{routeGVR: "RouteList"})
+	dynamicClient.PrependReactor("create", "routes", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if routeErr != nil {
+			return true, nil, routeErr
+		}
+		// OpenShift generates a host for Routes created without one.
+		route := action.(k8stesting.CreateAction).GetObject().(*unstructured.Unstructured).DeepCopy()
+		_ = unstructured.SetNestedField(route.Object, "llama3-nim.apps.example.com", "spec", "host")
+		return true, route, nil
+	})
+	return client, dynamicClient, nimservice
+}
+
+func Test_ExposeViaRoute_RecordsURL(t *testing.T) {
+	client, dynamicClient, nimservice := newRouteTestClients(t, nil)
+	options := newExposeTestOptions()
+	options.ExposeVia = ExposeRoute
+	out := &bytes.Buffer{}
+	options.IoStreams = &genericclioptions.IOStreams{Out: out, ErrOut: &bytes.Buffer{}}
+
+	if err := exposeViaRoute(context.Background(), dynamicClient, client, options, nimservice); err != nil {
+		t.Fatalf("exposeViaRoute error: %v", err)
+	}
+	if !strings.Contains(out.String(), "NIMService will be reachable at http://llama3-nim.apps.example.com") {
+		t.Fatalf("expected the generated URL in the output:\n%s", out.String())
+	}
+	got, err := client.NIM.AppsV1alpha1().NIMServices("nim").Get(context.Background(), "llama3", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get NIMService: %v", err)
+	}
+	if url := util.ExternalURL(got); url != "http://llama3-nim.apps.example.com" {
+		t.Fatalf("expected nim get to show the Route URL, got %q", url)
+	}
+}
+
+func Test_ExposeViaRoute_CopiesCertificateWithoutExternalCertificate(t *testing.T) {
+	client, dynamicClient, nimservice := newRouteTestClients(t, nil)
+	tlsSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "llama3-tls", Namespace: "nim"},
+		Data:       map[string][]byte{corev1.TLSCertKey: []byte("cert"), corev1.TLSPrivateKeyKey: []byte("key")},
+	}
+	if err := client.Kube.Tracker().Add(tlsSecret); err != nil {
+		t.Fatal(err)
+	}
+	var created []*unstructured.Unstructured
+	dynamicClient.PrependReactor("create", "routes", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		route := action.(k8stesting.CreateAction).GetObject().(*unstructured.Unstructured)
+		created = append(created, route)
+		if _, ok, _ := unstructured.NestedMap(route.Object, "spec", "tls", "externalCertificate"); ok {
+			return true, nil, apierrors.NewBadRequest(`strict decoding error: unknown field "spec.tls.externalCertificate"`)
+		}
+		return false, nil, nil
+	})
+	options := newExposeTestOptions()
+	options.ExposeVia = ExposeRoute
+	options.IngressTLSSecret = "llama3-tls"
+	errOut := &bytes.Buffer{}
+	options.IoStreams = &genericclioptions.IOStreams{Out: &bytes.Buffer{}, ErrOut: errOut}
+
+	if err := exposeViaRoute(context.Background(), dynamicClient, client, options, nimservice); err != nil {
+		t.Fatalf("exposeViaRoute error: %v", err)
+	}
+	if len(created) != 2 {
+		t.Fatalf("expected a retry with the copied certificate, got %d creates", len(created))
+	}
+	if key, _, _ := unstructured.NestedString(created[1].Object, "spec", "tls", "key"); key != "key" {
+		t.Fatalf("expected the key to be copied into the route: %+v", created[1].Object)
+	}
+	if !strings.Contains(errOut.String(), "copying its certificate and private key into the Route") {
+		t.Fatalf("expected a warning about the copied key, got %q", errOut.String())
+	}
+}
+
+func Test_ExposeViaRoute_DeletesNIMServiceOnFailure(t *testing.T) {
+	client, dynamicClient, nimservice := newRouteTestClients(t, errors.New(`the server could not find the requested resource`))
+	options := newExposeTestOptions()
+	options.ExposeVia = ExposeRoute
+	errOut := &bytes.Buffer{}
+	options.IoStreams = &genericclioptions.IOStreams{Out: &bytes.Buffer{}, ErrOut: errOut}
+
+	err := exposeViaRoute(context.Background(), dynamicClient, client, options, nimservice)
+	if err == nil || !strings.Contains(err.Error(), "failed to create Route nim/llama3") {
+		t.Fatalf("expected a Route error, got %v", err)
+	}
+	if _, err := client.NIM.AppsV1alpha1().NIMServices("nim").Get(context.Background(), "llama3", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected the NIMService to be deleted, got %v", err)
+	}
+	if !strings.Contains(errOut.String(), "deleted from namespace \"nim\" again") {
+		t.Fatalf("expected a note about the deleted NIMService, got %q", errOut.String())
+	}
+}
+
+func Test_FillOutNIMServiceSpec_MetricsAndScaleMetrics(t *testing.T) {
+	options := newExposeTestOptions()
+	options.ScaleMaxReplicas = 4
+	options.ScaleMinReplicas = -1
+	options.ScaleCPUUtilization = 80
+	options.ScaleMetrics = []string{"gpu_cache_usage_perc=0.75", "num_requests_running=10"}
+	options.ServiceMonitorInterval = "30s"
+	options.ServiceMonitorLabels = map[string]string{"release": "prometheus"}
+
+	ns, err := FillOutNIMServiceSpec(options)
+	if err != nil {
+		t.Fatalf("FillOutNIMServiceSpec error: %v", err)
+	}
+	if ns.Spec.Metrics.Enabled == nil || !*ns.Spec.Metrics.Enabled {
+		t.Fatalf("service monitor settings should enable metrics")
+	}
+	if ns.Spec.Metrics.ServiceMonitor.Interval != "30s" || ns.Spec.Metrics.ServiceMonitor.AdditionalLabels["release"] != "prometheus" {
+		t.Fatalf("service monitor not set correctly: %+v", ns.Spec.Metrics.ServiceMonitor)
+	}
+
+	metrics := ns.Spec.Scale.HPA.Metrics
+	if len(metrics) != 3 {
+		t.Fatalf("expected 3 HPA metrics, got %+v", metrics)
+	}
+	if metrics[0].Type != autoscalingv2.ResourceMetricSourceType || *metrics[0].Resource.Target.AverageUtilization != 80 {
+		t.Fatalf("cpu metric not set correctly: %+v", metrics[0])
+	}
+	kv := metrics[1]
+	if kv.Type != autoscalingv2.PodsMetricSourceType || kv.Pods.Metric.Name != "gpu_cache_usage_perc" || !kv.Pods.Target.AverageValue.Equal(resource.MustParse("0.75")) {
+		t.Fatalf("custom metric not set correctly: %+v", kv)
+	}
+}
+
+func Test_FillOutNIMServiceSpec_MetricsDefaultInterval(t *testing.T) {
+	options := newExposeTestOptions()
+	options.ScaleMaxReplicas = -1
+	options.Metrics = true
+
+	ns, err := FillOutNIMServiceSpec(options)
+	if err != nil {
+		t.Fatalf("FillOutNIMServiceSpec error: %v", err)
+	}
+	if ns.Spec.Metrics.ServiceMonitor.Interval != promv1.Duration(util.DefaultServiceMonitorInterval) {
+		t.Fatalf("expected default interval, got %+v", ns.Spec.Metrics.ServiceMonitor)
+	}
+}
+
+func Test_FillOutNIMServiceSpec_InvalidMetrics(t *testing.T) {
+	cases := map[string]func(*NIMServiceOptions){
+		"metric without hpa":   func(o *NIMServiceOptions) { o.ScaleMetrics = []string{"num_requests_running=10"} },
+		"metric without value": func(o *NIMServiceOptions) { o.ScaleMaxReplicas = 2; o.ScaleMetrics = []string{"num_requests_running"} },
+		"metric bad quantity":  func(o *NIMServiceOptions) { o.ScaleMaxReplicas = 2; o.ScaleMetrics = []string{"kv=high"} },
+		"bad interval":         func(o *NIMServiceOptions) { o.ServiceMonitorInterval = "30 seconds" },
+	}
+	for name, mutate := range cases {
+		options := newExposeTestOptions()
+		options.ScaleMaxReplicas = -1
+		mutate(options)
+		if _, err := FillOutNIMServiceSpec(options); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func Test_FillOutNIMServiceSpec_MultiNode(t *testing.T) {
+	options := newExposeTestOptions()
+	options.ScaleMaxReplicas = -1
+	options.PipelineParallelism = 2
+	options.GPUsPerPod = 8
+	options.MPIStartTimeout = 600
+
+	ns, err := FillOutNIMServiceSpec(options)
+	if err != nil {
+		t.Fatalf("FillOutNIMServiceSpec error: %v", err)
+	}
+	multiNode := ns.Spec.MultiNode
+	if multiNode == nil || multiNode.BackendType != appsv1alpha1.NIMBackendTypeLWS || multiNode.Size != 2 || multiNode.GPUSPerPod != 8 {
+		t.Fatalf("multi-node not set correctly: %+v", multiNode)
+	}
+	if multiNode.MPI == nil || multiNode.MPI.MPIStartTimeout != 600 {
+		t.Fatalf("mpi start timeout not set: %+v", multiNode.MPI)
+	}
+	if !ns.Spec.Resources.Limits[corev1.ResourceName("nvidia.com/gpu")].Equal(resource.MustParse("8")) {
+		t.Fatalf("gpu limit should follow --gpus-per-pod: %+v", ns.Spec.Resources.Limits)
+	}
+
+	// Without --gpus-per-pod, the GPU limit sets the GPUs per pod.
+	options = newExposeTestOptions()
+	options.ScaleMaxReplicas = -1
+	options.MultiNodeSize = 2
+	options.GPULimit = "4"
+	if ns, err = FillOutNIMServiceSpec(options); err != nil {
+		t.Fatalf("FillOutNIMServiceSpec error: %v", err)
+	}
+	if ns.Spec.MultiNode == nil || ns.Spec.MultiNode.GPUSPerPod != 4 {
+		t.Fatalf("gpus per pod should follow --gpu-limit: %+v", ns.Spec.MultiNode)
+	}
+}
+
+func Test_FillOutNIMServiceSpec_InvalidMultiNode(t *testing.T) {
+	cases := map[string]func(*NIMServiceOptions){
+		"negative size":        func(o *NIMServiceOptions) { o.MultiNodeSize = -1 },
+		"size and pp differ":   func(o *NIMServiceOptions) { o.MultiNodeSize = 2; o.PipelineParallelism = 4 },
+		"gpu limit differs":    func(o *NIMServiceOptions) { o.MultiNodeSize = 2; o.GPUsPerPod = 8; o.GPULimit = "4" },
+		"fractional gpu limit": func(o *NIMServiceOptions) { o.MultiNodeSize = 2; o.GPULimit = "500m" },
+		"unknown backend":      func(o *NIMServiceOptions) { o.MultiNodeSize = 2; o.MultiNodeBackend = "mpi-operator" },
+		"kserve": func(o *NIMServiceOptions) {
+			o.MultiNodeSize = 2
+			o.InferencePlatform = string(appsv1alpha1.PlatformTypeKServe)
+		},
+		"autoscaling": func(o *NIMServiceOptions) { o.MultiNodeSize = 2; o.ScaleMaxReplicas = 4 },
+	}
+	for name, mutate := range cases {
+		options := newExposeTestOptions()
+		options.ScaleMaxReplicas = -1
+		mutate(options)
+		if _, err := FillOutNIMServiceSpec(options); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func Test_FillOutNIMServiceSpec_Storage(t *testing.T) {
+	options := newExposeTestOptions()
+	options.NIMCacheStorageName = ""
+	options.HostPath = "/mnt/models"
+	options.SharedMemorySize = "16Gi"
+
+	ns, err := FillOutNIMServiceSpec(options)
+	if err != nil {
+		t.Fatalf("FillOutNIMServiceSpec error: %v", err)
+	}
+	if ns.Spec.Storage.HostPath == nil || *ns.Spec.Storage.HostPath != "/mnt/models" {
+		t.Fatalf("host path not set: %+v", ns.Spec.Storage)
+	}
+	if ns.Spec.Storage.SharedMemorySizeLimit == nil || !ns.Spec.Storage.SharedMemorySizeLimit.Equal(resource.MustParse("16Gi")) {
+		t.Fatalf("shared memory size not set: %+v", ns.Spec.Storage.SharedMemorySizeLimit)
+	}
+	// The operator treats any PVC field as a second storage source.
+	if !reflect.DeepEqual(ns.Spec.Storage.PVC, appsv1alpha1.PersistentVolumeClaim{}) {
+		t.Fatalf("pvc should be empty with host path storage: %+v", ns.Spec.Storage.PVC)
+	}
+
+	options = newExposeTestOptions()
+	options.NIMCacheStorageProfile = "tensorrt_llm-h100-fp8-tp1"
+	if ns, err = FillOutNIMServiceSpec(options); err != nil {
+		t.Fatalf("FillOutNIMServiceSpec error: %v", err)
+	}
+	if ns.Spec.Storage.NIMCache.Name != "llama3-cache" || ns.Spec.Storage.NIMCache.Profile != options.NIMCacheStorageProfile || !reflect.DeepEqual(ns.Spec.Storage.PVC, appsv1alpha1.PersistentVolumeClaim{}) {
+		t.Fatalf("nimcache storage not set correctly: %+v", ns.Spec.Storage)
+	}
+}
+
+func Test_FillOutNIMServiceSpec_ConfiguredStorageClass(t *testing.T) {
+	cmd := NewCreateNIMServiceCommand(nil, genericclioptions.IOStreams{Out: &bytes.Buffer{}, ErrOut: &bytes.Buffer{}})
+	// Inherited from the root command.
+	cmd.Flags().String("namespace", "nim", "")
+	config := &nimconfig.Config{Values: map[string]nimconfig.Value{nimconfig.StorageClass: {Value: "standard"}}}
+	if err := nimconfig.Apply(cmd.Flags(), config); err != nil {
+		t.Fatalf("Apply error: %v", err)
+	}
+	options := newExposeTestOptions()
+	options.PVCStorageClass = cmd.Flags().Lookup("pvc-storage-class").Value.String()
+	if err := options.CompleteNamespace([]string{"llama3"}, cmd); err != nil {
+		t.Fatalf("CompleteNamespace error: %v", err)
+	}
+
+	// Without --pvc-create the configured storage class is not a PVC source.
+	ns, err := FillOutNIMServiceSpec(options)
+	if err != nil {
+		t.Fatalf("FillOutNIMServiceSpec error: %v", err)
+	}
+	if !reflect.DeepEqual(ns.Spec.Storage.PVC, appsv1alpha1.PersistentVolumeClaim{}) {
+		t.Fatalf("pvc should be empty with nimcache storage: %+v", ns.Spec.Storage.PVC)
+	}
+
+	options.NIMCacheStorageName = ""
+	options.PVCCreate = true
+	options.PVCSize = "20Gi"
+	if ns, err = FillOutNIMServiceSpec(options); err != nil {
+		t.Fatalf("FillOutNIMServiceSpec error: %v", err)
+	}
+	if ns.Spec.Storage.PVC.StorageClass != "standard" {
+		t.Fatalf("configured storage class not used for a new pvc: %+v", ns.Spec.Storage.PVC)
+	}
+
+	// A storage class set on the command line still needs --pvc-create.
+	options.PVCCreate = false
+	options.PVCStorageName = "nim-pvc"
+	options.PVCSize = ""
+	options.PVCStorageClass = "fast"
+	if _, err := FillOutNIMServiceSpec(options); err == nil || !strings.Contains(err.Error(), "--pvc-storage-class only applies with --pvc-create") {
+		t.Fatalf("expected a --pvc-create error, got %v", err)
+	}
+}
+
+func Test_FillOutNIMServiceSpec_InvalidStorage(t *testing.T) {
+	cases := map[string]struct {
+		mutate func(*NIMServiceOptions)
+		err    string
+	}{
+		"none":              {func(o *NIMServiceOptions) { o.NIMCacheStorageName = "" }, "spec.storage: Required value: one of nimCache, pvc or hostPath must be defined"},
+		"nimcache and pvc":  {func(o *NIMServiceOptions) { o.PVCStorageName = "nim-pvc" }, "only one of nimCache, pvc or hostPath may be defined"},
+		"pvc create and hp": {func(o *NIMServiceOptions) { o.NIMCacheStorageName = ""; o.PVCCreate = true; o.HostPath = "/mnt" }, "only one of nimCache, pvc or hostPath may be defined"},
+		"relative hostpath": {func(o *NIMServiceOptions) { o.NIMCacheStorageName = ""; o.HostPath = "models" }, "spec.storage.hostPath: Invalid value: \"models\": must be an absolute path"},
+		"profile alone": {func(o *NIMServiceOptions) {
+			o.NIMCacheStorageName = ""
+			o.HostPath = "/mnt"
+			o.NIMCacheStorageProfile = "p"
+		}, "spec.storage.nimCache.name: Required value"},
+		"size without create": {func(o *NIMServiceOptions) {
+			o.NIMCacheStorageName = ""
+			o.PVCStorageName = "nim-pvc"
+			o.PVCSize = "20Gi"
+		}, "--pvc-size only applies with --pvc-create"},
+		"shm zero":     {func(o *NIMServiceOptions) { o.SharedMemorySize = "0" }, "must be greater than 0"},
+		"shm quantity": {func(o *NIMServiceOptions) { o.SharedMemorySize = "lots" }, "invalid --shared-memory-size"},
+	}
+	for name, tc := range cases {
+		options := newExposeTestOptions()
+		tc.mutate(options)
+		if _, err := FillOutNIMServiceSpec(options); err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%s: expected error containing %q, got %v", name, tc.err, err)
+		}
+	}
+}
+
+func Test_FillOutNIMServiceSpec_ReportsAllErrors(t *testing.T) {
+	options := newExposeTestOptions()
+	options.ServiceType = "BogusType"
+	options.InferencePlatform = "triton"
+	options.NIMCacheStorageName = ""
+	options.PVCCreate = true
+	options.ScaleMaxReplicas = 2
+	options.ScaleMinReplicas = 4
+
+	_, err := FillOutNIMServiceSpec(options)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	for _, want := range []string{
+		"spec.expose.service.type",
+		"spec.inferencePlatform",
+		"spec.storage.pvc.size",
+		"spec.storage.pvc.storageClass",
+		"spec.scale.hpa.minReplicas",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in error, got %v", want, err)
+		}
+	}
+}
+
+func Test_FillOutNIMServiceSpec_ReportsStorageWithOtherErrors(t *testing.T) {
+	options := newExposeTestOptions()
+	options.NIMCacheStorageName = ""
+	options.HostPath = "models"
+	options.ServiceType = "Bogus"
+	options.InferencePlatform = "x"
+
+	_, err := FillOutNIMServiceSpec(options)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	for _, want := range []string{"spec.storage.hostPath", "spec.expose.service.type", "spec.inferencePlatform"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in error, got %v", want, err)
+		}
+	}
+}
+
+func Test_FillOutNIMServiceSpec_ReportsFlagErrorsTogether(t *testing.T) {
+	options := newExposeTestOptions()
+	options.ExposeVia = ExposeHTTPRoute
+	options.MultiNodeSize = -1
+	options.CPULimit = "lots"
+	options.Env = []string{"NOVALUE"}
+	options.EnvFromSecrets = []string{"KEY="}
+	options.Tolerations = []string{"gpu:Sometimes"}
+	options.ScaleMetrics = []string{"gpu_cache_usage_perc"}
+	options.ServiceMonitorInterval = "soon"
+	options.ServiceType = "Bogus"
+
+	_, err := FillOutNIMServiceSpec(options)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	for _, want := range []string{
+		"spec.expose.httpRoute.spec.parentRefs: Required value",
+		"spec.multiNode.size: Invalid value: -1",
+		"spec.resources.limits[cpu]: Invalid value: \"lots\"",
+		"spec.env: Invalid value",
+		"invalid --env-from-secret",
+		"spec.tolerations: Invalid value",
+		"spec.scale.hpa.metrics: Invalid value",
+		"spec.metrics.serviceMonitor.interval: Invalid value: \"soon\"",
+		"spec.expose.service.type",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in error, got %v", want, err)
+		}
+	}
+}
+
+func newReadyNIMCache(puller string, profiles ...appsv1alpha1.NIMProfile) *appsv1alpha1.NIMCache {
+	nimcache := &appsv1alpha1.NIMCache{ObjectMeta: metav1.ObjectMeta{Name: "llama3-cache", Namespace: "nim"}}
+	nimcache.Spec.Source.NGC = &appsv1alpha1.NGCSource{ModelPuller: puller}
+	nimcache.Status.State = appsv1alpha1.NimCacheStatusReady
+	nimcache.Status.Profiles = profiles
+	return nimcache
+}
+
+func newFromNIMCacheOptions(errOut *bytes.Buffer) *NIMServiceOptions {
+	options := NewNIMServiceOptions(nil, genericclioptions.IOStreams{Out: &bytes.Buffer{}, ErrOut: errOut})
+	options.ResourceName = "llama3"
+	options.Namespace = "nim"
+	options.FromNIMCache = "llama3-cache"
+	options.AuthSecret = util.AuthSecret
+	options.ServicePort = util.ServicePort
+	options.PVCVolumeAccessMode = util.PVCVolumeAccessMode
+	options.ServiceType = util.ServiceType
+	options.GPULimit = util.GPULimit
+	options.ScaleMaxReplicas = util.ScaleMaxReplicas
+	options.InferencePlatform = util.InferencePlatform
+	return options
+}
+
+func Test_RunCreateNIMService_FromNIMCache(t *testing.T) {
+	nimcache := newReadyNIMCache("nvcr.io:443/nim/meta/llama-3.1-70b-instruct:1.3.3",
+		appsv1alpha1.NIMProfile{Name: "trt-fp8-tp4", Config: map[string]string{"tp": "4"}},
+		appsv1alpha1.NIMProfile{Name: "vllm-bf16-tp2", Config: map[string]string{"tp": "2"}},
+	)
+	client := fake.NewFakeClient(nil, []runtime.Object{nimcache})
+	options := newFromNIMCacheOptions(&bytes.Buffer{})
+	options.NIMCacheStorageProfile = "trt-fp8-tp4"
+
+	if err := RunCreateNIMService(context.Background(), options, client); err != nil {
+		t.Fatalf("RunCreateNIMService error: %v", err)
+	}
+	ns, err := client.NIM.AppsV1alpha1().NIMServices("nim").Get(context.Background(), "llama3", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("NIMService not created: %v", err)
+	}
+	if ns.Spec.Image.Repository != "nvcr.io:443/nim/meta/llama-3.1-70b-instruct" || ns.Spec.Image.Tag != "1.3.3" {
+		t.Fatalf("image not derived from the model puller: %+v", ns.Spec.Image)
+	}
+	if ns.Spec.Storage.NIMCache.Name != "llama3-cache" || ns.Spec.Storage.NIMCache.Profile != "trt-fp8-tp4" {
+		t.Fatalf("nimcache storage not set: %+v", ns.Spec.Storage.NIMCache)
+	}
+	if !ns.Spec.Resources.Limits[corev1.ResourceName("nvidia.com/gpu")].Equal(resource.MustParse("4")) {
+		t.Fatalf("gpu limit should follow the profile's tp: %+v", ns.Spec.Resources.Limits)
+	}
+
+	// An explicit GPU limit is kept, with a warning.
+	errOut := &bytes.Buffer{}
+	options = newFromNIMCacheOptions(errOut)
+	options.ResourceName = "llama3-tp2"
+	options.NIMCacheStorageProfile = "vllm-bf16-tp2"
+	options.GPULimit = "4"
+	options.ImageRepository = "registry.local/llama"
+	options.Tag = "custom"
+	if err := RunCreateNIMService(context.Background(), options, client); err != nil {
+		t.Fatalf("RunCreateNIMService error: %v", err)
+	}
+	if ns, _ = client.NIM.AppsV1alpha1().NIMServices("nim").Get(context.Background(), "llama3-tp2", metav1.GetOptions{}); ns.Spec.Image.Repository != "registry.local/llama" {
+		t.Fatalf("explicit image should win: %+v", ns.Spec.Image)
+	}
+	if !strings.Contains(errOut.String(), "differs from the tensor parallelism 2") {
+		t.Fatalf("expected gpu limit warning, got %q", errOut.String())
+	}
+}
+
+func Test_RunCreateNIMService_FromNIMCacheConfiguredGPULimit(t *testing.T) {
+	nimcache := newReadyNIMCache("nvcr.io/nim/meta/llama-3.1-70b-instruct:1.3.3",
+		appsv1alpha1.NIMProfile{Name: "trt-fp8-tp4", Config: map[string]string{"tp": "4"}},
+	)
+	client := fake.NewFakeClient(nil, []runtime.Object{nimcache})
+	errOut := &bytes.Buffer{}
+	options := newFromNIMCacheOptions(errOut)
+	options.NIMCacheStorageProfile = "trt-fp8-tp4"
+	// A GPU limit from nim config is a default, so the profile's tensor parallelism still replaces it.
+	options.GPULimit = "2"
+	options.DefaultGPULimit = "2"
+
+	if err := RunCreateNIMService(context.Background(), options, client); err != nil {
+		t.Fatalf("RunCreateNIMService error: %v", err)
+	}
+	ns, err := client.NIM.AppsV1alpha1().NIMServices("nim").Get(context.Background(), "llama3", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("NIMService not created: %v", err)
+	}
+	if !ns.Spec.Resources.Limits[corev1.ResourceName("nvidia.com/gpu")].Equal(resource.MustParse("4")) {
+		t.Fatalf("gpu limit should follow the profile's tp: %+v", ns.Spec.Resources.Limits)
+	}
+	if errOut.Len() != 0 {
+		t.Fatalf("unexpected warning: %s", errOut.String())
+	}
+}
+
+func Test_RunCreateNIMService_FromNIMCacheErrors(t *testing.T) {
+	notReady := newReadyNIMCache("nvcr.io/nim/llama:1.0")
+	notReady.Status.State = appsv1alpha1.NimCacheStatusInProgress
+	hf := newReadyNIMCache("")
+	hf.Spec.Source = appsv1alpha1.NIMSource{HF: &appsv1alpha1.HuggingFaceHubSource{}}
+
+	cases := map[string]struct {
+		nimcache *appsv1alpha1.NIMCache
+		mutate   func(*NIMServiceOptions)
+		err      string
+	}{
+		"missing":          {nil, func(*NIMServiceOptions) {}, "failed to get NIMCache nim/llama3-cache"},
+		"not ready":        {notReady, func(*NIMServiceOptions) {}, `is not ready (state "InProgress")`},
+		"not ngc":          {hf, func(*NIMServiceOptions) {}, "does not cache from NGC"},
+		"digest":           {newReadyNIMCache("nvcr.io/nim/llama@sha256:abc"), func(*NIMServiceOptions) {}, "pinned by digest"},
+		"no tag":           {newReadyNIMCache("nvcr.io/nim/llama"), func(*NIMServiceOptions) {}, "has no tag"},
+		"other storage":    {newReadyNIMCache("nvcr.io/nim/llama:1.0"), func(o *NIMServiceOptions) { o.NIMCacheStorageName = "other" }, "conflicts with --from-nimcache"},
+		"uncached profile": {newReadyNIMCache("nvcr.io/nim/llama:1.0", appsv1alpha1.NIMProfile{Name: "a"}), func(o *NIMServiceOptions) { o.NIMCacheStorageProfile = "b" }, `profile "b" is not cached by NIMCache nim/llama3-cache; cached profiles: a`},
+	}
+	for name, tc := range cases {
+		var nimObjs []runtime.Object
+		if tc.nimcache != nil {
+			nimObjs = append(nimObjs, tc.nimcache)
+		}
+		options := newFromNIMCacheOptions(&bytes.Buffer{})
+		tc.mutate(options)
+		err := RunCreateNIMService(context.Background(), options, fake.NewFakeClient(nil, nimObjs))
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%s: expected error containing %q, got %v", name, tc.err, err)
+		}
+	}
+}
+
+func newInteractiveClusterObjects() []runtime.Object {
+	return []runtime.Object{
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "models", Namespace: "nim"}},
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "fast", Annotations: map[string]string{defaultClassKey: "true"}}},
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "slow"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "ngc-api-secret", Namespace: "nim"}, Type: corev1.SecretTypeOpaque},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "ngc-secret", Namespace: "nim"}, Type: corev1.SecretTypeDockerConfigJson},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "gpu-1", Labels: map[string]string{gpuProductLabel: "NVIDIA-H100-80GB-HBM3"}}},
+	}
+}
+
+// Sets the flag defaults, which the wizard offers as default answers.
+func newInteractiveNIMServiceOptions(answers ...string) (*NIMServiceOptions, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	in := strings.NewReader(strings.Join(answers, "\n") + "\n")
+	options := NewNIMServiceOptions(nil, genericclioptions.IOStreams{In: in, Out: out, ErrOut: &bytes.Buffer{}})
+	options.ResourceName = "llama3"
+	options.Namespace = "nim"
+	options.Interactive = true
+	options.AuthSecret = util.AuthSecret
+	options.PullSecrets = util.PullSecrets
+	options.PullPolicy = util.PullPolicy
+	options.PVCVolumeAccessMode = util.PVCVolumeAccessMode
+	options.ServiceType = util.ServiceType
+	options.ServicePort = util.ServicePort
+	options.GPULimit = util.GPULimit
+	options.Replicas = util.Replicas
+	options.ScaleMaxReplicas = util.ScaleMaxReplicas
+	options.ScaleMinReplicas = util.ScaleMinReplicas
+	options.InferencePlatform = util.InferencePlatform
+	return options, out
+}
+
+func Test_RunCreateNIMService_Interactive(t *testing.T) {
+	client := fake.NewFakeClient(newInteractiveClusterObjects(), nil)
+	options, out := newInteractiveNIMServiceOptions(
+		"3",     // storage: pvc-create
+		"",      // PVC name: llama3-pvc
+		"100Gi", // PVC size
+		"",      // StorageClass: the cluster default
+		"",      // access mode: the flag default
+		"nvcr.io/nim/meta/llama-3.1-8b-instruct",
+		"1.3.3",
+		"",  // NGC API key secret
+		"",  // pull secret
+		"2", // GPU product
+		"2", // GPUs per replica
+		"y",
+	)
+
+	if err := RunCreateNIMService(context.Background(), options, client); err != nil {
+		t.Fatalf("RunCreateNIMService error: %v\n%s", err, out.String())
+	}
+	ns, err := client.NIM.AppsV1alpha1().NIMServices("nim").Get(context.Background(), "llama3", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("NIMService not created: %v", err)
+	}
+	pvc := ns.Spec.Storage.PVC
+	if pvc.Name != "llama3-pvc" || pvc.Create == nil || !*pvc.Create || pvc.Size != "100Gi" || pvc.StorageClass != "fast" || pvc.VolumeAccessMode != corev1.ReadWriteMany {
+		t.Fatalf("pvc not set from the answers: %+v", pvc)
+	}
+	if ns.Spec.Image.Repository != "nvcr.io/nim/meta/llama-3.1-8b-instruct" || ns.Spec.AuthSecret != "ngc-api-secret" || ns.Spec.Image.PullSecrets[0] != "ngc-secret" {
+		t.Fatalf("image or secrets not set: %+v %q", ns.Spec.Image, ns.Spec.AuthSecret)
+	}
+	if ns.Spec.NodeSelector[gpuProductLabel] != "NVIDIA-H100-80GB-HBM3" || !ns.Spec.Resources.Limits[corev1.ResourceName("nvidia.com/gpu")].Equal(resource.MustParse("2")) {
+		t.Fatalf("gpus not set: %+v %+v", ns.Spec.NodeSelector, ns.Spec.Resources.Limits)
+	}
+	for _, want := range []string{"  1) fast", "StorageClass [fast]:", "  2) NVIDIA-H100-80GB-HBM3", "kind: NIMService", "Create NIMService nim/llama3? (y/N):"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in output:\n%s", want, out.String())
+		}
+	}
+}
+
+func Test_RunCreateNIMService_InteractiveFromNIMCache(t *testing.T) {
+	nimcache := newReadyNIMCache("nvcr.io/nim/meta/llama-3.1-70b-instruct:1.3.3",
+		appsv1alpha1.NIMProfile{Name: "trt-fp8-tp4", Config: map[string]string{"tp": "4"}},
+		appsv1alpha1.NIMProfile{Name: "vllm-bf16-tp2", Config: map[string]string{"tp": "2"}},
+	)
+	client := fake.NewFakeClient(newInteractiveClusterObjects(), []runtime.Object{nimcache})
+	// A Ready NIMCache makes it the default storage, and its model puller the image.
+	options, out := newInteractiveNIMServiceOptions("", "1", "2", "", "", "", "", "", "y")
+
+	if err := RunCreateNIMService(context.Background(), options, client); err != nil {
+		t.Fatalf("RunCreateNIMService error: %v\n%s", err, out.String())
+	}
+	ns, err := client.NIM.AppsV1alpha1().NIMServices("nim").Get(context.Background(), "llama3", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("NIMService not created: %v", err)
+	}
+	if ns.Spec.Storage.NIMCache.Name != "llama3-cache" || ns.Spec.Storage.NIMCache.Profile != "vllm-bf16-tp2" || ns.Spec.Image.Tag != "1.3.3" {
+		t.Fatalf("nimcache storage or image not set: %+v %+v", ns.Spec.Storage.NIMCache, ns.Spec.Image)
+	}
+	if !ns.Spec.Resources.Limits[corev1.ResourceName("nvidia.com/gpu")].Equal(resource.MustParse("2")) || ns.Spec.NodeSelector != nil {
+		t.Fatalf("gpus should follow the profile: %+v %+v", ns.Spec.Resources.Limits, ns.Spec.NodeSelector)
+	}
+}
+
+func Test_RunCreateNIMService_InteractiveDeclined(t *testing.T) {
+	client := fake.NewFakeClient(newInteractiveClusterObjects(), nil)
+	options, out := newInteractiveNIMServiceOptions("pvc", "9", "models", "repo", "v1", "", "", "", "", "n")
+	if err := RunCreateNIMService(context.Background(), options, client); err != nil {
+		t.Fatalf("RunCreateNIMService error: %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "Choose one of the numbers or values above.") || !strings.Contains(out.String(), "Not created.") {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+	list, _ := client.NIM.AppsV1alpha1().NIMServices("nim").List(context.Background(), metav1.ListOptions{})
+	if len(list.Items) != 0 {
+		t.Fatalf("declined NIMService should not be created")
+	}
+
+	// Input that ends before the wizard does is an error, not a silent default.
+	options, _ = newInteractiveNIMServiceOptions("pvc", "models")
+	if err := RunCreateNIMService(context.Background(), options, client); err == nil || !strings.Contains(err.Error(), "standard input ended") {
+		t.Fatalf("expected input error, got %v", err)
+	}
+}
+
 // --- NIMCache tests ---
 
 func Test_ValidateNIMCacheOptions(t *testing.T) {
@@ -174,6 +1055,142 @@ func Test_FillOutNIMCacheSpec_DataStore(t *testing.T) {
 	}
 }
 
+func Test_FillOutNIMCacheSpec_JobSettings(t *testing.T) {
+	options := &NIMCacheOptions{
+		SourceConfiguration: "ngc",
+		ModelPuller:         "nvcr.io/nim/puller:latest",
+		AuthSecret:          "ngc-api-secret",
+		NodeSelector:        map[string]string{"kubernetes.io/hostname": "cache-node"},
+		Tolerations:         []string{"dedicated=cache:NoSchedule"},
+		Env:                 []string{"NIM_CACHE_PATH=/model-store"},
+		ProxyHTTP:           "http://proxy.corp:3128",
+		ProxyHTTPS:          "http://proxy.corp:3128",
+		NoProxy:             ".svc,.cluster.local",
+		CAConfigMap:         "corp-ca",
+		UserID:              1000,
+		GroupID:             2000,
+		Labels:              map[string]string{"team": "ml"},
+		Annotations:         map[string]string{"owner": "ml-platform"},
+	}
+
+	nc, err := FillOutNIMCacheSpec(options)
+	if err != nil {
+		t.Fatalf("FillOutNIMCacheSpec error: %v", err)
+	}
+	if nc.Spec.NodeSelector["kubernetes.io/hostname"] != "cache-node" || len(nc.Spec.Tolerations) != 1 || nc.Spec.Tolerations[0].Value != "cache" {
+		t.Fatalf("scheduling not set: %+v %+v", nc.Spec.NodeSelector, nc.Spec.Tolerations)
+	}
+	if len(nc.Spec.Env) != 1 || nc.Spec.Env[0].Name != "NIM_CACHE_PATH" {
+		t.Fatalf("env not set: %+v", nc.Spec.Env)
+	}
+	want := appsv1alpha1.ProxySpec{HttpProxy: "http://proxy.corp:3128", HttpsProxy: "http://proxy.corp:3128", NoProxy: ".svc,.cluster.local", CertConfigMap: "corp-ca"}
+	if nc.Spec.Proxy == nil || *nc.Spec.Proxy != want {
+		t.Fatalf("proxy not set: %+v", nc.Spec.Proxy)
+	}
+	if nc.Spec.UserID == nil || *nc.Spec.UserID != 1000 || nc.Spec.GroupID == nil || *nc.Spec.GroupID != 2000 {
+		t.Fatalf("user and group IDs not set")
+	}
+	if nc.Labels["team"] != "ml" || nc.Annotations["owner"] != "ml-platform" {
+		t.Fatalf("metadata not set: %+v %+v", nc.Labels, nc.Annotations)
+	}
+
+	// Unset IDs and no proxy flags leave the operator defaults in place.
+	options = NewNIMCacheOptions(nil, genericclioptions.IOStreams{})
+	options.SourceConfiguration = "ngc"
+	options.CAConfigMap = "corp-ca"
+	if nc, err = FillOutNIMCacheSpec(options); err != nil {
+		t.Fatalf("FillOutNIMCacheSpec error: %v", err)
+	}
+	if nc.Spec.UserID != nil || nc.Spec.GroupID != nil || nc.Spec.Proxy == nil || nc.Spec.Proxy.CertConfigMap != "corp-ca" || nc.Spec.Proxy.HttpProxy != "" {
+		t.Fatalf("unexpected defaults: user %v, group %v, proxy %+v", nc.Spec.UserID, nc.Spec.GroupID, nc.Spec.Proxy)
+	}
+}
+
+func Test_FillOutNIMCacheSpec_InvalidJobSettings(t *testing.T) {
+	cases := map[string]func(*NIMCacheOptions){
+		"env without value": func(o *NIMCacheOptions) { o.Env = []string{"NOVALUE"} },
+		"toleration effect": func(o *NIMCacheOptions) { o.Tolerations = []string{"gpu:Sometimes"} },
+		"proxy host only":   func(o *NIMCacheOptions) { o.ProxyHTTP = "proxy.corp:3128" },
+		"proxy no scheme":   func(o *NIMCacheOptions) { o.ProxyHTTPS = "proxy.corp" },
+	}
+	for name, mutate := range cases {
+		options := &NIMCacheOptions{SourceConfiguration: "ngc", ModelPuller: "img", AuthSecret: "s"}
+		mutate(options)
+		if _, err := FillOutNIMCacheSpec(options); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func Test_RunCreateNIMCache_Validation(t *testing.T) {
+	options := NewNIMCacheOptions(nil, genericclioptions.IOStreams{Out: &bytes.Buffer{}, ErrOut: &bytes.Buffer{}})
+	options.ResourceName = "llama3-cache"
+	options.Namespace = "nim"
+	options.SourceConfiguration = "ngc"
+	options.PVCCreate = true
+	options.PVCVolumeAccessMode = "ReadWriteSometimes"
+	options.QosProfile = "fast"
+	k8sClient := fake.NewFakeClient(nil, nil)
+
+	err := RunCreateNIMCache(context.Background(), options, k8sClient)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	for _, want := range []string{"spec.source.ngc.modelPuller", "spec.source.ngc.model.qosProfile", "spec.storage.pvc.size", "spec.storage.pvc.volumeAccessMode"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in error, got %v", want, err)
+		}
+	}
+	list, _ := k8sClient.NIMClient().AppsV1alpha1().NIMCaches("nim").List(context.Background(), metav1.ListOptions{})
+	if len(list.Items) != 0 {
+		t.Fatalf("invalid NIMCache should not be created")
+	}
+}
+
+func Test_RunCreateNIMCache_Interactive(t *testing.T) {
+	client := fake.NewFakeClient(newInteractiveClusterObjects(), nil)
+	out := &bytes.Buffer{}
+	answers := []string{
+		"", // source: ngc
+		"nvcr.io/nim/meta/llama-3.1-8b-instruct:1.3.3",
+		"1", // NGC API key secret
+		"",  // pull secret
+		"2", // GPU product
+		"2", // tensor parallelism
+		"1", // storage: existing PVC
+		"models",
+		"yes",
+	}
+	options := NewNIMCacheOptions(nil, genericclioptions.IOStreams{In: strings.NewReader(strings.Join(answers, "\n")), Out: out, ErrOut: &bytes.Buffer{}})
+	options.ResourceName = "llama3-cache"
+	options.Namespace = "nim"
+	options.Interactive = true
+	options.AuthSecret = util.AuthSecret
+	options.PullSecret = util.PullSecret
+	options.PVCVolumeAccessMode = util.PVCVolumeAccessMode
+
+	if err := RunCreateNIMCache(context.Background(), options, client); err != nil {
+		t.Fatalf("RunCreateNIMCache error: %v\n%s", err, out.String())
+	}
+	nc, err := client.NIM.AppsV1alpha1().NIMCaches("nim").Get(context.Background(), "llama3-cache", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("NIMCache not created: %v", err)
+	}
+	ngc := nc.Spec.Source.NGC
+	if ngc == nil || ngc.ModelPuller != "nvcr.io/nim/meta/llama-3.1-8b-instruct:1.3.3" || ngc.AuthSecret != "ngc-api-secret" || ngc.PullSecret != "ngc-secret" {
+		t.Fatalf("ngc source not set: %+v", ngc)
+	}
+	if len(ngc.Model.GPUs) != 1 || ngc.Model.GPUs[0].Product != "NVIDIA-H100-80GB-HBM3" || ngc.Model.TensorParallelism != "2" {
+		t.Fatalf("model not set: %+v", ngc.Model)
+	}
+	if nc.Spec.Storage.PVC.Name != "models" || *nc.Spec.Storage.PVC.Create {
+		t.Fatalf("existing pvc not set: %+v", nc.Spec.Storage.PVC)
+	}
+	if !strings.Contains(out.String(), "kind: NIMCache") {
+		t.Fatalf("expected the YAML before confirming:\n%s", out.String())
+	}
+}
+
 func Test_FillOutNIMCacheSpec_InvalidBools(t *testing.T) {
 	options := &NIMCacheOptions{
 		SourceConfiguration: "ngc",
@@ -192,3 +1209,114 @@ func Test_FillOutNIMCacheSpec_InvalidBools(t *testing.T) {
 		t.Fatalf("expected error for invalid --buildable bool")
 	}
 }
+
+// --- Secret tests ---
+
+func newTestSecretOptions(in string) (*SecretOptions, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	options := NewSecretOptions(nil, genericclioptions.IOStreams{In: strings.NewReader(in), Out: out, ErrOut: &bytes.Buffer{}})
+	options.Namespace = "nim"
+	options.AuthSecret = "ngc-api-secret"
+	options.PullSecret = "ngc-secret"
+	options.Registry = "nvcr.io"
+	return options, out
+}
+
+func Test_RunCreateNGCSecret_FromEnv(t *testing.T) {
+	t.Setenv("TEST_NGC_API_KEY", "nvapi-secret\n")
+	options, _ := newTestSecretOptions("")
+	options.KeyFromEnv = "TEST_NGC_API_KEY"
+	k8sClient := fake.NewFakeClient(nil, nil)
+
+	if err := RunCreateNGCSecret(context.Background(), options, k8sClient); err != nil {
+		t.Fatalf("RunCreateNGCSecret error: %v", err)
+	}
+
+	pull, err := k8sClient.Kube.CoreV1().Secrets("nim").Get(context.Background(), "ngc-secret", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("pull secret not created: %v", err)
+	}
+	if pull.Type != corev1.SecretTypeDockerConfigJson {
+		t.Fatalf("unexpected pull secret type %s", pull.Type)
+	}
+	var config struct {
+		Auths map[string]struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+			Auth     string `json:"auth"`
+		} `json:"auths"`
+	}
+	if err := json.Unmarshal(pull.Data[corev1.DockerConfigJsonKey], &config); err != nil {
+		t.Fatalf("invalid docker config: %v", err)
+	}
+	auth := config.Auths["nvcr.io"]
+	if auth.Username != "$oauthtoken" || auth.Password != "nvapi-secret" || auth.Auth != "JG9hdXRodG9rZW46bnZhcGktc2VjcmV0" {
+		t.Fatalf("unexpected nvcr.io auth: %+v", auth)
+	}
+
+	api, err := k8sClient.Kube.CoreV1().Secrets("nim").Get(context.Background(), "ngc-api-secret", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("api key secret not created: %v", err)
+	}
+	if api.Type != corev1.SecretTypeOpaque || string(api.Data["NGC_API_KEY"]) != "nvapi-secret" {
+		t.Fatalf("unexpected api key secret: %+v", api)
+	}
+}
+
+func Test_RunCreateHFSecret_FromFileAndStdin(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("hf_file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	options, _ := newTestSecretOptions("")
+	options.AuthSecret = "hf-api-secret"
+	options.KeyFromFile = path
+	k8sClient := fake.NewFakeClient(nil, nil)
+	if err := RunCreateHFSecret(context.Background(), options, k8sClient); err != nil {
+		t.Fatalf("RunCreateHFSecret error: %v", err)
+	}
+
+	// A second run fails without --overwrite and replaces the token with it.
+	options, out := newTestSecretOptions("hf_stdin\n")
+	options.AuthSecret = "hf-api-secret"
+	options.KeyFromStdin = true
+	if err := RunCreateHFSecret(context.Background(), options, k8sClient); err == nil || !strings.Contains(err.Error(), "--overwrite") {
+		t.Fatalf("expected already exists error, got %v", err)
+	}
+	options, out = newTestSecretOptions("hf_stdin\n")
+	options.AuthSecret = "hf-api-secret"
+	options.KeyFromStdin = true
+	options.Overwrite = true
+	if err := RunCreateHFSecret(context.Background(), options, k8sClient); err != nil {
+		t.Fatalf("RunCreateHFSecret overwrite error: %v", err)
+	}
+	if !strings.Contains(out.String(), "replaced") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+
+	secret, err := k8sClient.Kube.CoreV1().Secrets("nim").Get(context.Background(), "hf-api-secret", metav1.GetOptions{})
+	if err != nil || string(secret.Data["HF_TOKEN"]) != "hf_stdin" {
+		t.Fatalf("unexpected secret: %+v, %v", secret, err)
+	}
+}
+
+func Test_readKey_Sources(t *testing.T) {
+	options, _ := newTestSecretOptions("")
+	if _, err := readKey(options); err == nil {
+		t.Fatalf("expected error without a key source")
+	}
+	options.KeyFromEnv = "UNSET_TEST_VARIABLE"
+	options.KeyFromStdin = true
+	if _, err := readKey(options); err == nil {
+		t.Fatalf("expected error with two key sources")
+	}
+	options.KeyFromStdin = false
+	if _, err := readKey(options); err == nil || !strings.Contains(err.Error(), "not set") {
+		t.Fatalf("expected unset variable error, got %v", err)
+	}
+	options, _ = newTestSecretOptions("  \n")
+	options.KeyFromStdin = true
+	if _, err := readKey(options); err == nil || !strings.Contains(err.Error(), "empty") {
+		t.Fatalf("expected empty key error, got %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package create
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Parses --env values of the form KEY=VALUE. The value may be empty or contain '='.
+func parseEnvVars(values []string) ([]corev1.EnvVar, error) {
+	var envs []corev1.EnvVar
+	for _, value := range values {
+		name, val, ok := strings.Cut(value, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --env %q, must be KEY=VALUE", value)
+		}
+		envs = append(envs, corev1.EnvVar{Name: name, Value: val})
+	}
+	return envs, nil
+}
+
+// Parses --env-from-secret values of the form KEY=SECRET[:SECRET_KEY]. SECRET_KEY defaults to KEY.
+func parseEnvFromSecrets(values []string) ([]corev1.EnvVar, error) {
+	var envs []corev1.EnvVar
+	for _, value := range values {
+		name, ref, ok := strings.Cut(value, "=")
+		if !ok || name == "" || ref == "" {
+			return nil, fmt.Errorf("invalid --env-from-secret %q, must be KEY=SECRET[:SECRET_KEY]", value)
+		}
+		secret, key, _ := strings.Cut(ref, ":")
+		if key == "" {
+			key = name
+		}
+		if secret == "" {
+			return nil, fmt.Errorf("invalid --env-from-secret %q, secret name is empty", value)
+		}
+		envs = append(envs, corev1.EnvVar{
+			Name: name,
+			ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secret},
+				Key:                  key,
+			}},
+		})
+	}
+	return envs, nil
+}
+
+// Parses --toleration values in the kubectl taint syntax: KEY[=VALUE][:EFFECT]. Without a value the toleration uses the
+// Exists operator; without an effect it tolerates every effect. A bare ":EFFECT" tolerates every taint with that effect.
+func parseTolerations(values []string) ([]corev1.Toleration, error) {
+	var tolerations []corev1.Toleration
+	for _, value := range values {
+		keyValue, effect, _ := strings.Cut(value, ":")
+		key, val, hasValue := strings.Cut(keyValue, "=")
+
+		toleration := corev1.Toleration{Key: key, Operator: corev1.TolerationOpExists}
+		if hasValue {
+			if key == "" {
+				return nil, fmt.Errorf("invalid --toleration %q, a value requires a key", value)
+			}
+			toleration.Operator = corev1.TolerationOpEqual
+			toleration.Value = val
+		}
+		switch corev1.TaintEffect(effect) {
+		case "", corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+			toleration.Effect = corev1.TaintEffect(effect)
+		default:
+			return nil, fmt.Errorf("invalid --toleration %q, effect must be one of 'NoSchedule', 'PreferNoSchedule', 'NoExecute'", value)
+		}
+		if key == "" && effect == "" {
+			return nil, fmt.Errorf("invalid --toleration %q, must be KEY[=VALUE][:EFFECT]", value)
+		}
+		tolerations = append(tolerations, toleration)
+	}
+	return tolerations, nil
+}
+
+// Adds the quantity to the resource list under name, creating the list if needed. Empty quantities are skipped.
+func addQuantity(list corev1.ResourceList, name corev1.ResourceName, quantity, flag string) (corev1.ResourceList, error) {
+	if quantity == "" {
+		return list, nil
+	}
+	parsed, err := resource.ParseQuantity(quantity)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --%s %q: %w", flag, quantity, err)
+	}
+	if list == nil {
+		list = corev1.ResourceList{}
+	}
+	list[name] = parsed
+	return list, nil
+}
@@ -0,0 +1,338 @@
+package diff
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/term"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	"k8s-nim-operator-cli/pkg/util"
+	"k8s-nim-operator-cli/pkg/util/client"
+)
+
+const (
+	// Field manager of the dry-run apply, so the diff shows what nim would own after applying.
+	fieldManager = "kubectl-nim"
+
+	// Exit codes: differences found, and failures, like kubectl diff.
+	exitDrift = 1
+	exitError = 2
+
+	colorAuto   = "auto"
+	colorAlways = "always"
+	colorNever  = "never"
+)
+
+type DiffOptions struct {
+	IoStreams *genericclioptions.IOStreams
+	Namespace string
+	Filenames []string
+	Color     string
+}
+
+// An object identified by kind, namespace and name.
+type objectKey struct {
+	kind, namespace, name string
+}
+
+func (k objectKey) String() string {
+	return fmt.Sprintf("%s %s/%s", k.kind, k.namespace, k.name)
+}
+
+func NewDiffCommand(cmdFactory cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	options := &DiffOptions{IoStreams: &streams}
+
+	cmd := &cobra.Command{
+		Use:   "diff -f FILENAME",
+		Short: "Show how local NIMService and NIMCache manifests differ from the cluster",
+		Long: `Compare local NIMService and NIMCache manifests with the live objects, and print a unified diff of what applying them
+would change.
+
+Each manifest is applied with a server-side dry run, so the diff includes what the API server and the CRD schema would
+default and shows only fields that would actually change. Status, server-set metadata and fields holding their default
+are left out, as in nim export. Objects that exist only locally, and NIMServices or NIMCaches that exist only in the
+cluster, in the namespaces and kinds the manifests cover, are listed after the diff.
+
+Files may hold several YAML documents. A directory contributes its .yaml, .yml and .json files, and "-" reads from
+standard input. Objects without a namespace use --namespace.
+
+Exits with 0 when the cluster matches, 1 when there are differences and 2 when the comparison failed, so CI can detect
+drift.`,
+		Example: `  nim diff -f deploy/
+  nim diff -f llama3-nimservice.yaml -n nim --color=never`,
+		SilenceUsage: true,
+		// Runs the root's hook, which applies the config, so that a config that fails to load exits as a failure.
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			for parent := cmd.Parent(); parent != nil; parent = parent.Parent() {
+				if parent.PersistentPreRunE != nil {
+					return asFailure(parent.PersistentPreRunE(cmd, args))
+				}
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return asFailure(runDiff(cmd, args, options, cmdFactory))
+		},
+	}
+	cmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return asFailure(err)
+	})
+
+	cmd.Flags().StringArrayVarP(&options.Filenames, "filename", "f", nil, "File or directory of manifests to compare, or - for standard input. Can be repeated.")
+	cmd.Flags().StringVar(&options.Color, "color", colorAuto, "Color the diff. One of: auto, always, never. auto colors when writing to a terminal and NO_COLOR is unset.")
+
+	return cmd
+}
+
+func runDiff(cmd *cobra.Command, args []string, options *DiffOptions, cmdFactory cmdutil.Factory) error {
+	if len(args) > 0 {
+		return fmt.Errorf("unknown argument(s) %q; pass manifests with -f", strings.Join(args, " "))
+	}
+	if len(options.Filenames) == 0 {
+		cmd.HelpFunc()(cmd, args)
+		return nil
+	}
+	switch options.Color {
+	case colorAuto, colorAlways, colorNever:
+	default:
+		return fmt.Errorf("invalid --color %q, must be one of auto, always, never", options.Color)
+	}
+	namespace, err := cmd.Flags().GetString("namespace")
+	if err != nil {
+		return fmt.Errorf("failed to get namespace: %w", err)
+	}
+	options.Namespace = namespace
+	if options.Namespace == "" {
+		options.Namespace = "default"
+	}
+	k8sClient, err := client.NewClient(cmdFactory)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	return Run(cmd.Context(), options, k8sClient)
+}
+
+// Makes every error other than drift exit with exitError, so CI can tell a broken run from differences.
+func asFailure(err error) error {
+	var exitErr *util.ExitError
+	if err == nil || errors.As(err, &exitErr) {
+		return err
+	}
+	return &util.ExitError{Code: exitError, Err: err}
+}
+
+// Run prints the diff and returns an ExitError with code 1 on drift, or 2 when an object could not be compared.
+func Run(ctx context.Context, options *DiffOptions, k8sClient client.Client) error {
+	files, err := util.ReadObjects(options.Filenames, options.IoStreams.In)
+	if err != nil {
+		return &util.ExitError{Code: exitError, Err: err}
+	}
+
+	printer := &diffPrinter{out: options.IoStreams.Out, color: useColor(options)}
+	local := map[objectKey]bool{}
+	// Namespaces per kind covered by the manifests, searched for objects only in the cluster.
+	scopes := map[string]map[string]bool{}
+	var onlyLocal []objectKey
+	changed, failed := 0, 0
+
+	for _, file := range files {
+		obj := file.Object
+		gvk := obj.GroupVersionKind()
+		if gvk.Group != appsv1alpha1.SchemeGroupVersion.Group || (gvk.Kind != "NIMService" && gvk.Kind != "NIMCache") {
+			fmt.Fprintf(options.IoStreams.ErrOut, "%s: %s/%s: skipped, only NIMService and NIMCache are compared\n", file.Source, gvk.Kind, obj.GetName())
+			continue
+		}
+		if obj.GetNamespace() == "" {
+			obj.SetNamespace(options.Namespace)
+		}
+		key := objectKey{gvk.Kind, obj.GetNamespace(), obj.GetName()}
+		if local[key] {
+			fmt.Fprintf(options.IoStreams.ErrOut, "%s: %s is defined more than once\n", file.Source, key)
+			failed++
+			continue
+		}
+		local[key] = true
+		if scopes[key.kind] == nil {
+			scopes[key.kind] = map[string]bool{}
+		}
+		scopes[key.kind][key.namespace] = true
+
+		live, merged, err := compare(ctx, k8sClient, obj)
+		if err != nil {
+			fmt.Fprintf(options.IoStreams.ErrOut, "%s: %s: %v\n", file.Source, key, err)
+			failed++
+			continue
+		}
+		if live == nil {
+			onlyLocal = append(onlyLocal, key)
+		}
+		differs, err := printer.print(key, live, merged)
+		if err != nil {
+			return &util.ExitError{Code: exitError, Err: err}
+		}
+		if differs {
+			changed++
+		}
+	}
+
+	onlyCluster, err := findOnlyInCluster(ctx, k8sClient, scopes, local)
+	if err != nil {
+		return &util.ExitError{Code: exitError, Err: err}
+	}
+
+	out := options.IoStreams.Out
+	if len(onlyLocal) > 0 {
+		fmt.Fprintln(out, "\nOnly local, would be created:")
+		for _, key := range onlyLocal {
+			fmt.Fprintf(out, "  %s\n", key)
+		}
+	}
+	if len(onlyCluster) > 0 {
+		fmt.Fprintln(out, "\nOnly in the cluster:")
+		for _, key := range onlyCluster {
+			fmt.Fprintf(out, "  %s\n", key)
+		}
+	}
+
+	drift := changed + len(onlyCluster)
+	if failed > 0 {
+		return &util.ExitError{Code: exitError, Err: fmt.Errorf("%d object(s) could not be compared", failed)}
+	}
+	if drift > 0 {
+		return &util.ExitError{Code: exitDrift, Err: fmt.Errorf("%d object(s) differ from the cluster, %d only in the cluster", changed, len(onlyCluster))}
+	}
+	fmt.Fprintln(out, "No differences.")
+	return nil
+}
+
+// Returns the live object and the object a server-side apply of local would produce, both cleaned like nim export.
+// live is nil when the object does not exist yet.
+func compare(ctx context.Context, k8sClient client.Client, local *unstructured.Unstructured) (map[string]interface{}, map[string]interface{}, error) {
+	data, err := json.Marshal(local.Object)
+	if err != nil {
+		return nil, nil, err
+	}
+	namespace, name := local.GetNamespace(), local.GetName()
+	patchOptions := metav1.PatchOptions{DryRun: []string{metav1.DryRunAll}, FieldManager: fieldManager, Force: ptr.To(true)}
+	nimClient := k8sClient.NIMClient().AppsV1alpha1()
+
+	var live, merged runtime.Object
+	if local.GetKind() == "NIMService" {
+		current, err := nimClient.NIMServices(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err == nil {
+			live = current
+		} else if !apierrors.IsNotFound(err) {
+			return nil, nil, fmt.Errorf("failed to get the live object: %w", err)
+		}
+		if merged, err = nimClient.NIMServices(namespace).Patch(ctx, name, types.ApplyPatchType, data, patchOptions); err != nil {
+			return nil, nil, fmt.Errorf("server-side dry run failed: %w", err)
+		}
+	} else {
+		current, err := nimClient.NIMCaches(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err == nil {
+			live = current
+		} else if !apierrors.IsNotFound(err) {
+			return nil, nil, fmt.Errorf("failed to get the live object: %w", err)
+		}
+		if merged, err = nimClient.NIMCaches(namespace).Patch(ctx, name, types.ApplyPatchType, data, patchOptions); err != nil {
+			return nil, nil, fmt.Errorf("server-side dry run failed: %w", err)
+		}
+	}
+
+	mergedObject, err := cleaned(merged, local.GetKind())
+	if err != nil {
+		return nil, nil, err
+	}
+	if live == nil {
+		return nil, mergedObject, nil
+	}
+	liveObject, err := cleaned(live, local.GetKind())
+	if err != nil {
+		return nil, nil, err
+	}
+	return liveObject, mergedObject, nil
+}
+
+func cleaned(obj runtime.Object, kind string) (map[string]interface{}, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	u := &unstructured.Unstructured{Object: content}
+	u.SetAPIVersion(appsv1alpha1.SchemeGroupVersion.String())
+	u.SetKind(kind)
+	util.Clean(u, true)
+	return u.Object, nil
+}
+
+// Lists the NIMServices and NIMCaches in the namespaces the manifests cover that the manifests do not define.
+func findOnlyInCluster(ctx context.Context, k8sClient client.Client, scopes map[string]map[string]bool, local map[objectKey]bool) ([]objectKey, error) {
+	var keys []objectKey
+	nimClient := k8sClient.NIMClient().AppsV1alpha1()
+	for kind, namespaces := range scopes {
+		for namespace := range namespaces {
+			var names []string
+			if kind == "NIMService" {
+				list, err := nimClient.NIMServices(namespace).List(ctx, metav1.ListOptions{})
+				if err != nil {
+					return nil, fmt.Errorf("unable to retrieve NIMServices for namespace %s: %w", namespace, err)
+				}
+				for _, item := range list.Items {
+					names = append(names, item.Name)
+				}
+			} else {
+				list, err := nimClient.NIMCaches(namespace).List(ctx, metav1.ListOptions{})
+				if err != nil {
+					return nil, fmt.Errorf("unable to retrieve NIMCaches for namespace %s: %w", namespace, err)
+				}
+				for _, item := range list.Items {
+					names = append(names, item.Name)
+				}
+			}
+			for _, name := range names {
+				if key := (objectKey{kind, namespace, name}); !local[key] {
+					keys = append(keys, key)
+				}
+			}
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+	return keys, nil
+}
+
+func useColor(options *DiffOptions) bool {
+	switch options.Color {
+	case colorAlways:
+		return true
+	case colorNever:
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return term.TTY{Out: options.IoStreams.Out}.IsTerminalOut()
+}
+
+func toYAML(obj map[string]interface{}) (string, error) {
+	if obj == nil {
+		return "", nil
+	}
+	data, err := yaml.Marshal(obj)
+	return string(data), err
+}
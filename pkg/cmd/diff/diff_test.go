@@ -0,0 +1,229 @@
+package diff
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/yaml"
+
+	"k8s-nim-operator-cli/pkg/util"
+	"k8s-nim-operator-cli/pkg/util/client/fake"
+)
+
+const localNIMService = `apiVersion: apps.nvidia.com/v1alpha1
+kind: NIMService
+metadata:
+  name: llama3
+spec:
+  image:
+    repository: nvcr.io/nim/meta/llama3-8b-instruct
+    tag: 1.0.3
+  authSecret: ngc-api-secret
+  replicas: 2
+  storage:
+    nimCache:
+      name: llama3-cache
+`
+
+func newLiveNIMService(namespace, name string, replicas int) *appsv1alpha1.NIMService {
+	nimservice := &appsv1alpha1.NIMService{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, UID: "1234", ResourceVersion: "42"}}
+	nimservice.Spec.Image = appsv1alpha1.Image{Repository: "nvcr.io/nim/meta/llama3-8b-instruct", Tag: "1.0.3"}
+	nimservice.Spec.AuthSecret = "ngc-api-secret"
+	nimservice.Spec.Replicas = replicas
+	nimservice.Spec.Storage.NIMCache.Name = "llama3-cache"
+	nimservice.Status.State = "Ready"
+	return nimservice
+}
+
+// Serves server-side dry-run applies by returning the applied object with the CRD defaults, as the API server would
+// for the fields the manifest sets. Dry runs of objects named "rejected" fail.
+func newTestClient(t *testing.T, objects ...runtime.Object) *fake.Client {
+	client := fake.NewFakeClient(nil, objects)
+	client.NIM.PrependReactor("patch", "nimservices", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patch := action.(k8stesting.PatchActionImpl)
+		if patch.GetPatchType() != types.ApplyPatchType || len(patch.GetPatchOptions().DryRun) == 0 {
+			t.Errorf("expected a server-side dry-run apply, got %s %v", patch.GetPatchType(), patch.GetPatchOptions())
+		}
+		if patch.GetName() == "rejected" {
+			return true, nil, errors.New("admission webhook denied the request")
+		}
+		nimservice := &appsv1alpha1.NIMService{}
+		if err := yaml.Unmarshal(patch.GetPatch(), nimservice); err != nil {
+			return true, nil, err
+		}
+		if nimservice.Spec.Replicas == 0 {
+			nimservice.Spec.Replicas = 1
+		}
+		nimservice.UID = "dry-run"
+		return true, nimservice, nil
+	})
+	return client
+}
+
+func newTestOptions(in string, filenames ...string) (*DiffOptions, *bytes.Buffer, *bytes.Buffer) {
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	streams := genericclioptions.IOStreams{In: strings.NewReader(in), Out: out, ErrOut: errOut}
+	return &DiffOptions{IoStreams: &streams, Namespace: "nim", Filenames: filenames, Color: colorNever}, out, errOut
+}
+
+func exitCode(err error) int {
+	var exitErr *util.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	if err != nil {
+		return -1
+	}
+	return 0
+}
+
+func Test_Run_Drift(t *testing.T) {
+	client := newTestClient(t, newLiveNIMService("nim", "llama3", 1))
+	options, out, _ := newTestOptions(localNIMService, "-")
+
+	err := Run(context.Background(), options, client)
+	if exitCode(err) != exitDrift {
+		t.Fatalf("expected exit code %d, got %v", exitDrift, err)
+	}
+
+	want := `--- live/NIMService/nim/llama3
++++ merged/NIMService/nim/llama3
+@@ -8,6 +8,7 @@
+   image:
+     repository: nvcr.io/nim/meta/llama3-8b-instruct
+     tag: 1.0.3
++  replicas: 2
+   storage:
+     nimCache:
+       name: llama3-cache
+`
+	if out.String() != want {
+		t.Fatalf("unexpected diff:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func Test_Run_NoDrift(t *testing.T) {
+	client := newTestClient(t, newLiveNIMService("nim", "llama3", 2))
+	options, out, _ := newTestOptions(localNIMService, "-")
+
+	if err := Run(context.Background(), options, client); err != nil {
+		t.Fatalf("expected no drift, got %v", err)
+	}
+	if out.String() != "No differences.\n" {
+		t.Fatalf("unexpected output: %s", out.String())
+	}
+}
+
+func Test_Run_OnlyLocalAndOnlyCluster(t *testing.T) {
+	dir := t.TempDir()
+	manifest := strings.Replace(localNIMService, "name: llama3\n", "name: llama3\n  namespace: staging\n", 1)
+	if err := os.WriteFile(filepath.Join(dir, "llama3.yaml"), []byte(manifest), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	client := newTestClient(t, newLiveNIMService("staging", "mistral", 1), newLiveNIMService("other", "gemma", 1))
+	options, out, _ := newTestOptions("", dir)
+
+	err := Run(context.Background(), options, client)
+	if exitCode(err) != exitDrift {
+		t.Fatalf("expected exit code %d, got %v", exitDrift, err)
+	}
+	got := out.String()
+	for _, want := range []string{
+		"--- /dev/null\n+++ merged/NIMService/staging/llama3\n",
+		"+kind: NIMService\n",
+		"Only local, would be created:\n  NIMService staging/llama3\n",
+		"Only in the cluster:\n  NIMService staging/mistral\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output:\n%s", want, got)
+		}
+	}
+	// Only the namespaces the manifests cover are searched.
+	if strings.Contains(got, "gemma") {
+		t.Errorf("objects in other namespaces should not be listed:\n%s", got)
+	}
+}
+
+func Test_Run_Errors(t *testing.T) {
+	client := newTestClient(t)
+	in := strings.Replace(localNIMService, "name: llama3", "name: rejected", 1) + "---\n" + `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+`
+	options, _, errOut := newTestOptions(in, "-")
+
+	err := Run(context.Background(), options, client)
+	if exitCode(err) != exitError {
+		t.Fatalf("expected exit code %d, got %v", exitError, err)
+	}
+	for _, want := range []string{
+		"<stdin>: NIMService nim/rejected: server-side dry run failed: admission webhook denied the request",
+		"<stdin>: ConfigMap/settings: skipped",
+	} {
+		if !strings.Contains(errOut.String(), want) {
+			t.Errorf("expected %q in errors:\n%s", want, errOut.String())
+		}
+	}
+
+	options, _, _ = newTestOptions("", filepath.Join(t.TempDir(), "missing.yaml"))
+	if err := Run(context.Background(), options, client); exitCode(err) != exitError {
+		t.Errorf("expected exit code %d for a missing file, got %v", exitError, err)
+	}
+}
+
+func Test_Run_Color(t *testing.T) {
+	client := newTestClient(t, newLiveNIMService("nim", "llama3", 3))
+	options, out, _ := newTestOptions(localNIMService, "-")
+	options.Color = colorAlways
+
+	if err := Run(context.Background(), options, client); exitCode(err) != exitDrift {
+		t.Fatalf("expected drift, got %v", err)
+	}
+	for _, want := range []string{
+		ansiRed + "-  replicas: 3" + ansiReset,
+		ansiGreen + "+  replicas: 2" + ansiReset,
+		ansiCyan + "@@",
+		ansiBold + "--- live/NIMService/nim/llama3" + ansiReset,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in output:\n%s", fmt.Sprintf("%q", want), out.String())
+		}
+	}
+}
+
+func Test_NewDiffCommand_ExitCodes(t *testing.T) {
+	cases := map[string]struct {
+		args    []string
+		preRunE error
+	}{
+		"invalid color":    {[]string{"diff", "-f", "deploy/", "--color=sometimes"}, nil},
+		"unknown flag":     {[]string{"diff", "--bogus"}, nil},
+		"unknown argument": {[]string{"diff", "deploy/"}, nil},
+		"config failure":   {[]string{"diff", "-f", "deploy/"}, errors.New("failed to read config")},
+	}
+	for name, tc := range cases {
+		streams := genericclioptions.IOStreams{In: &bytes.Buffer{}, Out: &bytes.Buffer{}, ErrOut: &bytes.Buffer{}}
+		root := &cobra.Command{Use: "nim", SilenceErrors: true, PersistentPreRunE: func(*cobra.Command, []string) error { return tc.preRunE }}
+		root.SetOut(streams.Out)
+		root.SetErr(streams.ErrOut)
+		root.AddCommand(NewDiffCommand(nil, streams))
+		root.SetArgs(tc.args)
+		if err := root.Execute(); exitCode(err) != exitError {
+			t.Errorf("%s: expected exit code %d, got %v", name, exitError, err)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+const (
+	ansiReset = "\x1b[0m"
+	ansiBold  = "\x1b[1m"
+	ansiRed   = "\x1b[31m"
+	ansiGreen = "\x1b[32m"
+	ansiCyan  = "\x1b[36m"
+)
+
+type diffPrinter struct {
+	out   io.Writer
+	color bool
+}
+
+// Prints the unified diff from live to merged as YAML, and reports whether they differ. A nil live object diffs from
+// /dev/null.
+func (p *diffPrinter) print(key objectKey, live, merged map[string]interface{}) (bool, error) {
+	liveYAML, err := toYAML(live)
+	if err != nil {
+		return false, err
+	}
+	mergedYAML, err := toYAML(merged)
+	if err != nil {
+		return false, err
+	}
+	if liveYAML == mergedYAML {
+		return false, nil
+	}
+
+	path := fmt.Sprintf("%s/%s/%s", key.kind, key.namespace, key.name)
+	fromFile := "live/" + path
+	if live == nil {
+		fromFile = "/dev/null"
+	}
+	text, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(liveYAML),
+		B:        difflib.SplitLines(mergedYAML),
+		FromFile: fromFile,
+		ToFile:   "merged/" + path,
+		Context:  3,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	for _, line := range strings.SplitAfter(text, "\n") {
+		if line == "" {
+			continue
+		}
+		if !p.color {
+			fmt.Fprint(p.out, line)
+			continue
+		}
+		content := strings.TrimSuffix(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "---"), strings.HasPrefix(line, "+++"):
+			content = ansiBold + content + ansiReset
+		case strings.HasPrefix(line, "@@"):
+			content = ansiCyan + content + ansiReset
+		case strings.HasPrefix(line, "-"):
+			content = ansiRed + content + ansiReset
+		case strings.HasPrefix(line, "+"):
+			content = ansiGreen + content + ansiReset
+		}
+		fmt.Fprintln(p.out, content)
+	}
+	return true, nil
+}
@@ -0,0 +1,331 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	"k8s-nim-operator-cli/pkg/util"
+	"k8s-nim-operator-cli/pkg/util/client"
+)
+
+type EventsOptions struct {
+	*util.FetchResourceOptions
+	Watch bool
+}
+
+func NewEventsCommand(cmdFactory cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	options := &EventsOptions{FetchResourceOptions: util.NewFetchResourceOptions(cmdFactory, streams)}
+
+	cmd := &cobra.Command{
+		Use:   "events RESOURCE NAME",
+		Short: "Show the events of a NIMService or NIMCache and everything it owns",
+		Long: `Show the Kubernetes events of a NIMService or NIMCache together with those of the objects it owns, found by walking
+ownerReferences: Deployments, ReplicaSets, StatefulSets, Jobs, Pods, Services, PVCs, ConfigMaps, ServiceAccounts,
+HorizontalPodAutoscalers and Ingresses, and for a multi-node NIMService its LeaderWorkerSet. Reconcile failures of the
+operator and scheduling, image pull and probe failures of the pods show up here.
+
+Events are sorted oldest first. Repeats of an event on the same object are shown once, with how often and over how long
+they occurred. With -w, new events are printed as they arrive, including those of pods created later.`,
+		Example: `  nim events nimservice llama3 -n nim
+  nim events nimcache llama3-cache -n nim -w`,
+		SilenceUsage: true,
+		Args:         cobra.MaximumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				cmd.HelpFunc()(cmd, args)
+				return nil
+			}
+			switch util.ResourceType(strings.TrimSuffix(strings.ToLower(args[0]), "s")) {
+			case util.NIMService:
+				options.ResourceType = util.NIMService
+			case util.NIMCache:
+				options.ResourceType = util.NIMCache
+			default:
+				return fmt.Errorf("invalid resource type %q. Valid types are: nimservice, nimcache", args[0])
+			}
+			if len(args) != 2 {
+				return fmt.Errorf("a %s name is required", options.ResourceType)
+			}
+			if err := options.CompleteNamespace(args[1:], cmd); err != nil {
+				return err
+			}
+			k8sClient, err := client.NewClient(cmdFactory)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			return Run(cmd.Context(), options, k8sClient)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&options.Watch, "watch", "w", false, "After listing the events, watch for new ones.")
+
+	return cmd
+}
+
+func Run(ctx context.Context, options *EventsOptions, k8sClient client.Client) error {
+	r, err := getRoot(ctx, options, k8sClient)
+	if err != nil {
+		return err
+	}
+	kube := k8sClient.KubernetesClient()
+	set := findOwned(ctx, kube, options.Namespace, r, options.IoStreams.ErrOut)
+
+	list, err := kube.CoreV1().Events(options.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list events: %w", err)
+	}
+	agg := &aggregator{rows: map[string]*row{}, counts: map[string]int32{}}
+	for i := range list.Items {
+		if set.contains(list.Items[i].InvolvedObject) {
+			agg.add(&list.Items[i])
+		}
+	}
+
+	p := &eventPrinter{out: options.IoStreams.Out, now: time.Now}
+	if rows := agg.sorted(); len(rows) > 0 {
+		if err := p.print(rows); err != nil {
+			return err
+		}
+	} else if !options.Watch {
+		fmt.Fprintf(options.IoStreams.ErrOut, "No events found for %s %s/%s.\n", r.kind, options.Namespace, r.name)
+		return nil
+	}
+	if !options.Watch {
+		return nil
+	}
+
+	w := &eventWatcher{options: options, k8sClient: k8sClient, root: r, set: set, agg: agg, printer: p, checked: map[types.UID]bool{}}
+	return w.run(ctx, list.ResourceVersion)
+}
+
+func getRoot(ctx context.Context, options *EventsOptions, k8sClient client.Client) (*root, error) {
+	nimClient := k8sClient.NIMClient().AppsV1alpha1()
+	if options.ResourceType == util.NIMCache {
+		nimCache, err := nimClient.NIMCaches(options.Namespace).Get(ctx, options.ResourceName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("NIMCache %s not found in namespace %s", options.ResourceName, options.Namespace)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to retrieve NIMCache %s: %w", options.ResourceName, err)
+		}
+		return &root{kind: "NIMCache", name: nimCache.Name, uid: nimCache.UID}, nil
+	}
+
+	nimService, err := nimClient.NIMServices(options.Namespace).Get(ctx, options.ResourceName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("NIMService %s not found in namespace %s", options.ResourceName, options.Namespace)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve NIMService %s: %w", options.ResourceName, err)
+	}
+	r := &root{kind: "NIMService", name: nimService.Name, uid: nimService.UID}
+	if util.IsMultiNode(nimService) {
+		r.lwsName = nimService.GetLWSName()
+	}
+	return r, nil
+}
+
+// Events on one object with the same type, reason and message, merged into one row.
+type row struct {
+	object, eventType, reason, message string
+	count                              int32
+	first, last                        time.Time
+}
+
+type aggregator struct {
+	rows map[string]*row
+	// The count each event contributed so far, by event name, so an updated event only adds its new occurrences.
+	counts map[string]int32
+}
+
+// Merges the event into its row and reports whether the row changed.
+func (a *aggregator) add(event *corev1.Event) (*row, bool) {
+	object := objectName(event.InvolvedObject.Kind, event.InvolvedObject.Name)
+	message := strings.TrimSpace(event.Message)
+	key := strings.Join([]string{object, event.Type, event.Reason, message}, "\x00")
+	first, last := eventTimes(event)
+
+	r, ok := a.rows[key]
+	if !ok {
+		r = &row{object: object, eventType: event.Type, reason: event.Reason, message: message, first: first, last: last}
+		a.rows[key] = r
+	}
+	changed := !ok
+
+	count := max(event.Count, 1)
+	if previous := a.counts[event.Name]; count > previous {
+		r.count += count - previous
+		a.counts[event.Name] = count
+		changed = true
+	}
+	if first.Before(r.first) {
+		r.first = first
+	}
+	if last.After(r.last) {
+		r.last = last
+		changed = true
+	}
+	return r, changed
+}
+
+// Rows oldest first, by when they were last seen.
+func (a *aggregator) sorted() []*row {
+	rows := make([]*row, 0, len(a.rows))
+	for _, r := range a.rows {
+		rows = append(rows, r)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if !rows[i].last.Equal(rows[j].last) {
+			return rows[i].last.Before(rows[j].last)
+		}
+		if !rows[i].first.Equal(rows[j].first) {
+			return rows[i].first.Before(rows[j].first)
+		}
+		return rows[i].object < rows[j].object
+	})
+	return rows
+}
+
+// Returns when the event was first and last seen. Events from the events.k8s.io API set eventTime and a series
+// instead of the timestamps.
+func eventTimes(event *corev1.Event) (time.Time, time.Time) {
+	first := event.FirstTimestamp.Time
+	if first.IsZero() {
+		first = event.EventTime.Time
+	}
+	if first.IsZero() {
+		first = event.CreationTimestamp.Time
+	}
+	last := event.LastTimestamp.Time
+	if event.Series != nil && event.Series.LastObservedTime.After(last) {
+		last = event.Series.LastObservedTime.Time
+	}
+	if last.IsZero() {
+		last = first
+	}
+	return first, last
+}
+
+type eventPrinter struct {
+	out           io.Writer
+	now           func() time.Time
+	headerPrinted bool
+}
+
+func (p *eventPrinter) print(rows []*row) error {
+	table := &metav1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Last Seen", Type: "string"},
+			{Name: "Type", Type: "string"},
+			{Name: "Reason", Type: "string"},
+			{Name: "Object", Type: "string"},
+			{Name: "Message", Type: "string"},
+		},
+	}
+	for _, r := range rows {
+		table.Rows = append(table.Rows, metav1.TableRow{
+			Cells: []interface{}{p.lastSeen(r), r.eventType, r.reason, r.object, r.message},
+		})
+	}
+	printer := printers.NewTablePrinter(printers.PrintOptions{NoHeaders: p.headerPrinted})
+	p.headerPrinted = true
+	return printer.PrintObj(table, p.out)
+}
+
+// Like kubectl: the age of the last occurrence, and for repeats how often and over how long.
+func (p *eventPrinter) lastSeen(r *row) string {
+	now := p.now()
+	lastSeen := duration.HumanDuration(now.Sub(r.last))
+	if r.count > 1 {
+		return fmt.Sprintf("%s (x%d over %s)", lastSeen, r.count, duration.HumanDuration(now.Sub(r.first)))
+	}
+	return lastSeen
+}
+
+type eventWatcher struct {
+	options   *EventsOptions
+	k8sClient client.Client
+	root      *root
+	set       *objectSet
+	agg       *aggregator
+	printer   *eventPrinter
+	// Objects of the owned kinds already found not to belong to the root, so they are not walked again.
+	checked map[types.UID]bool
+}
+
+// Prints new and repeated events until ctx is done, watching again from the last seen version when the server
+// closes the watch.
+func (w *eventWatcher) run(ctx context.Context, resourceVersion string) error {
+	events := w.k8sClient.KubernetesClient().CoreV1().Events(w.options.Namespace)
+	for {
+		watcher, err := events.Watch(ctx, metav1.ListOptions{ResourceVersion: resourceVersion})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to watch events: %w", err)
+		}
+		for open := true; open; {
+			select {
+			case <-ctx.Done():
+				watcher.Stop()
+				return nil
+			case e, ok := <-watcher.ResultChan():
+				if !ok {
+					open = false
+					break
+				}
+				switch e.Type {
+				case watch.Error:
+					watcher.Stop()
+					return fmt.Errorf("failed to watch events: %w", apierrors.FromObject(e.Object))
+				case watch.Added, watch.Modified:
+					event, ok := e.Object.(*corev1.Event)
+					if !ok {
+						continue
+					}
+					resourceVersion = event.ResourceVersion
+					if err := w.handle(ctx, event); err != nil {
+						watcher.Stop()
+						return err
+					}
+				}
+			}
+		}
+		watcher.Stop()
+	}
+}
+
+func (w *eventWatcher) handle(ctx context.Context, event *corev1.Event) error {
+	ref := event.InvolvedObject
+	if !w.set.contains(ref) {
+		// Pods and ReplicaSets created since the walk belong to the root as well: walk again once per new object.
+		if !isOwnedKind(ref.Kind) || ref.UID == "" || w.checked[ref.UID] {
+			return nil
+		}
+		w.checked[ref.UID] = true
+		w.set = findOwned(ctx, w.k8sClient.KubernetesClient(), w.options.Namespace, w.root, io.Discard)
+		if !w.set.contains(ref) {
+			return nil
+		}
+	}
+	if r, changed := w.agg.add(event); changed {
+		return w.printer.print([]*row{r})
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"k8s-nim-operator-cli/pkg/util"
+	"k8s-nim-operator-cli/pkg/util/client/fake"
+)
+
+// A bytes.Buffer safe to read while a watch writes to it.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func newTestOptions(resourceType util.ResourceType, name string, out, errOut interface{ Write([]byte) (int, error) }) *EventsOptions {
+	streams := genericclioptions.IOStreams{In: &bytes.Buffer{}, Out: out, ErrOut: errOut}
+	options := &EventsOptions{FetchResourceOptions: util.NewFetchResourceOptions(nil, streams)}
+	options.Namespace = "nim"
+	options.ResourceType = resourceType
+	options.ResourceName = name
+	return options
+}
+
+func meta(name, uid string, owner metav1.Object) metav1.ObjectMeta {
+	m := metav1.ObjectMeta{Name: name, Namespace: "nim", UID: types.UID(uid)}
+	if owner != nil {
+		m.OwnerReferences = []metav1.OwnerReference{{Name: owner.GetName(), UID: owner.GetUID()}}
+	}
+	return m
+}
+
+func newEvent(name string, involved metav1.Object, kind, eventType, reason, message string, count int32, first, last time.Duration) *corev1.Event {
+	now := time.Now()
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "nim"},
+		InvolvedObject: corev1.ObjectReference{Kind: kind, Name: involved.GetName(), Namespace: "nim", UID: involved.GetUID()},
+		Type:           eventType,
+		Reason:         reason,
+		Message:        message,
+		Count:          count,
+		FirstTimestamp: metav1.NewTime(now.Add(-first)),
+		LastTimestamp:  metav1.NewTime(now.Add(-last)),
+	}
+}
+
+// A NIMService with a Deployment, ReplicaSet, Pod and Service, and a pod of something else.
+func newNIMServiceObjects() (*appsv1alpha1.NIMService, *appsv1.ReplicaSet, []runtime.Object) {
+	nimService := &appsv1alpha1.NIMService{ObjectMeta: meta("llama3", "nimservice-uid", nil)}
+	deployment := &appsv1.Deployment{ObjectMeta: meta("llama3", "deployment-uid", nimService)}
+	replicaSet := &appsv1.ReplicaSet{ObjectMeta: meta("llama3-5d4f", "replicaset-uid", deployment)}
+	// Listed before its owners, so the walk has to go round again.
+	pod := &corev1.Pod{ObjectMeta: meta("llama3-5d4f-x2x9z", "pod-uid", replicaSet)}
+	service := &corev1.Service{ObjectMeta: meta("llama3", "service-uid", nimService)}
+	other := &corev1.Pod{ObjectMeta: meta("mistral-6c7d-abcde", "other-uid", nil)}
+
+	kubeObjects := []runtime.Object{
+		pod, deployment, replicaSet, service, other,
+		newEvent("llama3.1", nimService, "NIMService", "Warning", "ReconcileFailed", "failed to create Deployment: quota exceeded\n", 1, 20*time.Minute, 20*time.Minute),
+		newEvent("llama3.2", deployment, "Deployment", "Normal", "ScalingReplicaSet", "Scaled up replica set llama3-5d4f to 1", 1, 15*time.Minute, 15*time.Minute),
+		// Two event objects with the same reason and message on the same pod are one row.
+		newEvent("llama3-5d4f-x2x9z.1", pod, "Pod", "Warning", "BackOff", "Back-off pulling image", 3, 12*time.Minute, 8*time.Minute),
+		newEvent("llama3-5d4f-x2x9z.2", pod, "Pod", "Warning", "BackOff", "Back-off pulling image", 2, 7*time.Minute, 5*time.Minute),
+		newEvent("llama3.3", service, "Service", "Normal", "Created", "Created service", 1, 10*time.Minute, 10*time.Minute),
+		newEvent("mistral.1", other, "Pod", "Warning", "Failed", "unrelated", 1, 6*time.Minute, 6*time.Minute),
+	}
+	return nimService, replicaSet, kubeObjects
+}
+
+func Test_Run(t *testing.T) {
+	nimService, _, kubeObjects := newNIMServiceObjects()
+	client := fake.NewFakeClient(kubeObjects, []runtime.Object{nimService})
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+
+	if err := Run(context.Background(), newTestOptions(util.NIMService, "llama3", out, errOut), client); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `LAST SEEN          TYPE      REASON              OBJECT                  MESSAGE
+20m                Warning   ReconcileFailed     NIMService/llama3       failed to create Deployment: quota exceeded
+15m                Normal    ScalingReplicaSet   Deployment/llama3       Scaled up replica set llama3-5d4f to 1
+10m                Normal    Created             Service/llama3          Created service
+5m (x5 over 12m)   Warning   BackOff             Pod/llama3-5d4f-x2x9z   Back-off pulling image
+`
+	if out.String() != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", out.String(), want)
+	}
+	if errOut.Len() != 0 {
+		t.Errorf("unexpected errors: %s", errOut.String())
+	}
+}
+
+func Test_Run_MultiNode(t *testing.T) {
+	nimService := &appsv1alpha1.NIMService{ObjectMeta: meta("llama3", "nimservice-uid", nil)}
+	nimService.Spec.MultiNode = &appsv1alpha1.NimServiceMultiNodeConfig{}
+	lws := &metav1.ObjectMeta{Name: nimService.GetLWSName(), UID: "lws-uid"}
+	statefulSet := &appsv1.StatefulSet{ObjectMeta: meta("llama3-lws-0", "statefulset-uid", nil)}
+	statefulSet.Labels = map[string]string{util.LWSNameLabel: nimService.GetLWSName()}
+	worker := &corev1.Pod{ObjectMeta: meta("llama3-lws-0-1", "worker-uid", statefulSet)}
+
+	client := fake.NewFakeClient([]runtime.Object{statefulSet, worker,
+		newEvent("lws.1", lws, "LeaderWorkerSet", "Normal", "GroupsProgressing", "Creating leader statefulset", 1, 9*time.Minute, 9*time.Minute),
+		newEvent("worker.1", worker, "Pod", "Warning", "FailedScheduling", "0/4 nodes are available: insufficient nvidia.com/gpu", 1, 3*time.Minute, 3*time.Minute),
+	}, []runtime.Object{nimService})
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+
+	if err := Run(context.Background(), newTestOptions(util.NIMService, "llama3", out, errOut), client); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"LeaderWorkerSet/llama3-lws", "Pod/llama3-lws-0-1"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in output:\n%s", want, out.String())
+		}
+	}
+}
+
+func Test_Run_NIMCacheNoEvents(t *testing.T) {
+	nimCache := &appsv1alpha1.NIMCache{ObjectMeta: meta("llama3-cache", "nimcache-uid", nil)}
+	client := fake.NewFakeClient(nil, []runtime.Object{nimCache})
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+
+	if err := Run(context.Background(), newTestOptions(util.NIMCache, "llama3-cache", out, errOut), client); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Len() != 0 || errOut.String() != "No events found for NIMCache nim/llama3-cache.\n" {
+		t.Errorf("unexpected output %q and errors %q", out.String(), errOut.String())
+	}
+
+	err := Run(context.Background(), newTestOptions(util.NIMCache, "missing", out, errOut), client)
+	if err == nil || err.Error() != "NIMCache missing not found in namespace nim" {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
+
+func Test_Run_Watch(t *testing.T) {
+	nimService, replicaSet, kubeObjects := newNIMServiceObjects()
+	client := fake.NewFakeClient(kubeObjects, []runtime.Object{nimService})
+	out, errOut := &syncBuffer{}, &syncBuffer{}
+	options := newTestOptions(util.NIMService, "llama3", out, errOut)
+	options.Watch = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- Run(ctx, options, client) }()
+
+	waitFor := func(what string, condition func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for !condition() {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %s; output:\n%s", what, out.String())
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	waitFor("the watch", func() bool {
+		for _, action := range client.Kube.Actions() {
+			if action.GetVerb() == "watch" && action.GetResource().Resource == "events" {
+				return true
+			}
+		}
+		return false
+	})
+	initial := len(out.String())
+
+	// A pod created after the walk, and a pod of something else.
+	ctx2 := context.Background()
+	newPod := &corev1.Pod{ObjectMeta: meta("llama3-5d4f-n8k2p", "new-pod-uid", replicaSet)}
+	other := &corev1.Pod{ObjectMeta: meta("gemma-1", "gemma-uid", nil)}
+	for _, pod := range []*corev1.Pod{newPod, other} {
+		if _, err := client.Kube.CoreV1().Pods("nim").Create(ctx2, pod, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := client.Kube.CoreV1().Events("nim").Create(ctx2, newEvent("gemma.1", other, "Pod", "Warning", "Failed", "unrelated", 1, 0, 0), metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	event := newEvent("llama3-5d4f-n8k2p.1", newPod, "Pod", "Warning", "Unhealthy", "Readiness probe failed", 1, 3*time.Minute, 3*time.Minute)
+	if _, err := client.Kube.CoreV1().Events("nim").Create(ctx2, event, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	waitFor("the new pod's event", func() bool { return strings.Contains(out.String()[initial:], "Readiness probe failed") })
+
+	// The same event repeating is printed again with its count.
+	event.Count = 2
+	event.LastTimestamp = metav1.NewTime(time.Now().Add(-2 * time.Minute))
+	if _, err := client.Kube.CoreV1().Events("nim").Update(ctx2, event, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	waitFor("the repeat", func() bool { return strings.Contains(out.String(), "(x2 over 3m)") })
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	watched := out.String()[initial:]
+	if strings.Contains(watched, "LAST SEEN") || strings.Contains(watched, "unrelated") {
+		t.Errorf("unexpected watch output:\n%s", watched)
+	}
+	if strings.Count(watched, "\n") != 2 {
+		t.Errorf("expected one line per change:\n%s", watched)
+	}
+}
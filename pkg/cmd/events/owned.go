@@ -0,0 +1,207 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"k8s-nim-operator-cli/pkg/util"
+)
+
+// An object of the namespace, as far as ownership is concerned.
+type object struct {
+	kind string
+	metav1.Object
+}
+
+// The CR and the objects it owns, directly or through other owned objects.
+type objectSet struct {
+	uids map[types.UID]bool
+	// Kind/name of every member, for events without a UID.
+	names map[string]bool
+	// Kind/name of members whose UID is unknown, like the LeaderWorkerSet, which is not listed.
+	withoutUID map[string]bool
+}
+
+func objectName(kind, name string) string {
+	return kind + "/" + name
+}
+
+func (s *objectSet) add(kind, name string, uid types.UID) {
+	if uid != "" {
+		s.uids[uid] = true
+	} else {
+		s.withoutUID[objectName(kind, name)] = true
+	}
+	s.names[objectName(kind, name)] = true
+}
+
+func (s *objectSet) contains(ref corev1.ObjectReference) bool {
+	name := objectName(ref.Kind, ref.Name)
+	if ref.UID != "" {
+		return s.uids[ref.UID] || s.withoutUID[name]
+	}
+	return s.names[name]
+}
+
+// The kinds the operator creates for a NIMService or NIMCache, and the pods and ReplicaSets they create in turn.
+var ownedKinds = []struct {
+	kind string
+	list func(ctx context.Context, kube kubernetes.Interface, namespace string) ([]object, error)
+}{
+	{"Deployment", func(ctx context.Context, kube kubernetes.Interface, namespace string) ([]object, error) {
+		list, err := kube.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return toObjects("Deployment", list.Items), nil
+	}},
+	{"ReplicaSet", func(ctx context.Context, kube kubernetes.Interface, namespace string) ([]object, error) {
+		list, err := kube.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return toObjects("ReplicaSet", list.Items), nil
+	}},
+	{"StatefulSet", func(ctx context.Context, kube kubernetes.Interface, namespace string) ([]object, error) {
+		list, err := kube.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return toObjects("StatefulSet", list.Items), nil
+	}},
+	{"Job", func(ctx context.Context, kube kubernetes.Interface, namespace string) ([]object, error) {
+		list, err := kube.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return toObjects("Job", list.Items), nil
+	}},
+	{"Pod", func(ctx context.Context, kube kubernetes.Interface, namespace string) ([]object, error) {
+		list, err := kube.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return toObjects("Pod", list.Items), nil
+	}},
+	{"Service", func(ctx context.Context, kube kubernetes.Interface, namespace string) ([]object, error) {
+		list, err := kube.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return toObjects("Service", list.Items), nil
+	}},
+	{"PersistentVolumeClaim", func(ctx context.Context, kube kubernetes.Interface, namespace string) ([]object, error) {
+		list, err := kube.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return toObjects("PersistentVolumeClaim", list.Items), nil
+	}},
+	{"ConfigMap", func(ctx context.Context, kube kubernetes.Interface, namespace string) ([]object, error) {
+		list, err := kube.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return toObjects("ConfigMap", list.Items), nil
+	}},
+	{"ServiceAccount", func(ctx context.Context, kube kubernetes.Interface, namespace string) ([]object, error) {
+		list, err := kube.CoreV1().ServiceAccounts(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return toObjects("ServiceAccount", list.Items), nil
+	}},
+	{"HorizontalPodAutoscaler", func(ctx context.Context, kube kubernetes.Interface, namespace string) ([]object, error) {
+		list, err := kube.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return toObjects("HorizontalPodAutoscaler", list.Items), nil
+	}},
+	{"Ingress", func(ctx context.Context, kube kubernetes.Interface, namespace string) ([]object, error) {
+		list, err := kube.NetworkingV1().Ingresses(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return toObjects("Ingress", list.Items), nil
+	}},
+}
+
+func toObjects[T any, P interface {
+	*T
+	metav1.Object
+}](kind string, items []T) []object {
+	objects := make([]object, len(items))
+	for i := range items {
+		objects[i] = object{kind, P(&items[i])}
+	}
+	return objects
+}
+
+func isOwnedKind(kind string) bool {
+	for _, k := range ownedKinds {
+		if k.kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// The resource whose events are shown: the CR, and for a multi-node NIMService the LeaderWorkerSet between it and its
+// StatefulSets. The LeaderWorkerSet is not served by the typed clients, so what it owns is found by its label.
+type root struct {
+	kind, name string
+	uid        types.UID
+	lwsName    string
+}
+
+// Walks ownerReferences down from the root through the objects of the namespace. Kinds that cannot be listed, for
+// example for lack of RBAC, are reported on warnings and skipped.
+func findOwned(ctx context.Context, kube kubernetes.Interface, namespace string, r *root, warnings io.Writer) *objectSet {
+	set := &objectSet{uids: map[types.UID]bool{}, names: map[string]bool{}, withoutUID: map[string]bool{}}
+	set.add(r.kind, r.name, r.uid)
+	if r.lwsName != "" {
+		set.add("LeaderWorkerSet", r.lwsName, "")
+	}
+
+	var candidates []object
+	for _, k := range ownedKinds {
+		objects, err := k.list(ctx, kube, namespace)
+		if err != nil {
+			fmt.Fprintf(warnings, "Warning: unable to list %s objects, their events are not shown: %v\n", k.kind, err)
+			continue
+		}
+		candidates = append(candidates, objects...)
+	}
+
+	// Repeat until nothing is added, since an object may be listed before its owner is found.
+	for added := true; added; {
+		added = false
+		for _, obj := range candidates {
+			if set.names[objectName(obj.kind, obj.GetName())] || !r.owns(set, obj) {
+				continue
+			}
+			set.add(obj.kind, obj.GetName(), obj.GetUID())
+			added = true
+		}
+	}
+	return set
+}
+
+func (r *root) owns(set *objectSet, obj object) bool {
+	if r.lwsName != "" && obj.GetLabels()[util.LWSNameLabel] == r.lwsName {
+		return true
+	}
+	for _, owner := range obj.GetOwnerReferences() {
+		if set.uids[owner.UID] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,260 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/yaml"
+
+	"k8s-nim-operator-cli/pkg/util"
+	"k8s-nim-operator-cli/pkg/util/client"
+	nimconfig "k8s-nim-operator-cli/pkg/util/config"
+)
+
+const (
+	formatYAML      = "yaml"
+	formatKustomize = "kustomize"
+	formatHelm      = "helm"
+)
+
+type ExportOptions struct {
+	*util.FetchResourceOptions
+	Output        string
+	OutputDir     string
+	KeepNamespace bool
+	Overlays      []string
+	ChartName     string
+}
+
+func NewExportCommand(cmdFactory cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	options := &ExportOptions{FetchResourceOptions: util.NewFetchResourceOptions(cmdFactory, streams)}
+
+	cmd := &cobra.Command{
+		Use:   "export RESOURCE [NAME]",
+		Short: "Export NIMServices or NIMCaches as portable manifests",
+		Long: `Export NIMServices or NIMCaches as manifests that can be applied to another cluster or committed to a GitOps
+repository. Without NAME every object of the type in the namespace is exported, or in every namespace with -A.
+
+Status, server-set metadata (uid, resourceVersion, generation, creationTimestamp, managedFields, ownerReferences), the
+operator's finalizers, kubectl's last-applied annotation and fields holding their CRD default are stripped. The namespace
+is dropped unless --keep-namespace or -A is set.
+
+Output formats:
+  yaml       The objects as YAML documents, on standard output or one file per object in --output-dir.
+  kustomize  A base with the objects and an overlay per --overlay in --output-dir. An overlay given as NAME=NAMESPACE
+             sets that namespace; otherwise it uses the exported namespace.
+  helm       A minimal chart in --output-dir, deploying to the release namespace, with each NIMService's image and
+             replicas in values.yaml.`,
+		Example: `  nim export nimservice llama3 -n staging > llama3.yaml
+  nim export nimservice -n staging -o kustomize --output-dir deploy/llama3 --overlay staging --overlay prod=nim-prod
+  nim export nimservice llama3 -n staging -o helm --output-dir charts/llama3
+  nim export nimcache -A`,
+		SilenceUsage: true,
+		Args:         cobra.MaximumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				cmd.HelpFunc()(cmd, args)
+				return nil
+			}
+			switch util.ResourceType(strings.TrimSuffix(strings.ToLower(args[0]), "s")) {
+			case util.NIMService:
+				options.ResourceType = util.NIMService
+			case util.NIMCache:
+				options.ResourceType = util.NIMCache
+			default:
+				return fmt.Errorf("invalid resource type %q. Valid types are: nimservice, nimcache", args[0])
+			}
+			if err := options.CompleteNamespace(args[1:], cmd); err != nil {
+				return err
+			}
+			if err := options.Validate(); err != nil {
+				return err
+			}
+			k8sClient, err := client.NewClient(cmdFactory)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			return Run(cmd.Context(), options, k8sClient)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&options.AllNamespaces, "all-namespaces", "A", false, "If present, export the requested objects across all namespaces, keeping their namespaces.")
+	cmd.Flags().StringVarP(&options.Output, "output", "o", formatYAML, "Output format. One of: yaml, kustomize, helm.")
+	cmd.Flags().StringVar(&options.OutputDir, "output-dir", "", "Directory to write the files to. Required for kustomize and helm.")
+	cmd.Flags().BoolVar(&options.KeepNamespace, "keep-namespace", false, "Keep metadata.namespace in the exported objects.")
+	cmd.Flags().StringArrayVar(&options.Overlays, "overlay", []string{"staging", "prod"}, "Kustomize overlay as NAME or NAME=NAMESPACE. Can be repeated.")
+	cmd.Flags().StringVar(&options.ChartName, "chart-name", "", "Name of the Helm chart. Defaults to the exported object's name, or the namespace.")
+
+	nimconfig.BindOutput(cmd.Flags(), formatYAML, formatKustomize, formatHelm)
+
+	return cmd
+}
+
+func (options *ExportOptions) Validate() error {
+	switch options.Output {
+	case formatYAML:
+	case formatKustomize, formatHelm:
+		if options.OutputDir == "" {
+			return fmt.Errorf("--output-dir is required with -o %s", options.Output)
+		}
+	default:
+		return fmt.Errorf("unsupported output format %q. Valid formats are: yaml, kustomize, helm", options.Output)
+	}
+	for _, overlay := range options.Overlays {
+		if name, _, _ := strings.Cut(overlay, "="); name == "" || strings.ContainsAny(name, `/\`) {
+			return fmt.Errorf("invalid overlay %q, must be NAME or NAME=NAMESPACE", overlay)
+		}
+	}
+	return nil
+}
+
+func Run(ctx context.Context, options *ExportOptions, k8sClient client.Client) error {
+	objects, err := fetchObjects(ctx, options.FetchResourceOptions, k8sClient)
+	if err != nil {
+		return err
+	}
+	if len(objects) == 0 {
+		if options.AllNamespaces {
+			return fmt.Errorf("no %ss found in any namespace", options.ResourceType)
+		}
+		return fmt.Errorf("no %ss found in namespace %s", options.ResourceType, options.Namespace)
+	}
+
+	keepNamespace := options.KeepNamespace || options.AllNamespaces
+	for _, obj := range objects {
+		util.Clean(obj, keepNamespace)
+	}
+
+	var files map[string][]byte
+	switch options.Output {
+	case formatKustomize:
+		files, err = kustomizeFiles(objects, options)
+	case formatHelm:
+		files, err = helmFiles(objects, options)
+	default:
+		if options.OutputDir == "" {
+			return writeYAML(objects, options)
+		}
+		files, err = objectFiles(objects, options.AllNamespaces, "")
+	}
+	if err != nil {
+		return err
+	}
+	return writeFiles(files, options)
+}
+
+// Fetches the objects in options as unstructured objects with their kind set, sorted by namespace and name.
+func fetchObjects(ctx context.Context, options *util.FetchResourceOptions, k8sClient client.Client) ([]*unstructured.Unstructured, error) {
+	resourceList, err := util.FetchResources(ctx, options, k8sClient)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []runtime.Object
+	var kind string
+	switch list := resourceList.(type) {
+	case *appsv1alpha1.NIMServiceList:
+		kind = "NIMService"
+		for i := range list.Items {
+			items = append(items, &list.Items[i])
+		}
+	case *appsv1alpha1.NIMCacheList:
+		kind = "NIMCache"
+		for i := range list.Items {
+			items = append(items, &list.Items[i])
+		}
+	default:
+		return nil, fmt.Errorf("unsupported resource type %q", options.ResourceType)
+	}
+
+	var objects []*unstructured.Unstructured
+	for _, item := range items {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(item)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s: %w", kind, err)
+		}
+		obj := &unstructured.Unstructured{Object: content}
+		// Match on the name as well, since not every client honors the field selector.
+		if options.ResourceName != "" && obj.GetName() != options.ResourceName {
+			continue
+		}
+		obj.SetAPIVersion(appsv1alpha1.SchemeGroupVersion.String())
+		obj.SetKind(kind)
+		objects = append(objects, obj)
+	}
+	sort.Slice(objects, func(i, j int) bool {
+		if objects[i].GetNamespace() != objects[j].GetNamespace() {
+			return objects[i].GetNamespace() < objects[j].GetNamespace()
+		}
+		return objects[i].GetName() < objects[j].GetName()
+	})
+	return objects, nil
+}
+
+func writeYAML(objects []*unstructured.Unstructured, options *ExportOptions) error {
+	out := options.IoStreams.Out
+	for i, obj := range objects {
+		data, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s %s: %w", obj.GetKind(), obj.GetName(), err)
+		}
+		if i > 0 {
+			fmt.Fprintln(out, "---")
+		}
+		if _, err := out.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns a file per object, keyed by its path under dir. Names are prefixed with the namespace when objects come from
+// several namespaces, since names are only unique within one.
+func objectFiles(objects []*unstructured.Unstructured, byNamespace bool, dir string) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	for _, obj := range objects {
+		data, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s %s: %w", obj.GetKind(), obj.GetName(), err)
+		}
+		files[filepath.Join(dir, objectFileName(obj, byNamespace))] = data
+	}
+	return files, nil
+}
+
+func objectFileName(obj *unstructured.Unstructured, byNamespace bool) string {
+	name := fmt.Sprintf("%s-%s.yaml", strings.ToLower(obj.GetKind()), obj.GetName())
+	if byNamespace && obj.GetNamespace() != "" {
+		name = obj.GetNamespace() + "-" + name
+	}
+	return name
+}
+
+func writeFiles(files map[string][]byte, options *ExportOptions) error {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		fullPath := filepath.Join(options.OutputDir, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(fullPath, files[path], 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", fullPath, err)
+		}
+		fmt.Fprintf(options.IoStreams.Out, "Wrote %s\n", fullPath)
+	}
+	return nil
+}
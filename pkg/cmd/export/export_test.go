@@ -0,0 +1,236 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/yaml"
+
+	"k8s-nim-operator-cli/pkg/util"
+	"k8s-nim-operator-cli/pkg/util/client/fake"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func newNIMService(namespace, name string) *appsv1alpha1.NIMService {
+	nimservice := &appsv1alpha1.NIMService{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         namespace,
+			UID:               "8c1f7a4e-0000-0000-0000-000000000000",
+			ResourceVersion:   "4711",
+			Generation:        3,
+			CreationTimestamp: metav1.Now(),
+			Finalizers:        []string{"finalizer.nimservice.apps.nvidia.com", "example.com/keep"},
+			Labels:            map[string]string{"team": "llm"},
+			Annotations:       map[string]string{"kubectl.kubernetes.io/last-applied-configuration": "{}"},
+			ManagedFields:     []metav1.ManagedFieldsEntry{{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationApply}},
+		},
+	}
+	nimservice.Spec.Image = appsv1alpha1.Image{Repository: "nvcr.io/nim/meta/llama3-8b-instruct", Tag: "1.0.3"}
+	nimservice.Spec.AuthSecret = "ngc-api-secret"
+	nimservice.Spec.Storage.NIMCache.Name = name + "-cache"
+	nimservice.Spec.Replicas = 1
+	nimservice.Spec.InferencePlatform = "standalone"
+	nimservice.Spec.Expose.Service.Port = ptr(int32(8000))
+	nimservice.Spec.Expose.Service.Type = "LoadBalancer"
+	nimservice.Status.State = "Ready"
+	return nimservice
+}
+
+func newTestOptions(namespace string, output string) (*ExportOptions, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	options := &ExportOptions{
+		FetchResourceOptions: util.NewFetchResourceOptions(nil, genericclioptions.IOStreams{Out: out, ErrOut: &bytes.Buffer{}}),
+		Output:               output,
+		Overlays:             []string{"staging", "prod=nim-prod"},
+	}
+	options.Namespace = namespace
+	options.ResourceType = util.NIMService
+	return options, out
+}
+
+func Test_Run_YAML(t *testing.T) {
+	client := fake.NewFakeClient(nil, []runtime.Object{newNIMService("staging", "llama3"), newNIMService("staging", "mistral")})
+	options, out := newTestOptions("staging", formatYAML)
+	options.ResourceName = "llama3"
+
+	if err := Run(context.Background(), options, client); err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+
+	want := `apiVersion: apps.nvidia.com/v1alpha1
+kind: NIMService
+metadata:
+  finalizers:
+  - example.com/keep
+  labels:
+    team: llm
+  name: llama3
+spec:
+  authSecret: ngc-api-secret
+  expose:
+    service:
+      type: LoadBalancer
+  image:
+    repository: nvcr.io/nim/meta/llama3-8b-instruct
+    tag: 1.0.3
+  storage:
+    nimCache:
+      name: llama3-cache
+`
+	if out.String() != want {
+		t.Fatalf("unexpected export:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func Test_Run_AllNamespaces(t *testing.T) {
+	client := fake.NewFakeClient(nil, []runtime.Object{newNIMService("prod", "llama3"), newNIMService("staging", "llama3")})
+	options, out := newTestOptions("default", formatYAML)
+	options.AllNamespaces = true
+
+	if err := Run(context.Background(), options, client); err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	docs := strings.Split(out.String(), "---\n")
+	if len(docs) != 2 || !strings.Contains(docs[0], "namespace: prod") || !strings.Contains(docs[1], "namespace: staging") {
+		t.Fatalf("expected both objects with their namespaces:\n%s", out.String())
+	}
+}
+
+func Test_Run_Kustomize(t *testing.T) {
+	client := fake.NewFakeClient(nil, []runtime.Object{newNIMService("staging", "llama3"), newNIMService("staging", "mistral")})
+	options, _ := newTestOptions("staging", formatKustomize)
+	options.OutputDir = t.TempDir()
+
+	if err := Run(context.Background(), options, client); err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+
+	for path, want := range map[string]string{
+		"base/kustomization.yaml":             "resources:\n- nimservice-llama3.yaml\n- nimservice-mistral.yaml\n",
+		"overlays/staging/kustomization.yaml": "namespace: staging\nresources:\n- ../../base\n",
+		"overlays/prod/kustomization.yaml":    "namespace: nim-prod\nresources:\n- ../../base\n",
+	} {
+		data, err := os.ReadFile(filepath.Join(options.OutputDir, path))
+		if err != nil {
+			t.Fatalf("%s not written: %v", path, err)
+		}
+		if !strings.HasSuffix(string(data), want) || !strings.HasPrefix(string(data), "apiVersion: kustomize.config.k8s.io/v1beta1\nkind: Kustomization\n") {
+			t.Errorf("unexpected %s:\n%s", path, data)
+		}
+	}
+	data, err := os.ReadFile(filepath.Join(options.OutputDir, "base", "nimservice-mistral.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "namespace:") || strings.Contains(string(data), "status:") {
+		t.Errorf("base objects should not carry a namespace or status:\n%s", data)
+	}
+}
+
+func Test_Run_Helm(t *testing.T) {
+	nimservice := newNIMService("staging", "llama3")
+	nimservice.Spec.Replicas = 2
+	nimservice.Spec.Env = append(nimservice.Spec.Env, corev1.EnvVar{Name: "PROMPT_TEMPLATE", Value: "{{ messages }}"})
+	client := fake.NewFakeClient(nil, []runtime.Object{nimservice})
+	options, _ := newTestOptions("staging", formatHelm)
+	options.ResourceName = "llama3"
+	options.OutputDir = t.TempDir()
+
+	if err := Run(context.Background(), options, client); err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+
+	read := func(path string) string {
+		data, err := os.ReadFile(filepath.Join(options.OutputDir, path))
+		if err != nil {
+			t.Fatalf("%s not written: %v", path, err)
+		}
+		return string(data)
+	}
+	chart := read("Chart.yaml")
+	if !strings.Contains(chart, "name: llama3\n") || !strings.Contains(chart, "appVersion: 1.0.3\n") {
+		t.Errorf("unexpected Chart.yaml:\n%s", chart)
+	}
+	values := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(read("values.yaml")), &values); err != nil {
+		t.Fatal(err)
+	}
+	if tag, _, _ := unstructured.NestedString(values, "nimservices", "llama3", "image", "tag"); tag != "1.0.3" {
+		t.Errorf("unexpected values: %v", values)
+	}
+	if replicas, _, _ := unstructured.NestedFieldNoCopy(values, "nimservices", "llama3", "replicas"); replicas != float64(2) {
+		t.Errorf("unexpected replicas in values: %v", values)
+	}
+
+	template := read("templates/nimservice-llama3.yaml")
+	for _, want := range []string{
+		"namespace: {{ .Release.Namespace }}",
+		`tag: {{ (index .Values.nimservices "llama3").image.tag | quote }}`,
+		`replicas: {{ (index .Values.nimservices "llama3").replicas }}`,
+		// The object's own braces are escaped, so Helm prints them literally.
+		`{{ "{{" }} messages }}`,
+	} {
+		if !strings.Contains(template, want) {
+			t.Errorf("expected %q in template:\n%s", want, template)
+		}
+	}
+}
+
+func Test_Run_Errors(t *testing.T) {
+	client := fake.NewFakeClient(nil, nil)
+	options, _ := newTestOptions("staging", formatYAML)
+	if err := Run(context.Background(), options, client); err == nil || !strings.Contains(err.Error(), "no nimservices found in namespace staging") {
+		t.Errorf("expected an error for no objects, got %v", err)
+	}
+
+	for _, tc := range []struct {
+		output, dir string
+		overlays    []string
+	}{
+		{"json", "", nil},
+		{formatKustomize, "", nil},
+		{formatHelm, "", nil},
+		{formatKustomize, "out", []string{"=prod"}},
+	} {
+		options, _ := newTestOptions("staging", tc.output)
+		options.OutputDir = tc.dir
+		options.Overlays = tc.overlays
+		if err := options.Validate(); err == nil {
+			t.Errorf("expected an error for -o %s --output-dir %q --overlay %v", tc.output, tc.dir, tc.overlays)
+		}
+	}
+}
+
+func Test_Run_NIMCache(t *testing.T) {
+	nimcache := &appsv1alpha1.NIMCache{ObjectMeta: metav1.ObjectMeta{Name: "llama3-cache", Namespace: "staging", UID: "1234"}}
+	nimcache.Spec.Source.DataStore = &appsv1alpha1.NemoDataStoreSource{Endpoint: "http://datastore/v1/hf", Namespace: "default"}
+	nimcache.Spec.Storage.PVC = appsv1alpha1.PersistentVolumeClaim{Create: ptr(true), Size: "50Gi"}
+	nimcache.Status.State = appsv1alpha1.NimCacheStatusReady
+	client := fake.NewFakeClient(nil, []runtime.Object{nimcache})
+	options, out := newTestOptions("staging", formatYAML)
+	options.ResourceType = util.NIMCache
+
+	if err := Run(context.Background(), options, client); err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	for _, unwanted := range []string{"namespace:", "uid:", "status:", "resources:"} {
+		if strings.Contains(out.String(), unwanted) {
+			t.Errorf("unexpected %q in export:\n%s", unwanted, out.String())
+		}
+	}
+	if !strings.Contains(out.String(), "endpoint: http://datastore/v1/hf") || !strings.Contains(out.String(), "size: 50Gi") {
+		t.Errorf("spec missing from export:\n%s", out.String())
+	}
+}
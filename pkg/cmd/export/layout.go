@@ -0,0 +1,176 @@
+package export
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+type kustomization struct {
+	APIVersion string   `json:"apiVersion"`
+	Kind       string   `json:"kind"`
+	Namespace  string   `json:"namespace,omitempty"`
+	Resources  []string `json:"resources"`
+}
+
+func newKustomization(namespace string, resources ...string) kustomization {
+	return kustomization{
+		APIVersion: "kustomize.config.k8s.io/v1beta1",
+		Kind:       "Kustomization",
+		Namespace:  namespace,
+		Resources:  resources,
+	}
+}
+
+// Returns base/ with the objects and their kustomization.yaml, and overlays/NAME/kustomization.yaml per overlay.
+func kustomizeFiles(objects []*unstructured.Unstructured, options *ExportOptions) (map[string][]byte, error) {
+	files, err := objectFiles(objects, options.AllNamespaces, "base")
+	if err != nil {
+		return nil, err
+	}
+	var resources []string
+	for _, obj := range objects {
+		resources = append(resources, objectFileName(obj, options.AllNamespaces))
+	}
+	if files["base/kustomization.yaml"], err = yaml.Marshal(newKustomization("", resources...)); err != nil {
+		return nil, err
+	}
+
+	for _, overlay := range options.Overlays {
+		name, namespace, found := strings.Cut(overlay, "=")
+		if !found && !options.AllNamespaces {
+			namespace = options.Namespace
+		}
+		path := filepath.Join("overlays", name, "kustomization.yaml")
+		if files[path], err = yaml.Marshal(newKustomization(namespace, "../../base")); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+type chart struct {
+	APIVersion  string `json:"apiVersion"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Type        string `json:"type"`
+	Version     string `json:"version"`
+	AppVersion  string `json:"appVersion,omitempty"`
+}
+
+// Replaces fields of a template with Helm expressions. Fields are first set to placeholders, which marshal as plain
+// scalars and are swapped for the expressions afterwards.
+type templater struct {
+	expressions map[string]string
+}
+
+func (t *templater) set(obj map[string]interface{}, expression string, fields ...string) error {
+	placeholder := fmt.Sprintf("NIM_EXPORT_PLACEHOLDER_%d", len(t.expressions))
+	t.expressions[placeholder] = expression
+	return unstructured.SetNestedField(obj, placeholder, fields...)
+}
+
+func (t *templater) render(obj map[string]interface{}) ([]byte, error) {
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	// Braces in the object's own values would be read as template actions.
+	text := strings.ReplaceAll(string(data), "{{", `{{ "{{" }}`)
+	for placeholder, expression := range t.expressions {
+		text = strings.ReplaceAll(text, placeholder, expression)
+	}
+	return []byte(text), nil
+}
+
+// Returns a minimal chart: Chart.yaml, values.yaml with each NIMService's image and replicas, and a template per object
+// that deploys to the release namespace.
+func helmFiles(objects []*unstructured.Unstructured, options *ExportOptions) (map[string][]byte, error) {
+	t := &templater{expressions: map[string]string{}}
+	nimservices := map[string]interface{}{}
+	files := map[string][]byte{}
+	appVersion := ""
+
+	for _, obj := range objects {
+		if !options.KeepNamespace && !options.AllNamespaces {
+			if err := t.set(obj.Object, "{{ .Release.Namespace }}", "metadata", "namespace"); err != nil {
+				return nil, err
+			}
+		}
+		if obj.GetKind() == "NIMService" {
+			values := map[string]interface{}{}
+			ref := fmt.Sprintf("(index .Values.nimservices %q)", obj.GetName())
+			for _, field := range []string{"repository", "tag"} {
+				value, found, _ := unstructured.NestedString(obj.Object, "spec", "image", field)
+				if !found {
+					continue
+				}
+				if err := unstructured.SetNestedField(values, value, "image", field); err != nil {
+					return nil, err
+				}
+				if err := t.set(obj.Object, fmt.Sprintf("{{ %s.image.%s | quote }}", ref, field), "spec", "image", field); err != nil {
+					return nil, err
+				}
+				if field == "tag" {
+					appVersion = value
+				}
+			}
+			if scaled, _, _ := unstructured.NestedBool(obj.Object, "spec", "scale", "enabled"); !scaled {
+				replicas, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+				if !found {
+					replicas = 1
+				}
+				values["replicas"] = replicas
+				if err := t.set(obj.Object, fmt.Sprintf("{{ %s.replicas }}", ref), "spec", "replicas"); err != nil {
+					return nil, err
+				}
+			}
+			nimservices[obj.GetName()] = values
+		}
+
+		data, err := t.render(obj.Object)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s %s: %w", obj.GetKind(), obj.GetName(), err)
+		}
+		files[filepath.Join("templates", objectFileName(obj, options.AllNamespaces))] = data
+	}
+
+	name := options.ChartName
+	if name == "" {
+		switch {
+		case len(objects) == 1:
+			name = objects[0].GetName()
+		case !options.AllNamespaces:
+			name = options.Namespace
+		default:
+			name = "nim"
+		}
+	}
+	if len(nimservices) != 1 {
+		appVersion = ""
+	}
+
+	var err error
+	files["Chart.yaml"], err = yaml.Marshal(chart{
+		APIVersion:  "v2",
+		Name:        name,
+		Description: "NIM Operator resources exported by nim export.",
+		Type:        "application",
+		Version:     "0.1.0",
+		AppVersion:  appVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]interface{}{}
+	if len(nimservices) > 0 {
+		values["nimservices"] = nimservices
+	}
+	if files["values.yaml"], err = yaml.Marshal(values); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
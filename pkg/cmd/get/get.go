@@ -30,6 +30,7 @@ func NewGetCommand(cmdFactory cmdutil.Factory, streams genericclioptions.IOStrea
 
 	cmd.AddCommand(NewGetNIMCacheCommand(cmdFactory, streams))
 	cmd.AddCommand(NewGetNIMServiceCommand(cmdFactory, streams))
+	cmd.AddCommand(NewGetAllCommand(cmdFactory, streams))
 	return cmd
 }
 
@@ -48,7 +49,10 @@ func Run(ctx context.Context, options *util.FetchResourceOptions, k8sClient clie
 		if !ok {
 			return fmt.Errorf("failed to cast resourceList to NIMServiceList")
 		}
-		return printNIMServices(nimServiceList, options.IoStreams.Out)
+		if err := printNIMServices(nimServiceList, options.IoStreams.Out); err != nil {
+			return err
+		}
+		return util.PrintMultiNodePods(ctx, k8sClient.KubernetesClient(), nimServiceList, options.IoStreams.Out)
 
 	case util.NIMCache:
 		// Cast resourceList to NIMCacheList.
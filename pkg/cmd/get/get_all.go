@@ -0,0 +1,296 @@
+package get
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	util "k8s-nim-operator-cli/pkg/util"
+	"k8s-nim-operator-cli/pkg/util/client"
+
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+)
+
+// allResources holds every NIM Operator resource kind listed by "nim get all".
+type allResources struct {
+	NIMCaches    *appsv1alpha1.NIMCacheList
+	NIMServices  *appsv1alpha1.NIMServiceList
+	NIMPipelines *appsv1alpha1.NIMPipelineList
+	NIMBuilds    *appsv1alpha1.NIMBuildList
+}
+
+func NewGetAllCommand(cmdFactory cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	options := util.NewFetchResourceOptions(cmdFactory, streams)
+
+	cmd := &cobra.Command{
+		Use:          "all",
+		Short:        "Get all NIM Operator resources.",
+		Long:         "Get a grouped summary of every NIM Operator resource (NIMCaches, NIMServices, NIMPipelines and NIMBuilds) in a namespace.",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := options.CompleteNamespace(args, cmd); err != nil {
+				return err
+			}
+			// running cmd.Execute or cmd.ExecuteE sets the context, which will be done by root
+			k8sClient, err := client.NewClient(cmdFactory)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			return RunAll(cmd.Context(), options, k8sClient)
+		},
+	}
+	cmd.Flags().BoolVarP(&options.AllNamespaces, "all-namespaces", "A", false, "If present, list all NIM Operator resources across all namespaces. Namespace in current context is ignored even if specified with --namespace.")
+	return cmd
+}
+
+// RunAll lists every NIM Operator kind and prints them as grouped tables.
+func RunAll(ctx context.Context, options *util.FetchResourceOptions, k8sClient client.Client) error {
+	resources, err := fetchAll(ctx, options, k8sClient)
+	if err != nil {
+		return err
+	}
+
+	if resources.empty() {
+		if options.AllNamespaces {
+			fmt.Fprintln(options.IoStreams.ErrOut, "No NIM Operator resources found.")
+		} else {
+			fmt.Fprintf(options.IoStreams.ErrOut, "No NIM Operator resources found in namespace %s.\n", options.Namespace)
+		}
+		return nil
+	}
+	return printAll(resources, options.AllNamespaces, options.IoStreams.Out)
+}
+
+// fetchAll issues the list calls for every kind concurrently.
+func fetchAll(ctx context.Context, options *util.FetchResourceOptions, k8sClient client.Client) (*allResources, error) {
+	namespace := options.Namespace
+	if options.AllNamespaces {
+		namespace = ""
+	}
+	apps := k8sClient.NIMClient().AppsV1alpha1()
+	listopts := v1.ListOptions{}
+
+	resources := &allResources{}
+	errs := make([]error, 4)
+
+	var wg sync.WaitGroup
+	wg.Add(4)
+	go func() {
+		defer wg.Done()
+		resources.NIMCaches, errs[0] = apps.NIMCaches(namespace).List(ctx, listopts)
+	}()
+	go func() {
+		defer wg.Done()
+		resources.NIMServices, errs[1] = apps.NIMServices(namespace).List(ctx, listopts)
+	}()
+	go func() {
+		defer wg.Done()
+		resources.NIMPipelines, errs[2] = apps.NIMPipelines(namespace).List(ctx, listopts)
+	}()
+	go func() {
+		defer wg.Done()
+		resources.NIMBuilds, errs[3] = apps.NIMBuilds(namespace).List(ctx, listopts)
+	}()
+	wg.Wait()
+
+	kinds := []string{"NIMCaches", "NIMServices", "NIMPipelines", "NIMBuilds"}
+	for i, err := range errs {
+		// A missing CRD means the kind is simply not installed in this cluster.
+		if err == nil || apierrors.IsNotFound(err) {
+			continue
+		}
+		if options.AllNamespaces {
+			return nil, fmt.Errorf("unable to retrieve %s for all namespaces: %w", kinds[i], err)
+		}
+		return nil, fmt.Errorf("unable to retrieve %s for namespace %s: %w", kinds[i], namespace, err)
+	}
+
+	if resources.NIMCaches == nil {
+		resources.NIMCaches = &appsv1alpha1.NIMCacheList{}
+	}
+	if resources.NIMServices == nil {
+		resources.NIMServices = &appsv1alpha1.NIMServiceList{}
+	}
+	if resources.NIMPipelines == nil {
+		resources.NIMPipelines = &appsv1alpha1.NIMPipelineList{}
+	}
+	if resources.NIMBuilds == nil {
+		resources.NIMBuilds = &appsv1alpha1.NIMBuildList{}
+	}
+	return resources, nil
+}
+
+func (r *allResources) empty() bool {
+	return len(r.NIMCaches.Items) == 0 && len(r.NIMServices.Items) == 0 &&
+		len(r.NIMPipelines.Items) == 0 && len(r.NIMBuilds.Items) == 0
+}
+
+// printAll prints one table per non-empty kind, separated by a blank line.
+func printAll(resources *allResources, allNamespaces bool, output io.Writer) error {
+	tables := []*v1.Table{}
+	if len(resources.NIMCaches.Items) > 0 {
+		tables = append(tables, allNIMCachesTable(resources, allNamespaces))
+	}
+	if len(resources.NIMServices.Items) > 0 {
+		tables = append(tables, allNIMServicesTable(resources.NIMServices, allNamespaces))
+	}
+	if len(resources.NIMPipelines.Items) > 0 {
+		tables = append(tables, allNIMPipelinesTable(resources.NIMPipelines, allNamespaces))
+	}
+	if len(resources.NIMBuilds.Items) > 0 {
+		tables = append(tables, allNIMBuildsTable(resources.NIMBuilds, allNamespaces))
+	}
+
+	resultTablePrinter := printers.NewTablePrinter(printers.PrintOptions{})
+	for i, table := range tables {
+		if i > 0 {
+			fmt.Fprintln(output)
+		}
+		if err := resultTablePrinter.PrintObj(table, output); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func allNIMCachesTable(resources *allResources, allNamespaces bool) *v1.Table {
+	// Index NIMServices by the NIMCache they reference.
+	usedBy := map[string][]string{}
+	for _, nimservice := range resources.NIMServices.Items {
+		if ref := nimservice.Spec.Storage.NIMCache.Name; ref != "" {
+			key := nimservice.GetNamespace() + "/" + ref
+			usedBy[key] = append(usedBy[key], nimservice.GetName())
+		}
+	}
+
+	resTable := &v1.Table{ColumnDefinitions: withNamespaceColumn([]v1.TableColumnDefinition{
+		{Name: "NIMCache", Type: "string"},
+		{Name: "Source", Type: "string"},
+		{Name: "Status", Type: "string"},
+		{Name: "PVC", Type: "string"},
+		{Name: "Used By", Type: "string"},
+		{Name: "Age", Type: "string"},
+	}, allNamespaces)}
+
+	for _, nimcache := range resources.NIMCaches.Items {
+		users := usedBy[nimcache.GetNamespace()+"/"+nimcache.GetName()]
+		sort.Strings(users)
+		resTable.Rows = append(resTable.Rows, v1.TableRow{
+			Cells: withNamespaceCell([]interface{}{
+				nimcache.GetName(),
+				getSource(&nimcache),
+				nimcache.Status.State,
+				getPVCDetails(&nimcache),
+				util.OrNone(strings.Join(users, ",")),
+				getAge(nimcache.GetCreationTimestamp()),
+			}, nimcache.GetNamespace(), allNamespaces),
+		})
+	}
+	return resTable
+}
+
+func allNIMServicesTable(nimServiceList *appsv1alpha1.NIMServiceList, allNamespaces bool) *v1.Table {
+	resTable := &v1.Table{ColumnDefinitions: withNamespaceColumn([]v1.TableColumnDefinition{
+		{Name: "NIMService", Type: "string"},
+		{Name: "Status", Type: "string"},
+		{Name: "NIMCache", Type: "string"},
+		{Name: "Endpoint", Type: "string"},
+		{Name: "External URL", Type: "string"},
+		{Name: "Age", Type: "string"},
+	}, allNamespaces)}
+
+	for _, nimservice := range nimServiceList.Items {
+		resTable.Rows = append(resTable.Rows, v1.TableRow{
+			Cells: withNamespaceCell([]interface{}{
+				nimservice.GetName(),
+				nimservice.Status.State,
+				util.OrNone(nimservice.Spec.Storage.NIMCache.Name),
+				getEndpoint(&nimservice),
+				util.OrNone(util.ExternalURL(&nimservice)),
+				getAge(nimservice.GetCreationTimestamp()),
+			}, nimservice.GetNamespace(), allNamespaces),
+		})
+	}
+	return resTable
+}
+
+func allNIMPipelinesTable(nimPipelineList *appsv1alpha1.NIMPipelineList, allNamespaces bool) *v1.Table {
+	resTable := &v1.Table{ColumnDefinitions: withNamespaceColumn([]v1.TableColumnDefinition{
+		{Name: "NIMPipeline", Type: "string"},
+		{Name: "Status", Type: "string"},
+		{Name: "Services", Type: "string"},
+		{Name: "Age", Type: "string"},
+	}, allNamespaces)}
+
+	for _, pipeline := range nimPipelineList.Items {
+		services := make([]string, 0, len(pipeline.Spec.Services))
+		for _, svc := range pipeline.Spec.Services {
+			services = append(services, svc.Name)
+		}
+		resTable.Rows = append(resTable.Rows, v1.TableRow{
+			Cells: withNamespaceCell([]interface{}{
+				pipeline.GetName(),
+				pipeline.Status.State,
+				util.OrNone(strings.Join(services, ",")),
+				getAge(pipeline.GetCreationTimestamp()),
+			}, pipeline.GetNamespace(), allNamespaces),
+		})
+	}
+	return resTable
+}
+
+func allNIMBuildsTable(nimBuildList *appsv1alpha1.NIMBuildList, allNamespaces bool) *v1.Table {
+	resTable := &v1.Table{ColumnDefinitions: withNamespaceColumn([]v1.TableColumnDefinition{
+		{Name: "NIMBuild", Type: "string"},
+		{Name: "Status", Type: "string"},
+		{Name: "NIMCache", Type: "string"},
+		{Name: "Age", Type: "string"},
+	}, allNamespaces)}
+
+	for _, build := range nimBuildList.Items {
+		resTable.Rows = append(resTable.Rows, v1.TableRow{
+			Cells: withNamespaceCell([]interface{}{
+				build.GetName(),
+				build.Status.State,
+				util.OrNone(build.Spec.NIMCache.Name),
+				getAge(build.GetCreationTimestamp()),
+			}, build.GetNamespace(), allNamespaces),
+		})
+	}
+	return resTable
+}
+
+// Prepend a Namespace column when listing across all namespaces.
+func withNamespaceColumn(columns []v1.TableColumnDefinition, allNamespaces bool) []v1.TableColumnDefinition {
+	if !allNamespaces {
+		return columns
+	}
+	return append([]v1.TableColumnDefinition{{Name: "Namespace", Type: "string"}}, columns...)
+}
+
+func withNamespaceCell(cells []interface{}, namespace string, allNamespaces bool) []interface{} {
+	if !allNamespaces {
+		return cells
+	}
+	return append([]interface{}{namespace}, cells...)
+}
+
+func getAge(created v1.Time) string {
+	if created.Time.IsZero() {
+		return "<unknown>"
+	}
+	return duration.HumanDuration(time.Since(created.Time))
+}
@@ -55,6 +55,7 @@ func printNIMServices(nimServiceList *appsv1alpha1.NIMServiceList, output io.Wri
 			{Name: "Status", Type: "string"},
 			{Name: "Age", Type: "string"},
 			{Name: "Endpoint", Type: "string"},
+			{Name: "External URL", Type: "string"},
 		},
 	}
 
@@ -70,6 +71,7 @@ func printNIMServices(nimServiceList *appsv1alpha1.NIMServiceList, output io.Wri
 				nimservice.Status.State,
 				age,
 				getEndpoint(&nimservice),
+				util.OrNone(util.ExternalURL(&nimservice)),
 			},
 		})
 	}
@@ -82,4 +84,4 @@ func getEndpoint(nimService *appsv1alpha1.NIMService) string {
 		return nimService.Status.Model.ExternalEndpoint
 	}
 	return "error"
-}
\ No newline at end of file
+}
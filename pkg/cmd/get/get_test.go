@@ -2,6 +2,7 @@ package get
 
 import (
 	"bytes"
+	"context"
 	"strings"
 	"testing"
 	"time"
@@ -10,7 +11,12 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/utils/ptr"
+
+	util "k8s-nim-operator-cli/pkg/util"
+	"k8s-nim-operator-cli/pkg/util/client/fake"
 )
 
 // NIMService tests.
@@ -242,3 +248,62 @@ func Test_printNIMCaches(t *testing.T) {
 		}
 	}
 }
+
+// "get all" tests.
+func Test_fetchAll_and_printAll(t *testing.T) {
+	cache := ncWithNGC("cache-a", "ns1", "img:tag")
+	cache.Status.State = "Ready"
+	svc := withStorageNIMCache(newBaseNS("svc-a", "ns1"), "cache-a", "")
+	svc.Status.State = "Ready"
+	other := newBaseNS("svc-other", "ns2")
+	pipeline := &appsv1alpha1.NIMPipeline{ObjectMeta: metav1.ObjectMeta{Name: "pipe-a", Namespace: "ns1"}}
+	pipeline.Spec.Services = []appsv1alpha1.NIMServicePipelineSpec{{Name: "svc-a"}}
+
+	k8sClient := fake.NewFakeClient(nil, []runtime.Object{&cache, &svc, &other, pipeline})
+
+	options := util.NewFetchResourceOptions(nil, genericclioptions.IOStreams{})
+	options.Namespace = "ns1"
+
+	resources, err := fetchAll(context.Background(), options, k8sClient)
+	if err != nil {
+		t.Fatalf("fetchAll error: %v", err)
+	}
+	if len(resources.NIMCaches.Items) != 1 || len(resources.NIMServices.Items) != 1 || len(resources.NIMPipelines.Items) != 1 {
+		t.Fatalf("unexpected resources for ns1: %+v", resources)
+	}
+	if resources.empty() {
+		t.Fatalf("expected resources to be non-empty")
+	}
+
+	var buf bytes.Buffer
+	if err := printAll(resources, false, &buf); err != nil {
+		t.Fatalf("printAll error: %v", err)
+	}
+	out := buf.String()
+	for _, s := range []string{"NIMCACHE", "USED BY", "NIMSERVICE", "NIMPIPELINE", "cache-a", "svc-a", "pipe-a"} {
+		if !strings.Contains(out, s) {
+			t.Fatalf("output missing %q:\n%s", s, out)
+		}
+	}
+	if strings.Contains(out, "NIMBUILD") || strings.Contains(out, "NAMESPACE") {
+		t.Fatalf("unexpected empty group or namespace column:\n%s", out)
+	}
+
+	options.AllNamespaces = true
+	resources, err = fetchAll(context.Background(), options, k8sClient)
+	if err != nil {
+		t.Fatalf("fetchAll -A error: %v", err)
+	}
+	if len(resources.NIMServices.Items) != 2 {
+		t.Fatalf("expected 2 NIMServices across namespaces, got %d", len(resources.NIMServices.Items))
+	}
+	buf.Reset()
+	if err := printAll(resources, true, &buf); err != nil {
+		t.Fatalf("printAll -A error: %v", err)
+	}
+	for _, s := range []string{"NAMESPACE", "ns2", "svc-other"} {
+		if !strings.Contains(buf.String(), s) {
+			t.Fatalf("output missing %q:\n%s", s, buf.String())
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/rest"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	"k8s-nim-operator-cli/pkg/util"
+	"k8s-nim-operator-cli/pkg/util/client"
+	"k8s-nim-operator-cli/pkg/util/openai"
+
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+)
+
+const (
+	livePath  = "/v1/health/live"
+	readyPath = "/v1/health/ready"
+)
+
+// ConnectFunc returns a base URL that reaches the target pod. The returned func releases the connection.
+type ConnectFunc func(ctx context.Context, target *util.PortForwardTarget) (string, func(), error)
+
+type HealthOptions struct {
+	*util.FetchResourceOptions
+	All     bool
+	Timeout time.Duration
+	// Connect defaults to a port-forward to each pod.
+	Connect ConnectFunc
+}
+
+func NewHealthCommand(cmdFactory cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	options := &HealthOptions{FetchResourceOptions: util.NewFetchResourceOptions(cmdFactory, streams)}
+
+	cmd := &cobra.Command{
+		Use:   "health RESOURCE [NAME | --all]",
+		Short: "Check the health endpoints of every NIMService pod",
+		Long: `Call /v1/health/live and /v1/health/ready on every pod of a NIMService through a port-forward and report the
+latency and readiness per pod.
+
+The NIMService state can report Ready while individual pods are still loading weights; this command shows what each
+pod actually answers. It exits with an error if any pod is not ready.`,
+		Example: `  nim health nimservice llama3-nimservice
+  nim health nimservice --all -n nim-service
+  nim health nimservice --all -A`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch {
+			case len(args) == 0:
+				// Show help if no args provided.
+				cmd.HelpFunc()(cmd, args)
+				return nil
+			case len(args) == 2 && !options.All:
+				if err := options.CompleteNamespace(args, cmd); err != nil {
+					return err
+				}
+			case len(args) == 1 && options.All:
+				if err := options.CompleteNamespace(nil, cmd); err != nil {
+					return err
+				}
+				options.ResourceType = util.ResourceType(strings.ToLower(args[0]))
+			case len(args) == 1:
+				return fmt.Errorf("specify a NIMService name or --all")
+			case len(args) == 2:
+				return fmt.Errorf("a NIMService name cannot be combined with --all")
+			default:
+				fmt.Println(fmt.Errorf("unknown command(s) %q", strings.Join(args, " ")))
+				return nil
+			}
+			if options.AllNamespaces && !options.All {
+				return fmt.Errorf("--all-namespaces requires --all")
+			}
+			if options.ResourceType != util.NIMService {
+				return fmt.Errorf("health only supports nimservice, got %q", args[0])
+			}
+			k8sClient, err := client.NewClient(cmdFactory)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			restConfig, err := cmdFactory.ToRESTConfig()
+			if err != nil {
+				return fmt.Errorf("failed to get REST config: %w", err)
+			}
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return Run(ctx, options, k8sClient, restConfig)
+		},
+	}
+
+	cmd.Flags().BoolVar(&options.All, "all", false, "Check every NIMService in the namespace.")
+	cmd.Flags().BoolVarP(&options.AllNamespaces, "all-namespaces", "A", false, "With --all, check NIMServices across all namespaces.")
+	cmd.Flags().DurationVar(&options.Timeout, "timeout", 5*time.Second, "Timeout for each health request.")
+	cmd.SetHelpTemplate(helpTemplate)
+
+	return cmd
+}
+
+// podHealth is the result of checking a single pod. Pods that could not be probed carry the reason in Err.
+type podHealth struct {
+	NIMService *appsv1alpha1.NIMService
+	Pod        *corev1.Pod
+	Target     *util.PortForwardTarget
+	Live       *openai.ProbeResult
+	Ready      *openai.ProbeResult
+	Err        error
+}
+
+// Serving reports whether the pod answered the readiness endpoint with a 2xx status.
+func (h *podHealth) Serving() bool {
+	return h.Ready != nil && h.Ready.OK()
+}
+
+func Run(ctx context.Context, options *HealthOptions, k8sClient client.Client, restConfig *rest.Config) error {
+	options.ResourceType = util.NIMService
+	resourceList, err := util.FetchResources(ctx, options.FetchResourceOptions, k8sClient)
+	if err != nil {
+		return err
+	}
+	nimServiceList, ok := resourceList.(*appsv1alpha1.NIMServiceList)
+	if !ok {
+		return fmt.Errorf("failed to cast resourceList to NIMServiceList")
+	}
+	if len(nimServiceList.Items) == 0 {
+		if !options.All {
+			return fmt.Errorf("NIMService %s not found in namespace %s", options.ResourceName, options.Namespace)
+		}
+		fmt.Fprintf(options.IoStreams.ErrOut, "No NIMServices found.\n")
+		return nil
+	}
+
+	connect := options.Connect
+	if connect == nil {
+		connect = portForwardConnect(restConfig, k8sClient, options.IoStreams.ErrOut)
+	}
+
+	var results []*podHealth
+	for i := range nimServiceList.Items {
+		nimservice := &nimServiceList.Items[i]
+		svc, pods, err := util.ListNIMServicePods(ctx, k8sClient.KubernetesClient(), nimservice)
+		if err != nil {
+			results = append(results, &podHealth{NIMService: nimservice, Err: err})
+			continue
+		}
+		if len(pods) == 0 {
+			results = append(results, &podHealth{NIMService: nimservice, Err: fmt.Errorf("no pods found")})
+			continue
+		}
+		for j := range pods {
+			result := &podHealth{NIMService: nimservice, Pod: &pods[j]}
+			result.Target, result.Err = podTarget(svc, &pods[j], nimservice.GetServicePort())
+			results = append(results, result)
+		}
+	}
+
+	// Probe all pods concurrently; each result is only written by its own goroutine.
+	var wg sync.WaitGroup
+	for _, result := range results {
+		if result.Target == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(result *podHealth) {
+			defer wg.Done()
+			probePod(ctx, options, connect, result)
+		}(result)
+	}
+	wg.Wait()
+
+	if err := printHealth(results, options.AllNamespaces, options.IoStreams.Out); err != nil {
+		return err
+	}
+
+	notServing := 0
+	for _, result := range results {
+		if !result.Serving() {
+			notServing++
+		}
+	}
+	if notServing > 0 {
+		return fmt.Errorf("%d of %d pods are not ready", notServing, len(results))
+	}
+	return nil
+}
+
+// Returns the port-forward target for a pod, or why the pod cannot be probed.
+func podTarget(svc *corev1.Service, pod *corev1.Pod, servicePort int32) (*util.PortForwardTarget, error) {
+	if pod.DeletionTimestamp != nil {
+		return nil, fmt.Errorf("pod is terminating")
+	}
+	if pod.Status.Phase != corev1.PodRunning {
+		return nil, fmt.Errorf("pod is %s", strings.ToLower(string(pod.Status.Phase)))
+	}
+	return util.PodPortForwardTarget(svc, pod, servicePort)
+}
+
+// Connects to the pod and calls the live and ready endpoints, recording the outcome in result.
+func probePod(ctx context.Context, options *HealthOptions, connect ConnectFunc, result *podHealth) {
+	baseURL, stop, err := connect(ctx, result.Target)
+	if err != nil {
+		result.Err = err
+		return
+	}
+	defer stop()
+
+	c := openai.NewClient(baseURL)
+	live, err := probe(ctx, c, livePath, options.Timeout)
+	if err != nil {
+		result.Err = err
+		return
+	}
+	result.Live = live
+	ready, err := probe(ctx, c, readyPath, options.Timeout)
+	if err != nil {
+		result.Err = err
+		return
+	}
+	result.Ready = ready
+}
+
+func probe(ctx context.Context, c *openai.Client, path string, timeout time.Duration) (*openai.ProbeResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	result, err := c.Probe(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Returns a ConnectFunc that port-forwards to the target pod on a free local port.
+func portForwardConnect(restConfig *rest.Config, k8sClient client.Client, errOut io.Writer) ConnectFunc {
+	return func(ctx context.Context, target *util.PortForwardTarget) (string, func(), error) {
+		forwardCtx, cancel := context.WithCancel(ctx)
+		localPort, _, err := util.StartPortForward(forwardCtx, restConfig, k8sClient.KubernetesClient(), target, "localhost", 0, io.Discard, errOut)
+		if err != nil {
+			cancel()
+			return "", nil, err
+		}
+		return fmt.Sprintf("http://localhost:%d", localPort), cancel, nil
+	}
+}
+
+func printHealth(results []*podHealth, allNamespaces bool, output io.Writer) error {
+	resultTablePrinter := printers.NewTablePrinter(printers.PrintOptions{})
+
+	resTable := &v1.Table{
+		ColumnDefinitions: []v1.TableColumnDefinition{
+			{Name: "NIMService", Type: "string"},
+			{Name: "State", Type: "string"},
+			{Name: "Pod", Type: "string"},
+			{Name: "Pod Ready", Type: "string"},
+			{Name: "Live", Type: "string"},
+			{Name: "Ready", Type: "string"},
+			{Name: "Message", Type: "string"},
+		},
+	}
+	if allNamespaces {
+		resTable.ColumnDefinitions = append([]v1.TableColumnDefinition{{Name: "Namespace", Type: "string"}}, resTable.ColumnDefinitions...)
+	}
+
+	for _, result := range results {
+		podName, podReady := "<none>", "<none>"
+		if result.Pod != nil {
+			podName = result.Pod.GetName()
+			podReady = fmt.Sprintf("%t", util.IsPodReady(result.Pod))
+		}
+
+		message := ""
+		switch {
+		case result.Err != nil:
+			message = result.Err.Error()
+		case !result.Serving() && result.NIMService.Status.State == appsv1alpha1.NIMServiceStatusReady:
+			message = "NIMService reports Ready but the pod is not serving yet"
+		}
+
+		cells := []interface{}{
+			result.NIMService.GetName(),
+			result.NIMService.Status.State,
+			podName,
+			podReady,
+			formatProbe(result.Live),
+			formatProbe(result.Ready),
+			message,
+		}
+		if allNamespaces {
+			cells = append([]interface{}{result.NIMService.GetNamespace()}, cells...)
+		}
+		resTable.Rows = append(resTable.Rows, v1.TableRow{Cells: cells})
+	}
+
+	return resultTablePrinter.PrintObj(resTable, output)
+}
+
+// Formats a probe as its status code and latency, e.g. "200 (12.3ms)".
+func formatProbe(result *openai.ProbeResult) string {
+	if result == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%d (%.1fms)", result.StatusCode, float64(result.Latency.Microseconds())/1000)
+}
+
+// Custom help message template. Needed to show supported resource types as a custom category to be consistent with "Available Commands" for get and status.
+const helpTemplate = `{{- if .Long }}{{ .Long }}{{- else }}{{ .Short }}{{- end }}
+
+Usage:{{if .Runnable}}
+  {{.UseLine}}{{end}}
+
+Supported RESOURCE types:
+  nimservice   Check the health endpoints of NIMService pods.
+
+{{if .HasExample}}Examples:
+{{ .Example }}
+
+{{end}}{{if .HasAvailableLocalFlags}}Flags:
+{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}
+
+{{end}}{{if .HasAvailableInheritedFlags}}Global Flags:
+{{.InheritedFlags.FlagUsages | trimTrailingWhitespaces}}
+
+{{end}}`
@@ -0,0 +1,142 @@
+package health
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"k8s-nim-operator-cli/pkg/util"
+	"k8s-nim-operator-cli/pkg/util/client/fake"
+)
+
+func newTestPod(name string, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "nim", Labels: map[string]string{"app": "llama"}},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name:  "llama",
+			Ports: []corev1.ContainerPort{{Name: "api", ContainerPort: 8000}},
+		}}},
+		Status: corev1.PodStatus{
+			Phase:      phase,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+}
+
+func Test_Run_ReportsPerPodReadiness(t *testing.T) {
+	nimservice := &appsv1alpha1.NIMService{ObjectMeta: metav1.ObjectMeta{Name: "llama", Namespace: "nim"}}
+	nimservice.Status.State = appsv1alpha1.NIMServiceStatusReady
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "llama", Namespace: "nim"},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": "llama"},
+			Ports:    []corev1.ServicePort{{Name: "api", Port: 8000, TargetPort: intstr.FromString("api")}},
+		},
+	}
+	k8sClient := fake.NewFakeClient([]runtime.Object{svc, newTestPod("llama-a", corev1.PodRunning), newTestPod("llama-b", corev1.PodRunning), newTestPod("llama-c", corev1.PodPending)}, []runtime.Object{nimservice})
+
+	// One server per pod: llama-a serves, llama-b is still loading weights.
+	servers := map[string]*httptest.Server{}
+	for pod, readyStatus := range map[string]int{"llama-a": http.StatusOK, "llama-b": http.StatusServiceUnavailable} {
+		readyStatus := readyStatus
+		servers[pod] = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case livePath:
+				w.WriteHeader(http.StatusOK)
+			case readyPath:
+				w.WriteHeader(readyStatus)
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer servers[pod].Close()
+	}
+
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	options := &HealthOptions{
+		FetchResourceOptions: util.NewFetchResourceOptions(nil, genericclioptions.IOStreams{Out: out, ErrOut: errOut}),
+		All:                  true,
+		Timeout:              time.Second,
+		Connect: func(ctx context.Context, target *util.PortForwardTarget) (string, func(), error) {
+			if target.PodPort != 8000 {
+				t.Errorf("unexpected pod port %d", target.PodPort)
+			}
+			return servers[target.PodName].URL, func() {}, nil
+		},
+	}
+	options.Namespace = "nim"
+
+	err := Run(context.Background(), options, k8sClient, nil)
+	if err == nil || err.Error() != "2 of 3 pods are not ready" {
+		t.Fatalf("expected 2 of 3 pods not ready, got %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected header and 3 rows, got:\n%s", out.String())
+	}
+	if !strings.Contains(lines[1], "llama-a") || !strings.Contains(lines[1], "200 (") {
+		t.Fatalf("unexpected row for llama-a: %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "llama-b") || !strings.Contains(lines[2], "503 (") || !strings.Contains(lines[2], "reports Ready but the pod is not serving") {
+		t.Fatalf("unexpected row for llama-b: %q", lines[2])
+	}
+	if !strings.Contains(lines[3], "llama-c") || !strings.Contains(lines[3], "pod is pending") {
+		t.Fatalf("unexpected row for llama-c: %q", lines[3])
+	}
+}
+
+func Test_Run_NotFound(t *testing.T) {
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	options := &HealthOptions{FetchResourceOptions: util.NewFetchResourceOptions(nil, genericclioptions.IOStreams{Out: out, ErrOut: errOut})}
+	options.Namespace = "nim"
+	options.ResourceName = "missing"
+
+	err := Run(context.Background(), options, fake.NewFakeClient(nil, nil), nil)
+	if err == nil || err.Error() != "NIMService missing not found in namespace nim" {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+
+	// With --all an empty namespace is not an error.
+	options.ResourceName = ""
+	options.All = true
+	if err := Run(context.Background(), options, fake.NewFakeClient(nil, nil), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if errOut.String() != "No NIMServices found.\n" {
+		t.Errorf("unexpected message %q", errOut.String())
+	}
+}
+
+func Test_HealthCommand_Args(t *testing.T) {
+	cases := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"nimservice"}, "specify a NIMService name or --all"},
+		{[]string{"nimservice", "llama", "--all"}, "cannot be combined with --all"},
+		{[]string{"nimservice", "llama", "-A"}, "--all-namespaces requires --all"},
+		{[]string{"nimcache", "--all"}, "health only supports nimservice"},
+	}
+	for _, tc := range cases {
+		cmd := NewHealthCommand(nil, genericclioptions.IOStreams{Out: &bytes.Buffer{}, ErrOut: &bytes.Buffer{}})
+		cmd.Flags().StringP("namespace", "n", "", "")
+		cmd.SetArgs(tc.args)
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("args %v: expected error containing %q, got %v", tc.args, tc.want, err)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package infer
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/client-go/rest"
+
+	"k8s-nim-operator-cli/pkg/util/client"
+	"k8s-nim-operator-cli/pkg/util/openai"
+)
+
+// RunChat reads user messages line by line from stdin and streams each answer, keeping the conversation history.
+func RunChat(ctx context.Context, options *InferenceOptions, k8sClient client.Client, restConfig *rest.Config) error {
+	c, model, stop, err := Connect(ctx, options, k8sClient, restConfig)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	out := options.IoStreams.Out
+	fmt.Fprintf(out, "Chatting with %s at %s. Type /reset to clear the history, /exit to quit.\n", model, c.BaseURL)
+
+	messages := InitialMessages(options)
+	scanner := bufio.NewScanner(options.IoStreams.In)
+	for {
+		fmt.Fprint(out, ">>> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return scanner.Err()
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		switch line {
+		case "":
+			continue
+		case "/exit", "/quit":
+			return nil
+		case "/reset":
+			messages = InitialMessages(options)
+			fmt.Fprintln(out, "History cleared.")
+			continue
+		}
+
+		messages = append(messages, openai.ChatMessage{Role: "user", Content: line})
+		result, err := c.StreamChatCompletion(ctx, NewChatRequest(options, model, messages), func(delta string) error {
+			_, err := fmt.Fprint(out, delta)
+			return err
+		})
+		fmt.Fprintln(out)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			// Drop the unanswered message so the next turn starts from a consistent history.
+			messages = messages[:len(messages)-1]
+			fmt.Fprintf(options.IoStreams.ErrOut, "error: %v\n", err)
+			continue
+		}
+		messages = append(messages, openai.ChatMessage{Role: "assistant", Content: result.Content})
+	}
+}
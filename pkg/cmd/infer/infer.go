@@ -0,0 +1,219 @@
+package infer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/utils/ptr"
+
+	"k8s-nim-operator-cli/pkg/util"
+	"k8s-nim-operator-cli/pkg/util/client"
+	"k8s-nim-operator-cli/pkg/util/openai"
+)
+
+type InferenceOptions struct {
+	*util.FetchResourceOptions
+	Prompt       string
+	Model        string
+	SystemPrompt string
+	MaxTokens    int
+	Temperature  float64
+	Endpoint     string
+	PortForward  bool
+}
+
+func NewInferenceOptions(cmdFactory cmdutil.Factory, streams genericclioptions.IOStreams) *InferenceOptions {
+	return &InferenceOptions{FetchResourceOptions: util.NewFetchResourceOptions(cmdFactory, streams)}
+}
+
+func NewInferCommand(cmdFactory cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	options := NewInferenceOptions(cmdFactory, streams)
+
+	cmd := &cobra.Command{
+		Use:   "infer RESOURCE NAME",
+		Short: "Send a single chat completion to a NIMService",
+		Long: `Send a single prompt to the OpenAI-compatible /v1/chat/completions endpoint of a NIMService and stream the answer.
+
+The endpoint is taken from --endpoint, then from the NIMService's status.model.externalEndpoint. If neither is set, a
+port-forward to a ready pod is opened for the duration of the request. --model defaults to status.model.name.`,
+		Example: `  nim infer nimservice llama3-nimservice --prompt "What is a GPU?"
+  nim infer nimservice llama3-nimservice --prompt "Summarize Kubernetes" --max-tokens=256 --temperature=0.2`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runWithNIMService(cmd, args, cmdFactory, options, RunInfer)
+		},
+	}
+
+	cmd.Flags().StringVar(&options.Prompt, "prompt", "", "Prompt to send as the user message. Required")
+	AddInferenceFlags(cmd, options)
+	cmd.SetHelpTemplate(helpTemplate)
+
+	return cmd
+}
+
+func NewChatCommand(cmdFactory cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	options := NewInferenceOptions(cmdFactory, streams)
+
+	cmd := &cobra.Command{
+		Use:   "chat RESOURCE NAME",
+		Short: "Start an interactive chat with a NIMService",
+		Long: `Start an interactive chat session against the OpenAI-compatible /v1/chat/completions endpoint of a NIMService.
+
+Each line read from stdin is sent as a user message and the answer is streamed back. The conversation history is kept
+for the session. Type /reset to clear the history and /exit (or Ctrl+D) to quit. Endpoint and model resolution is the
+same as for "nim infer".`,
+		Example: `  nim chat nimservice llama3-nimservice
+  nim chat nimservice llama3-nimservice --system-prompt="You are a terse assistant."`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runWithNIMService(cmd, args, cmdFactory, options, RunChat)
+		},
+	}
+
+	AddInferenceFlags(cmd, options)
+	cmd.SetHelpTemplate(helpTemplate)
+
+	return cmd
+}
+
+// AddInferenceFlags binds the model, sampling and endpoint flags shared by commands that send chat completions.
+func AddInferenceFlags(cmd *cobra.Command, options *InferenceOptions) {
+	cmd.Flags().StringVar(&options.Model, "model", util.InferenceModel, "Model to request. Defaults to the NIMService's status.model.name.")
+	cmd.Flags().StringVar(&options.SystemPrompt, "system-prompt", util.SystemPrompt, "Optional system prompt sent before the user messages.")
+	cmd.Flags().IntVar(&options.MaxTokens, "max-tokens", util.MaxTokens, "Maximum number of tokens to generate per answer.")
+	cmd.Flags().Float64Var(&options.Temperature, "temperature", util.Temperature, "Sampling temperature. The server default is used if not set.")
+	cmd.Flags().StringVar(&options.Endpoint, "endpoint", util.InferenceEndpoint, "Base URL of the NIM API, e.g. http://localhost:8000. Skips endpoint discovery.")
+	cmd.Flags().BoolVar(&options.PortForward, "port-forward", false, "Always port-forward to a NIMService pod, even if an external endpoint is reported.")
+}
+
+// Common argument handling for commands that target a single NIMService.
+func runWithNIMService(cmd *cobra.Command, args []string, cmdFactory cmdutil.Factory, options *InferenceOptions,
+	run func(context.Context, *InferenceOptions, client.Client, *rest.Config) error) error {
+	switch len(args) {
+	case 0:
+		// Show help if no args provided.
+		cmd.HelpFunc()(cmd, args)
+	case 2:
+		if err := options.CompleteNamespace(args, cmd); err != nil {
+			return err
+		}
+		if options.ResourceType != util.NIMService {
+			return fmt.Errorf("%s only supports nimservice, got %q", cmd.Name(), args[0])
+		}
+		k8sClient, err := client.NewClient(cmdFactory)
+		if err != nil {
+			return fmt.Errorf("failed to create client: %w", err)
+		}
+		restConfig, err := cmdFactory.ToRESTConfig()
+		if err != nil {
+			return fmt.Errorf("failed to get REST config: %w", err)
+		}
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		return run(ctx, options, k8sClient, restConfig)
+	default:
+		fmt.Println(fmt.Errorf("unknown command(s) %q", strings.Join(args, " ")))
+	}
+	return nil
+}
+
+// Connect resolves the NIMService endpoint and model name. The returned func releases any port-forward.
+func Connect(ctx context.Context, options *InferenceOptions, k8sClient client.Client, restConfig *rest.Config) (*openai.Client, string, func(), error) {
+	nimservice, err := util.FetchNIMService(ctx, options.FetchResourceOptions, k8sClient)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	model := options.Model
+	if model == "" && nimservice.Status.Model != nil {
+		model = nimservice.Status.Model.Name
+	}
+	if model == "" {
+		return nil, "", nil, fmt.Errorf("NIMService %q does not report a model name yet; specify one with --model", nimservice.GetName())
+	}
+
+	baseURL, stop, err := util.ConnectNIMService(ctx, restConfig, k8sClient.KubernetesClient(), nimservice, options.Endpoint, options.PortForward, options.IoStreams.ErrOut)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return openai.NewClient(baseURL), model, stop, nil
+}
+
+// NewChatRequest returns the chat request for the given messages, applying the sampling flags.
+func NewChatRequest(options *InferenceOptions, model string, messages []openai.ChatMessage) openai.ChatCompletionRequest {
+	request := openai.ChatCompletionRequest{
+		Model:     model,
+		Messages:  messages,
+		MaxTokens: options.MaxTokens,
+	}
+	if options.Temperature >= 0 {
+		request.Temperature = ptr.To(options.Temperature)
+	}
+	return request
+}
+
+// InitialMessages returns the initial conversation, which only holds the system prompt if one is set.
+func InitialMessages(options *InferenceOptions) []openai.ChatMessage {
+	if options.SystemPrompt == "" {
+		return nil
+	}
+	return []openai.ChatMessage{{Role: "system", Content: options.SystemPrompt}}
+}
+
+// RunInfer sends options.Prompt and streams the answer to stdout.
+func RunInfer(ctx context.Context, options *InferenceOptions, k8sClient client.Client, restConfig *rest.Config) error {
+	if strings.TrimSpace(options.Prompt) == "" {
+		return fmt.Errorf("--prompt must be set")
+	}
+
+	c, model, stop, err := Connect(ctx, options, k8sClient, restConfig)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	messages := append(InitialMessages(options), openai.ChatMessage{Role: "user", Content: options.Prompt})
+	out := options.IoStreams.Out
+	if _, err := c.StreamChatCompletion(ctx, NewChatRequest(options, model, messages), func(delta string) error {
+		_, err := fmt.Fprint(out, delta)
+		return err
+	}); err != nil {
+		return err
+	}
+	fmt.Fprintln(out)
+	return nil
+}
+
+// Custom help message template. Needed to show supported resource types as a custom category to be consistent with "Available Commands" for get and status.
+const helpTemplate = `{{- if .Long }}{{ .Long }}{{- else }}{{ .Short }}{{- end }}
+
+Usage:{{if .Runnable}}
+  {{.UseLine}}{{end}}
+
+Supported RESOURCE types:
+  nimservice   Send chat completions to a NIMService.
+
+{{if .HasExample}}Examples:
+{{ .Example }}
+
+{{end}}{{if .HasAvailableLocalFlags}}Flags:
+{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}
+
+{{end}}{{if .HasAvailableInheritedFlags}}Global Flags:
+{{.InheritedFlags.FlagUsages | trimTrailingWhitespaces}}
+
+{{end}}{{if .HasHelpSubCommands}}Additional help topics:{{range .Commands}}{{if .IsAdditionalHelpTopicCommand}}
+  {{.CommandPath}} {{.Short}}{{end}}{{end}}
+
+{{end}}{{if .HasAvailableSubCommands}}Available Commands:{{range .Commands}}{{if (and .IsAvailableCommand (not .IsAdditionalHelpTopicCommand))}}
+  {{rpad .Name .NamePadding }} {{.Short}}{{end}}{{end}}
+
+{{end}}`
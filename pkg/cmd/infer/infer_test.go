@@ -0,0 +1,142 @@
+package infer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"k8s-nim-operator-cli/pkg/util/client/fake"
+	"k8s-nim-operator-cli/pkg/util/openai"
+)
+
+func newTestClient(modelName string) *fake.Client {
+	nimservice := &appsv1alpha1.NIMService{ObjectMeta: metav1.ObjectMeta{Name: "llama", Namespace: "nim"}}
+	if modelName != "" {
+		nimservice.Status.Model = &appsv1alpha1.ModelStatus{Name: modelName}
+	}
+	return fake.NewFakeClient(nil, []runtime.Object{nimservice})
+}
+
+// newChatServer stands in for a NIM and streams the answer word by word, recording every request it receives.
+func newChatServer(t *testing.T, answer string, requests *[]openai.ChatCompletionRequest) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			http.NotFound(w, r)
+			return
+		}
+		var req openai.ChatCompletionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		*requests = append(*requests, req)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, word := range strings.SplitAfter(answer, " ") {
+			fmt.Fprintf(w, "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":%q}}]}\n\n", word)
+		}
+		fmt.Fprint(w, "data: {\"choices\":[{\"index\":0,\"delta\":{},\"finish_reason\":\"stop\"}]}\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[],\"usage\":{\"prompt_tokens\":3,\"completion_tokens\":4,\"total_tokens\":7}}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+}
+
+func newTestOptions(endpoint string) (*InferenceOptions, *bytes.Buffer, *bytes.Buffer) {
+	in, out := &bytes.Buffer{}, &bytes.Buffer{}
+	options := NewInferenceOptions(nil, genericclioptions.IOStreams{In: in, Out: out, ErrOut: &bytes.Buffer{}})
+	options.Namespace = "nim"
+	options.ResourceName = "llama"
+	options.Endpoint = endpoint
+	options.MaxTokens = 64
+	options.Temperature = -1
+	return options, in, out
+}
+
+func Test_RunInfer(t *testing.T) {
+	var requests []openai.ChatCompletionRequest
+	server := newChatServer(t, "GPUs are fast.", &requests)
+	defer server.Close()
+
+	options, _, out := newTestOptions(server.URL)
+	options.Prompt = "What is a GPU?"
+	options.SystemPrompt = "Be brief."
+
+	if err := RunInfer(context.Background(), options, newTestClient("meta/llama-3.1-8b-instruct"), nil); err != nil {
+		t.Fatalf("RunInfer error: %v", err)
+	}
+	if got := out.String(); got != "GPUs are fast.\n" {
+		t.Fatalf("output = %q", got)
+	}
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(requests))
+	}
+	req := requests[0]
+	if req.Model != "meta/llama-3.1-8b-instruct" || !req.Stream || req.MaxTokens != 64 || req.Temperature != nil {
+		t.Fatalf("unexpected request: %+v", req)
+	}
+	if len(req.Messages) != 2 || req.Messages[0].Role != "system" || req.Messages[1].Content != "What is a GPU?" {
+		t.Fatalf("unexpected messages: %+v", req.Messages)
+	}
+}
+
+func Test_RunInfer_ModelRequired(t *testing.T) {
+	options, _, _ := newTestOptions("http://localhost:1")
+	options.Prompt = "hi"
+	if err := RunInfer(context.Background(), options, newTestClient(""), nil); err == nil || !strings.Contains(err.Error(), "--model") {
+		t.Fatalf("expected --model error, got %v", err)
+	}
+}
+
+func Test_RunInfer_ServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "model not loaded", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	options, _, _ := newTestOptions(server.URL)
+	options.Prompt = "hi"
+	options.Model = "m"
+	err := RunInfer(context.Background(), options, newTestClient(""), nil)
+	if err == nil || !strings.Contains(err.Error(), "503") || !strings.Contains(err.Error(), "model not loaded") {
+		t.Fatalf("expected 503 error with body, got %v", err)
+	}
+}
+
+func Test_RunChat(t *testing.T) {
+	var requests []openai.ChatCompletionRequest
+	server := newChatServer(t, "Hello there.", &requests)
+	defer server.Close()
+
+	options, in, out := newTestOptions(server.URL)
+	options.Temperature = 0.5
+	in.WriteString("hi\nhow are you?\n/reset\nagain\n/exit\n")
+
+	if err := RunChat(context.Background(), options, newTestClient("m"), nil); err != nil {
+		t.Fatalf("RunChat error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Hello there.") || !strings.Contains(out.String(), "History cleared.") {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+	if len(requests) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(requests))
+	}
+	// The second turn carries the first exchange; the turn after /reset starts over.
+	if len(requests[1].Messages) != 3 || requests[1].Messages[1].Role != "assistant" {
+		t.Fatalf("history not kept: %+v", requests[1].Messages)
+	}
+	if len(requests[2].Messages) != 1 || requests[2].Messages[0].Content != "again" {
+		t.Fatalf("history not reset: %+v", requests[2].Messages)
+	}
+	if requests[0].Temperature == nil || *requests[0].Temperature != 0.5 {
+		t.Fatalf("temperature not sent: %+v", requests[0])
+	}
+}
@@ -0,0 +1,295 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	"k8s-nim-operator-cli/pkg/util"
+	"k8s-nim-operator-cli/pkg/util/client"
+	nimconfig "k8s-nim-operator-cli/pkg/util/config"
+)
+
+// Node label set by GPU feature discovery; the operator matches profiles against it when the spec names no GPU.
+const gpuProductLabel = "nvidia.com/gpu.product"
+
+type ManifestOptions struct {
+	*util.FetchResourceOptions
+	Output string
+}
+
+func NewManifestCommand(cmdFactory cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	options := &ManifestOptions{FetchResourceOptions: util.NewFetchResourceOptions(cmdFactory, streams)}
+
+	cmd := &cobra.Command{
+		Use:   "manifest RESOURCE NAME",
+		Short: "Show every profile in a NIMCache's model manifest",
+		Long: `Show every profile the model manifest of a NIMCache offers, not only the cached ones, with its tags.
+
+The manifest is read from the ConfigMap the operator creates for the NIMCache. Each profile is marked as selected when it
+matches the NIMCache's model spec (engine, precision, tensor parallelism, GPUs, LoRA, QoS profile) and as cached when the
+NIMCache reports it in its status. For profiles that were not selected, the MISMATCH column says which part of the spec
+ruled them out, which explains why a combination of --gpus, --engine and --tensor-parallelism matched nothing.`,
+		Example: `  nim manifest nimcache llama3-nimcache
+  nim manifest nimcache llama3-nimcache -n nim-service -o yaml`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch len(args) {
+			case 0:
+				// Show help if no args provided.
+				cmd.HelpFunc()(cmd, args)
+			case 2:
+				if err := options.CompleteNamespace(args, cmd); err != nil {
+					return err
+				}
+				if options.ResourceType != util.NIMCache {
+					return fmt.Errorf("manifest only supports nimcache, got %q", args[0])
+				}
+				if options.Output != "table" && options.Output != "yaml" {
+					return fmt.Errorf("unsupported output format %q. Valid formats are: table, yaml", options.Output)
+				}
+				k8sClient, err := client.NewClient(cmdFactory)
+				if err != nil {
+					return fmt.Errorf("failed to create client: %w", err)
+				}
+				return Run(cmd.Context(), options, k8sClient)
+			default:
+				fmt.Println(fmt.Errorf("unknown command(s) %q", strings.Join(args, " ")))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.Output, "output", "o", "table", "Output format. One of: table, yaml. yaml prints the raw manifest.")
+	cmd.SetHelpTemplate(helpTemplate)
+
+	nimconfig.BindOutput(cmd.Flags(), "table", "yaml")
+
+	return cmd
+}
+
+func Run(ctx context.Context, options *ManifestOptions, k8sClient client.Client) error {
+	nimcache, err := util.FetchNIMCache(ctx, options.FetchResourceOptions, k8sClient)
+	if err != nil {
+		return err
+	}
+	configMap, err := findManifestConfigMap(ctx, k8sClient, nimcache)
+	if err != nil {
+		return err
+	}
+	data, ok := configMap.Data[util.ModelManifestKey]
+	if !ok {
+		return fmt.Errorf("ConfigMap %s/%s has no %s key", configMap.GetNamespace(), configMap.GetName(), util.ModelManifestKey)
+	}
+
+	out := options.IoStreams.Out
+	if options.Output == "yaml" {
+		fmt.Fprint(out, data)
+		return nil
+	}
+
+	manifest, err := util.ParseModelManifest([]byte(data))
+	if err != nil {
+		return err
+	}
+	if len(manifest.Profiles) == 0 {
+		return fmt.Errorf("the model manifest of NIMCache %s/%s lists no profiles", nimcache.GetNamespace(), nimcache.GetName())
+	}
+
+	cached := map[string]bool{}
+	for _, profile := range nimcache.Status.Profiles {
+		cached[profile.Name] = true
+	}
+	spec := nimcache.GetModelSpec()
+	discoveredGPUs := discoverGPUs(ctx, k8sClient)
+
+	rows := make([]profileRow, 0, len(manifest.Profiles))
+	selected := 0
+	for _, profile := range manifest.Profiles {
+		row := profileRow{ProfileInfo: profile, Cached: cached[profile.ID]}
+		row.Mismatches = util.ProfileMismatches(spec, profile, discoveredGPUs)
+		if len(row.Mismatches) == 0 {
+			selected++
+		}
+		rows = append(rows, row)
+	}
+
+	if err := printManifest(rows, out); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "\n%d of %d profiles match the model spec of NIMCache %s/%s (%s), %d cached.\n",
+		selected, len(rows), nimcache.GetNamespace(), nimcache.GetName(), describeSpec(spec), len(cached))
+	if selected == 0 && len(spec.Profiles) == 0 {
+		fmt.Fprintln(out, "No profile matches; relax the selection parameters or pick a profile ID above with --profiles.")
+	}
+	return nil
+}
+
+type profileRow struct {
+	util.ProfileInfo
+	Cached     bool
+	Mismatches []string
+}
+
+// Returns the ConfigMap holding the NIMCache's model manifest: the one controlled by the NIMCache, or failing that the one
+// with the name the operator gives it.
+func findManifestConfigMap(ctx context.Context, k8sClient client.Client, nimcache *appsv1alpha1.NIMCache) (*corev1.ConfigMap, error) {
+	configMaps, err := k8sClient.KubernetesClient().CoreV1().ConfigMaps(nimcache.GetNamespace()).List(ctx, v1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ConfigMaps: %w", err)
+	}
+
+	var byName *corev1.ConfigMap
+	for i := range configMaps.Items {
+		configMap := &configMaps.Items[i]
+		for _, owner := range configMap.GetOwnerReferences() {
+			if owner.Kind != "NIMCache" {
+				continue
+			}
+			if owner.UID == nimcache.GetUID() || (owner.UID == "" && owner.Name == nimcache.GetName()) {
+				if _, ok := configMap.Data[util.ModelManifestKey]; ok {
+					return configMap, nil
+				}
+			}
+		}
+		if configMap.GetName() == nimcache.GetName()+util.ModelManifestSuffix {
+			byName = configMap
+		}
+	}
+	if byName != nil {
+		return byName, nil
+	}
+	return nil, fmt.Errorf("no model manifest ConfigMap found for NIMCache %s/%s; the operator creates it once the model puller has run (state: %s)",
+		nimcache.GetNamespace(), nimcache.GetName(), nimcache.Status.State)
+}
+
+// Returns the GPU products labelled on the cluster's nodes. Listing nodes often needs more permissions than the namespace,
+// so failures are ignored and profiles are then matched against the spec only.
+func discoverGPUs(ctx context.Context, k8sClient client.Client) []string {
+	nodes, err := k8sClient.KubernetesClient().CoreV1().Nodes().List(ctx, v1.ListOptions{LabelSelector: gpuProductLabel})
+	if err != nil {
+		return nil
+	}
+	var products []string
+	seen := map[string]bool{}
+	for _, node := range nodes.Items {
+		if product := node.GetLabels()[gpuProductLabel]; product != "" && !seen[product] {
+			seen[product] = true
+			products = append(products, product)
+		}
+	}
+	return products
+}
+
+// Summarizes the selection parameters of the model spec, e.g. "engine=vllm, tp=2".
+func describeSpec(spec appsv1alpha1.ModelSpec) string {
+	if len(spec.Profiles) > 0 {
+		return "profiles=" + strings.Join(spec.Profiles, ",")
+	}
+	var params []string
+	for _, param := range [][2]string{
+		{"engine", spec.Engine},
+		{"precision", spec.Precision},
+		{"tp", spec.TensorParallelism},
+		{"qos-profile", spec.QoSProfile},
+	} {
+		if param[1] != "" {
+			params = append(params, param[0]+"="+param[1])
+		}
+	}
+	var gpus []string
+	for _, gpu := range spec.GPUs {
+		gpus = append(gpus, gpu.Product)
+	}
+	if len(gpus) > 0 {
+		params = append(params, "gpus="+strings.Join(gpus, ","))
+	}
+	if spec.Lora != nil {
+		params = append(params, fmt.Sprintf("lora=%t", *spec.Lora))
+	}
+	if spec.Buildable != nil {
+		params = append(params, fmt.Sprintf("buildable=%t", *spec.Buildable))
+	}
+	if len(params) == 0 {
+		return "no selection parameters"
+	}
+	return strings.Join(params, ", ")
+}
+
+func printManifest(rows []profileRow, output io.Writer) error {
+	resultTablePrinter := printers.NewTablePrinter(printers.PrintOptions{})
+
+	resTable := &v1.Table{
+		ColumnDefinitions: []v1.TableColumnDefinition{
+			{Name: "Profile ID", Type: "string"},
+			{Name: "Engine", Type: "string"},
+			{Name: "Precision", Type: "string"},
+			{Name: "TP", Type: "string"},
+			{Name: "PP", Type: "string"},
+			{Name: "GPU", Type: "string"},
+			{Name: "LLM Engine", Type: "string"},
+			{Name: "LoRA", Type: "string"},
+			{Name: "QoS Profile", Type: "string"},
+			{Name: "Selected", Type: "string"},
+			{Name: "Cached", Type: "string"},
+			{Name: "Mismatch", Type: "string"},
+		},
+	}
+
+	for _, row := range rows {
+		resTable.Rows = append(resTable.Rows, v1.TableRow{
+			Cells: []interface{}{
+				row.ID,
+				util.OrNone(row.Engine),
+				util.OrNone(row.Precision),
+				util.OrNone(row.TP),
+				util.OrNone(row.PP),
+				util.OrNone(row.GPU),
+				util.OrNone(row.LLMEngine),
+				util.OrNone(row.Lora),
+				util.OrNone(row.Profile),
+				yesNo(len(row.Mismatches) == 0),
+				yesNo(row.Cached),
+				util.OrNone(strings.Join(row.Mismatches, "; ")),
+			},
+		})
+	}
+
+	return resultTablePrinter.PrintObj(resTable, output)
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// Custom help message template. Needed to show supported resource types as a custom category to be consistent with "Available Commands" for get and status.
+const helpTemplate = `{{- if .Long }}{{ .Long }}{{- else }}{{ .Short }}{{- end }}
+
+Usage:{{if .Runnable}}
+  {{.UseLine}}{{end}}
+
+Supported RESOURCE types:
+  nimcache     Show the profiles in a NIMCache's model manifest.
+
+{{if .HasExample}}Examples:
+{{ .Example }}
+
+{{end}}{{if .HasAvailableLocalFlags}}Flags:
+{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}
+
+{{end}}{{if .HasAvailableInheritedFlags}}Global Flags:
+{{.InheritedFlags.FlagUsages | trimTrailingWhitespaces}}
+
+{{end}}`
@@ -0,0 +1,183 @@
+package manifest
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"k8s-nim-operator-cli/pkg/util"
+	"k8s-nim-operator-cli/pkg/util/client/fake"
+)
+
+const manifestV1 = `
+trt-fp8-tp2-h100:
+  model: meta/llama3-8b-instruct
+  release: "1.0.0"
+  tags:
+    llm_engine: tensorrt_llm
+    precision: fp8
+    tp: "2"
+    gpu: H100
+    profile: throughput
+trt-fp8-tp1-l40s:
+  model: meta/llama3-8b-instruct
+  release: "1.0.0"
+  tags:
+    llm_engine: tensorrt_llm
+    precision: fp8
+    tp: "1"
+    gpu: L40S
+    profile: latency
+vllm-bf16-tp1:
+  model: meta/llama3-8b-instruct
+  release: "1.0.0"
+  tags:
+    llm_engine: vllm
+    precision: bf16
+    tp: "1"
+    feat_lora: "false"
+`
+
+const manifestV2 = `
+schema_version: "2.0"
+profile_selection_criteria: tags
+profiles:
+- id: vllm-bf16-tp1
+  tags:
+    llm_engine: vllm
+    precision: bf16
+    tp: "1"
+- id: vllm-bf16-tp2
+  tags:
+    llm_engine: vllm
+    precision: bf16
+    tp: "2"
+`
+
+func newTestClient(spec appsv1alpha1.ModelSpec, manifest string, cached ...string) *fake.Client {
+	nimcache := &appsv1alpha1.NIMCache{ObjectMeta: metav1.ObjectMeta{Name: "llama", Namespace: "nim", UID: types.UID("cache-uid")}}
+	nimcache.Spec.Source.NGC = &appsv1alpha1.NGCSource{Model: &spec}
+	for _, id := range cached {
+		nimcache.Status.Profiles = append(nimcache.Status.Profiles, appsv1alpha1.NIMProfile{Name: id})
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			// Not the default name, so the lookup has to go through the owner reference.
+			Name:            "llama-profiles",
+			Namespace:       "nim",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "NIMCache", Name: "llama", UID: types.UID("cache-uid")}},
+		},
+		Data: map[string]string{util.ModelManifestKey: manifest},
+	}
+	return fake.NewFakeClient([]runtime.Object{configMap}, []runtime.Object{nimcache})
+}
+
+func newTestOptions(out *bytes.Buffer) *ManifestOptions {
+	options := &ManifestOptions{
+		FetchResourceOptions: util.NewFetchResourceOptions(nil, genericclioptions.IOStreams{Out: out, ErrOut: &bytes.Buffer{}}),
+		Output:               "table",
+	}
+	options.Namespace = "nim"
+	options.ResourceName = "llama"
+	return options
+}
+
+// Returns the table rows keyed by profile ID.
+func parseRows(output string) map[string]string {
+	rows := map[string]string{}
+	for _, line := range strings.Split(output, "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 && fields[0] != "PROFILE" {
+			rows[fields[0]] = line
+		}
+	}
+	return rows
+}
+
+func Test_Run_V1MarksSelectedAndCached(t *testing.T) {
+	out := &bytes.Buffer{}
+	spec := appsv1alpha1.ModelSpec{Engine: "tensorrt_llm", GPUs: []appsv1alpha1.GPUSpec{{Product: "h100"}}}
+	if err := Run(context.Background(), newTestOptions(out), newTestClient(spec, manifestV1, "trt-fp8-tp2-h100")); err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	rows := parseRows(out.String())
+	if fields := strings.Fields(rows["trt-fp8-tp2-h100"]); len(fields) < 3 || strings.Join(fields[len(fields)-3:], " ") != "yes yes <none>" {
+		t.Fatalf("expected h100 profile selected and cached:\n%s", out.String())
+	}
+	if row := rows["trt-fp8-tp1-l40s"]; !strings.Contains(row, "gpu L40S not requested or found on nodes") {
+		t.Fatalf("expected GPU mismatch for l40s profile:\n%s", out.String())
+	}
+	if row := rows["vllm-bf16-tp1"]; !strings.HasSuffix(row, "engine vllm != tensorrt_llm") {
+		t.Fatalf("expected engine mismatch for vllm profile:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "1 of 3 profiles match the model spec of NIMCache nim/llama (engine=tensorrt_llm, gpus=h100), 1 cached.") {
+		t.Fatalf("unexpected summary:\n%s", out.String())
+	}
+}
+
+func Test_Run_V2NothingMatches(t *testing.T) {
+	out := &bytes.Buffer{}
+	spec := appsv1alpha1.ModelSpec{Engine: "vllm", TensorParallelism: "4"}
+	if err := Run(context.Background(), newTestOptions(out), newTestClient(spec, manifestV2)); err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	rows := parseRows(out.String())
+	if len(rows) < 2 || !strings.Contains(rows["vllm-bf16-tp1"], "tp 1 != 4") || !strings.Contains(rows["vllm-bf16-tp2"], "tp 2 != 4") {
+		t.Fatalf("expected tp mismatches:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "No profile matches") {
+		t.Fatalf("expected hint when nothing matches:\n%s", out.String())
+	}
+}
+
+func Test_Run_ProfileWithoutGPUTag(t *testing.T) {
+	const manifest = `
+trt-fp8-tp1:
+  tags:
+    llm_engine: tensorrt_llm
+    precision: fp8
+    tp: "1"
+`
+	out := &bytes.Buffer{}
+	client := newTestClient(appsv1alpha1.ModelSpec{Engine: "tensorrt_llm"}, manifest)
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "gpu-1", Labels: map[string]string{gpuProductLabel: "NVIDIA-H100-80GB-HBM3"}}}
+	if _, err := client.Kube.CoreV1().Nodes().Create(context.Background(), node, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("create node: %v", err)
+	}
+	if err := Run(context.Background(), newTestOptions(out), client); err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	// Without a gpu tag the profile must not be taken to match every discovered GPU.
+	if row := parseRows(out.String())["trt-fp8-tp1"]; !strings.Contains(row, "no gpu tag to match") || strings.Contains(row, " yes ") {
+		t.Fatalf("expected unknown GPU for profile without gpu tag:\n%s", out.String())
+	}
+}
+
+func Test_Run_YAML(t *testing.T) {
+	out := &bytes.Buffer{}
+	options := newTestOptions(out)
+	options.Output = "yaml"
+	if err := Run(context.Background(), options, newTestClient(appsv1alpha1.ModelSpec{}, manifestV2)); err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	if out.String() != manifestV2 {
+		t.Fatalf("expected raw manifest, got:\n%s", out.String())
+	}
+}
+
+func Test_Run_NoConfigMap(t *testing.T) {
+	nimcache := &appsv1alpha1.NIMCache{ObjectMeta: metav1.ObjectMeta{Name: "llama", Namespace: "nim"}}
+	nimcache.Status.State = "Pending"
+	client := fake.NewFakeClient(nil, []runtime.Object{nimcache})
+	err := Run(context.Background(), newTestOptions(&bytes.Buffer{}), client)
+	if err == nil || !strings.Contains(err.Error(), "no model manifest ConfigMap found") {
+		t.Fatalf("expected missing ConfigMap error, got %v", err)
+	}
+}
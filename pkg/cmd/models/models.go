@@ -0,0 +1,157 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/rest"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	"k8s-nim-operator-cli/pkg/util"
+	"k8s-nim-operator-cli/pkg/util/client"
+	"k8s-nim-operator-cli/pkg/util/openai"
+)
+
+type ModelsOptions struct {
+	*util.FetchResourceOptions
+	Endpoint    string
+	PortForward bool
+}
+
+func NewModelsCommand(cmdFactory cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	options := &ModelsOptions{FetchResourceOptions: util.NewFetchResourceOptions(cmdFactory, streams)}
+
+	cmd := &cobra.Command{
+		Use:   "models RESOURCE NAME",
+		Short: "List the models served by a NIMService",
+		Long: `Query the /v1/models endpoint of a NIMService and list the served model IDs, including LoRA adapters.
+
+The endpoint is taken from --endpoint, then from the NIMService's status.model.externalEndpoint. If neither is set, a
+port-forward to a ready pod is opened for the duration of the request.`,
+		Example: `  nim models nimservice llama3-nimservice
+  nim models nimservice llama3-nimservice --endpoint=http://llama3.example.com`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch len(args) {
+			case 0:
+				// Show help if no args provided.
+				cmd.HelpFunc()(cmd, args)
+			case 2:
+				if err := options.CompleteNamespace(args, cmd); err != nil {
+					return err
+				}
+				if options.ResourceType != util.NIMService {
+					return fmt.Errorf("models only supports nimservice, got %q", args[0])
+				}
+				k8sClient, err := client.NewClient(cmdFactory)
+				if err != nil {
+					return fmt.Errorf("failed to create client: %w", err)
+				}
+				restConfig, err := cmdFactory.ToRESTConfig()
+				if err != nil {
+					return fmt.Errorf("failed to get REST config: %w", err)
+				}
+				ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+				defer stop()
+				return Run(ctx, options, k8sClient, restConfig)
+			default:
+				fmt.Println(fmt.Errorf("unknown command(s) %q", strings.Join(args, " ")))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&options.Endpoint, "endpoint", util.InferenceEndpoint, "Base URL of the NIM API, e.g. http://localhost:8000. Skips endpoint discovery.")
+	cmd.Flags().BoolVar(&options.PortForward, "port-forward", false, "Always port-forward to a NIMService pod, even if an external endpoint is reported.")
+	cmd.SetHelpTemplate(helpTemplate)
+
+	return cmd
+}
+
+func Run(ctx context.Context, options *ModelsOptions, k8sClient client.Client, restConfig *rest.Config) error {
+	nimservice, err := util.FetchNIMService(ctx, options.FetchResourceOptions, k8sClient)
+	if err != nil {
+		return err
+	}
+
+	baseURL, stop, err := util.ConnectNIMService(ctx, restConfig, k8sClient.KubernetesClient(), nimservice, options.Endpoint, options.PortForward, options.IoStreams.ErrOut)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	models, err := openai.NewClient(baseURL).ListModels(ctx)
+	if err != nil {
+		return err
+	}
+	if len(models) == 0 {
+		fmt.Fprintf(options.IoStreams.ErrOut, "NIMService %s/%s does not serve any models yet.\n", nimservice.GetNamespace(), nimservice.GetName())
+		return nil
+	}
+	return printModels(models, options.IoStreams.Out)
+}
+
+func printModels(models []openai.Model, output io.Writer) error {
+	resultTablePrinter := printers.NewTablePrinter(printers.PrintOptions{})
+
+	resTable := &v1.Table{
+		ColumnDefinitions: []v1.TableColumnDefinition{
+			{Name: "ID", Type: "string"},
+			{Name: "Type", Type: "string"},
+			{Name: "Parent", Type: "string"},
+			{Name: "Owned By", Type: "string"},
+			{Name: "Max Model Len", Type: "string"},
+		},
+	}
+
+	for _, model := range models {
+		kind, parent := "base", "<none>"
+		if model.IsAdapter() {
+			kind, parent = "lora", model.Parent
+		}
+		maxModelLen := "<unknown>"
+		if model.MaxModelLen > 0 {
+			maxModelLen = fmt.Sprintf("%d", model.MaxModelLen)
+		}
+		resTable.Rows = append(resTable.Rows, v1.TableRow{
+			Cells: []interface{}{
+				model.ID,
+				kind,
+				parent,
+				model.OwnedBy,
+				maxModelLen,
+			},
+		})
+	}
+
+	return resultTablePrinter.PrintObj(resTable, output)
+}
+
+// Custom help message template. Needed to show supported resource types as a custom category to be consistent with "Available Commands" for get and status.
+const helpTemplate = `{{- if .Long }}{{ .Long }}{{- else }}{{ .Short }}{{- end }}
+
+Usage:{{if .Runnable}}
+  {{.UseLine}}{{end}}
+
+Supported RESOURCE types:
+  nimservice   List the models served by a NIMService.
+
+{{if .HasExample}}Examples:
+{{ .Example }}
+
+{{end}}{{if .HasAvailableLocalFlags}}Flags:
+{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}
+
+{{end}}{{if .HasAvailableInheritedFlags}}Global Flags:
+{{.InheritedFlags.FlagUsages | trimTrailingWhitespaces}}
+
+{{end}}`
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"k8s-nim-operator-cli/pkg/util"
+	"k8s-nim-operator-cli/pkg/util/client/fake"
+)
+
+func Test_Run_ListsBaseModelAndAdapters(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/models" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"object":"list","data":[
+			{"id":"meta/llama-3.1-8b-instruct","object":"model","owned_by":"system","root":"meta/llama-3.1-8b-instruct","max_model_len":8192},
+			{"id":"llama-3.1-8b-sql-lora","object":"model","owned_by":"system","root":"/loras/sql","parent":"meta/llama-3.1-8b-instruct"}
+		]}`))
+	}))
+	defer server.Close()
+
+	nimservice := &appsv1alpha1.NIMService{ObjectMeta: metav1.ObjectMeta{Name: "llama", Namespace: "nim"}}
+	k8sClient := fake.NewFakeClient(nil, []runtime.Object{nimservice})
+
+	out := &bytes.Buffer{}
+	options := &ModelsOptions{
+		FetchResourceOptions: util.NewFetchResourceOptions(nil, genericclioptions.IOStreams{Out: out, ErrOut: &bytes.Buffer{}}),
+		Endpoint:             server.URL + "/v1/",
+	}
+	options.Namespace = "nim"
+	options.ResourceName = "llama"
+
+	if err := Run(context.Background(), options, k8sClient, nil); err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header and 2 rows, got:\n%s", out.String())
+	}
+	if fields := strings.Fields(lines[1]); fields[0] != "meta/llama-3.1-8b-instruct" || fields[1] != "base" || fields[4] != "8192" {
+		t.Fatalf("unexpected base model row: %q", lines[1])
+	}
+	if fields := strings.Fields(lines[2]); fields[0] != "llama-3.1-8b-sql-lora" || fields[1] != "lora" || fields[2] != "meta/llama-3.1-8b-instruct" {
+		t.Fatalf("unexpected adapter row: %q", lines[2])
+	}
+}
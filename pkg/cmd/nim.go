@@ -9,12 +9,30 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
+	"k8s-nim-operator-cli/pkg/cmd/apply"
+	"k8s-nim-operator-cli/pkg/cmd/backup"
+	"k8s-nim-operator-cli/pkg/cmd/bench"
+	"k8s-nim-operator-cli/pkg/cmd/capacity"
+	"k8s-nim-operator-cli/pkg/cmd/config"
 	"k8s-nim-operator-cli/pkg/cmd/delete"
+	"k8s-nim-operator-cli/pkg/cmd/diff"
+	"k8s-nim-operator-cli/pkg/cmd/events"
+	"k8s-nim-operator-cli/pkg/cmd/export"
 	"k8s-nim-operator-cli/pkg/cmd/create"
 	"k8s-nim-operator-cli/pkg/cmd/get"
+	"k8s-nim-operator-cli/pkg/cmd/health"
+	"k8s-nim-operator-cli/pkg/cmd/infer"
 	"k8s-nim-operator-cli/pkg/cmd/log"
+	"k8s-nim-operator-cli/pkg/cmd/models"
+	"k8s-nim-operator-cli/pkg/cmd/portforward"
+	"k8s-nim-operator-cli/pkg/cmd/preflight"
+	"k8s-nim-operator-cli/pkg/cmd/manifest"
+	"k8s-nim-operator-cli/pkg/cmd/profiles"
 	"k8s-nim-operator-cli/pkg/cmd/status"
+	"k8s-nim-operator-cli/pkg/cmd/top"
 	"k8s-nim-operator-cli/pkg/cmd/deploy"
+	"k8s-nim-operator-cli/pkg/cmd/validate"
+	nimconfig "k8s-nim-operator-cli/pkg/util/config"
 )
 
 func init() {
@@ -47,12 +65,36 @@ func NewNIMCommand(streams genericiooptions.IOStreams) *cobra.Command {
 
 	cmdFactory := cmdutil.NewFactory(configFlags)
 
+	// Flag defaults come from the user and project config and NIM_* variables, for the current context and namespace.
+	cmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		return nimconfig.ApplyToCommand(cmd, configFlags)
+	}
+
 	cmd.AddCommand(get.NewGetCommand(cmdFactory, streams))
 	cmd.AddCommand(status.NewStatusCommand(cmdFactory, streams))
 	cmd.AddCommand(log.NewLogCommand(cmdFactory, streams))
 	cmd.AddCommand(delete.NewDeleteCommand(cmdFactory, streams))
 	cmd.AddCommand(create.NewCreateCommand(cmdFactory, streams))
 	cmd.AddCommand(deploy.NewDeployCommand(cmdFactory, streams))
+	cmd.AddCommand(portforward.NewPortForwardCommand(cmdFactory, streams))
+	cmd.AddCommand(infer.NewInferCommand(cmdFactory, streams))
+	cmd.AddCommand(infer.NewChatCommand(cmdFactory, streams))
+	cmd.AddCommand(models.NewModelsCommand(cmdFactory, streams))
+	cmd.AddCommand(health.NewHealthCommand(cmdFactory, streams))
+	cmd.AddCommand(bench.NewBenchCommand(cmdFactory, streams))
+	cmd.AddCommand(preflight.NewPreflightCommand(cmdFactory, streams))
+	cmd.AddCommand(profiles.NewProfilesCommand(cmdFactory, streams))
+	cmd.AddCommand(manifest.NewManifestCommand(cmdFactory, streams))
+	cmd.AddCommand(validate.NewValidateCommand(cmdFactory, streams))
+	cmd.AddCommand(apply.NewApplyCommand(cmdFactory, streams))
+	cmd.AddCommand(config.NewConfigCommand(configFlags, streams))
+	cmd.AddCommand(export.NewExportCommand(cmdFactory, streams))
+	cmd.AddCommand(diff.NewDiffCommand(cmdFactory, streams))
+	cmd.AddCommand(backup.NewBackupCommand(cmdFactory, streams))
+	cmd.AddCommand(backup.NewRestoreCommand(cmdFactory, streams))
+	cmd.AddCommand(events.NewEventsCommand(cmdFactory, streams))
+	cmd.AddCommand(top.NewTopCommand(cmdFactory, streams))
+	cmd.AddCommand(capacity.NewCapacityCommand(cmdFactory, streams))
 
 	return cmd
 }
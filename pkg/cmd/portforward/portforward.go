@@ -0,0 +1,148 @@
+package portforward
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	"k8s-nim-operator-cli/pkg/util"
+	"k8s-nim-operator-cli/pkg/util/client"
+)
+
+type PortForwardOptions struct {
+	*util.FetchResourceOptions
+	Address   string
+	LocalPort int
+}
+
+func NewPortForwardCommand(cmdFactory cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	options := &PortForwardOptions{FetchResourceOptions: util.NewFetchResourceOptions(cmdFactory, streams)}
+
+	cmd := &cobra.Command{
+		Use:   "port-forward RESOURCE NAME [LOCAL_PORT]",
+		Short: "Forward a local port to a NIMService",
+		Long: `Forward a local port to a ready pod behind the Service of a NIMService.
+
+The Service is the one the operator generates for the NIMService and the port is spec.expose.service.port. LOCAL_PORT defaults to the
+Service port; use 0 to pick a random free port. Forwarding runs until interrupted.`,
+		Example: `  nim port-forward nimservice llama3-nimservice
+  nim port-forward nimservice llama3-nimservice 9000 -n nim-service`,
+		Aliases:      []string{"pf"},
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch len(args) {
+			case 0:
+				// Show help if no args provided.
+				cmd.HelpFunc()(cmd, args)
+			case 2, 3:
+				if err := options.CompleteNamespace(args[:2], cmd); err != nil {
+					return err
+				}
+				if options.ResourceType != util.NIMService {
+					return fmt.Errorf("port-forward only supports nimservice, got %q", args[0])
+				}
+				options.LocalPort = -1
+				if len(args) == 3 {
+					port, err := strconv.ParseUint(args[2], 10, 16)
+					if err != nil {
+						return fmt.Errorf("invalid LOCAL_PORT %q: %w", args[2], err)
+					}
+					options.LocalPort = int(port)
+				}
+				k8sClient, err := client.NewClient(cmdFactory)
+				if err != nil {
+					return fmt.Errorf("failed to create client: %w", err)
+				}
+				restConfig, err := cmdFactory.ToRESTConfig()
+				if err != nil {
+					return fmt.Errorf("failed to get REST config: %w", err)
+				}
+				ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+				defer stop()
+				return Run(ctx, options, k8sClient, restConfig)
+			default:
+				fmt.Println(fmt.Errorf("unknown command(s) %q", strings.Join(args, " ")))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&options.Address, "address", "localhost", "Address to listen on. Only accepts IP addresses or localhost.")
+	cmd.SetHelpTemplate(helpTemplate)
+
+	return cmd
+}
+
+// Run resolves the NIMService's Service and a ready pod, then forwards until ctx is cancelled.
+func Run(ctx context.Context, options *PortForwardOptions, k8sClient client.Client, restConfig *rest.Config) error {
+	nimservice, err := util.FetchNIMService(ctx, options.FetchResourceOptions, k8sClient)
+	if err != nil {
+		return err
+	}
+
+	target, err := util.ResolvePortForwardTarget(ctx, k8sClient.KubernetesClient(), nimservice)
+	if err != nil {
+		return err
+	}
+
+	localPort := options.LocalPort
+	if localPort < 0 {
+		localPort = int(target.ServicePort)
+	}
+
+	// Port-forward's own "Forwarding from" lines are replaced by the summary below.
+	bound, done, err := util.StartPortForward(ctx, restConfig, k8sClient.KubernetesClient(), target, options.Address, localPort, io.Discard, options.IoStreams.ErrOut)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(options.IoStreams.Out, "Forwarding from %s:%d -> service/%s:%d (pod/%s:%d)\n", options.Address, bound, target.ServiceName, target.ServicePort, target.PodName, target.PodPort)
+	fmt.Fprintf(options.IoStreams.Out, "OpenAI-compatible base URL: %s\n", BaseURL(options.Address, bound))
+	fmt.Fprintln(options.IoStreams.Out, "Press Ctrl+C to stop forwarding.")
+
+	return <-done
+}
+
+// BaseURL returns the OpenAI-compatible base URL for a forwarded port.
+func BaseURL(address string, port uint16) string {
+	return fmt.Sprintf("http://%s:%d/v1", address, port)
+}
+
+// Custom help message template. Needed to show supported resource types as a custom category to be consistent with "Available Commands" for get and status.
+const helpTemplate = `{{- if .Long }}{{ .Long }}{{- else }}{{ .Short }}{{- end }}
+
+Usage:{{if .Runnable}}
+  {{.UseLine}}{{end}}
+
+{{if gt (len .Aliases) 0}}Aliases:
+  {{.NameAndAliases}}
+
+{{end}}Supported RESOURCE types:
+  nimservice   Forward a local port to a NIMService.
+
+{{if .HasExample}}Examples:
+{{ .Example }}
+
+{{end}}{{if .HasAvailableLocalFlags}}Flags:
+{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}
+
+{{end}}{{if .HasAvailableInheritedFlags}}Global Flags:
+{{.InheritedFlags.FlagUsages | trimTrailingWhitespaces}}
+
+{{end}}{{if .HasHelpSubCommands}}Additional help topics:{{range .Commands}}{{if .IsAdditionalHelpTopicCommand}}
+  {{.CommandPath}} {{.Short}}{{end}}{{end}}
+
+{{end}}{{if .HasAvailableSubCommands}}Available Commands:{{range .Commands}}{{if (and .IsAvailableCommand (not .IsAdditionalHelpTopicCommand))}}
+  {{rpad .Name .NamePadding }} {{.Short}}{{end}}{{end}}
+
+{{end}}`
@@ -0,0 +1,264 @@
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s-nim-operator-cli/pkg/util"
+	"k8s-nim-operator-cli/pkg/util/client"
+
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+)
+
+const (
+	gpuPresentLabel     = "nvidia.com/gpu.present=true"
+	gpuResource         = corev1.ResourceName("nvidia.com/gpu")
+	operatorLabel       = "app.kubernetes.io/name=k8s-nim-operator"
+	defaultClassKey     = "storageclass.kubernetes.io/is-default-class"
+	betaDefaultClassKey = "storageclass.beta.kubernetes.io/is-default-class"
+	ngcAPIKeyKey        = "NGC_API_KEY"
+)
+
+// Provisioners known to support ReadWriteMany volumes. The list is not exhaustive.
+var rwxProvisioners = []string{
+	"nfs", "efs.csi.aws.com", "filestore.csi.storage.gke.io", "file.csi.azure.com", "kubernetes.io/azure-file",
+	"cephfs", "csi.trident.netapp.io", "driver.longhorn.io", "nfs.csi.k8s.io", "spectrumscale.csi.ibm.com",
+}
+
+// Checks that the NIMService and NIMCache CRDs are served by the API server.
+func checkCRDs(ctx context.Context, options *PreflightOptions, k8sClient client.Client) []CheckResult {
+	const name = "CRDs"
+	groupVersion := appsv1alpha1.SchemeGroupVersion.String()
+	resources, err := k8sClient.KubernetesClient().Discovery().ServerResourcesForGroupVersion(groupVersion)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return []CheckResult{{Name: name, Status: Fail, Message: fmt.Sprintf("unable to discover %s: %v", groupVersion, err)}}
+	}
+
+	served := map[string]bool{}
+	if resources != nil {
+		for _, resource := range resources.APIResources {
+			served[resource.Name] = true
+		}
+	}
+	var missing []string
+	for _, resource := range []string{"nimservices", "nimcaches"} {
+		if !served[resource] {
+			missing = append(missing, resource)
+		}
+	}
+	if len(missing) > 0 {
+		return []CheckResult{{
+			Name:        name,
+			Status:      Fail,
+			Message:     fmt.Sprintf("%s not served by %s", strings.Join(missing, ", "), groupVersion),
+			Remediation: "Install the NIM Operator, e.g. helm install nim-operator nvidia/k8s-nim-operator -n nim-operator --create-namespace",
+		}}
+	}
+	return []CheckResult{{Name: name, Status: Pass, Message: fmt.Sprintf("nimservices and nimcaches are served by %s", groupVersion)}}
+}
+
+// Checks that an operator deployment exists and is available.
+func checkOperator(ctx context.Context, options *PreflightOptions, k8sClient client.Client) []CheckResult {
+	const name = "Operator"
+	deployments, err := k8sClient.KubernetesClient().AppsV1().Deployments(options.OperatorNamespace).List(ctx, v1.ListOptions{LabelSelector: operatorLabel})
+	if err != nil {
+		return []CheckResult{{Name: name, Status: Warn, Message: fmt.Sprintf("unable to list deployments: %v", err),
+			Remediation: "Verify the operator manually, e.g. kubectl get deployments -A -l " + operatorLabel}}
+	}
+	if len(deployments.Items) == 0 {
+		return []CheckResult{{
+			Name:        name,
+			Status:      Fail,
+			Message:     fmt.Sprintf("no deployment labelled %s found", operatorLabel),
+			Remediation: "Install the NIM Operator, e.g. helm install nim-operator nvidia/k8s-nim-operator -n nim-operator --create-namespace",
+		}}
+	}
+
+	deployment := deployments.Items[0]
+	location := fmt.Sprintf("%s/%s", deployment.GetNamespace(), deployment.GetName())
+	if deployment.Status.AvailableReplicas < 1 {
+		return []CheckResult{{
+			Name:        name,
+			Status:      Fail,
+			Message:     fmt.Sprintf("deployment %s has no available replicas", location),
+			Remediation: fmt.Sprintf("Inspect the operator pods: kubectl describe deployment %s -n %s", deployment.GetName(), deployment.GetNamespace()),
+		}}
+	}
+	return []CheckResult{{Name: name, Status: Pass, Message: fmt.Sprintf("deployment %s is available", location)}}
+}
+
+// Checks that GPU nodes exist and advertise allocatable GPUs.
+func checkGPUNodes(ctx context.Context, options *PreflightOptions, k8sClient client.Client) []CheckResult {
+	const name = "GPU nodes"
+	nodes, err := k8sClient.KubernetesClient().CoreV1().Nodes().List(ctx, v1.ListOptions{LabelSelector: gpuPresentLabel})
+	if err != nil {
+		return []CheckResult{{Name: name, Status: Warn, Message: fmt.Sprintf("unable to list nodes: %v", err),
+			Remediation: "Listing nodes requires cluster-scoped read access; ask a cluster admin to verify GPU nodes."}}
+	}
+	if len(nodes.Items) == 0 {
+		return []CheckResult{{
+			Name:        name,
+			Status:      Fail,
+			Message:     fmt.Sprintf("no nodes labelled %s", gpuPresentLabel),
+			Remediation: "Install the NVIDIA GPU Operator (or NFD and the device plugin) so GPU nodes are labelled and advertise nvidia.com/gpu.",
+		}}
+	}
+
+	var total int64
+	var withoutGPUs []string
+	for _, node := range nodes.Items {
+		allocatable := node.Status.Allocatable[gpuResource]
+		if allocatable.Value() == 0 {
+			withoutGPUs = append(withoutGPUs, node.GetName())
+		}
+		total += allocatable.Value()
+	}
+	if total == 0 {
+		return []CheckResult{{
+			Name:        name,
+			Status:      Fail,
+			Message:     fmt.Sprintf("%d GPU node(s) but no allocatable %s", len(nodes.Items), gpuResource),
+			Remediation: "Check that the NVIDIA device plugin pods are running on the GPU nodes.",
+		}}
+	}
+	if len(withoutGPUs) > 0 {
+		sort.Strings(withoutGPUs)
+		return []CheckResult{{
+			Name:        name,
+			Status:      Warn,
+			Message:     fmt.Sprintf("%d allocatable GPU(s); no allocatable %s on %s", total, gpuResource, strings.Join(withoutGPUs, ", ")),
+			Remediation: "Check the NVIDIA device plugin on the listed nodes.",
+		}}
+	}
+	return []CheckResult{{Name: name, Status: Pass, Message: fmt.Sprintf("%d allocatable GPU(s) on %d node(s)", total, len(nodes.Items))}}
+}
+
+// Checks that PVCs can be provisioned without naming a StorageClass, or that an RWX-capable class exists.
+func checkStorageClasses(ctx context.Context, options *PreflightOptions, k8sClient client.Client) []CheckResult {
+	const name = "StorageClass"
+	classes, err := k8sClient.KubernetesClient().StorageV1().StorageClasses().List(ctx, v1.ListOptions{})
+	if err != nil {
+		return []CheckResult{{Name: name, Status: Warn, Message: fmt.Sprintf("unable to list StorageClasses: %v", err),
+			Remediation: "Use --pvc-storage-class with a StorageClass you know exists, or an existing PVC with --pvc-storage-name."}}
+	}
+	if len(classes.Items) == 0 {
+		return []CheckResult{{
+			Name:        name,
+			Status:      Fail,
+			Message:     "no StorageClasses found",
+			Remediation: "Install a CSI driver with a StorageClass, or pre-create a PVC and pass it with --pvc-storage-name.",
+		}}
+	}
+
+	var defaults, rwx []string
+	for _, class := range classes.Items {
+		annotations := class.GetAnnotations()
+		if annotations[defaultClassKey] == "true" || annotations[betaDefaultClassKey] == "true" {
+			defaults = append(defaults, class.GetName())
+		}
+		for _, provisioner := range rwxProvisioners {
+			if strings.Contains(class.Provisioner, provisioner) {
+				rwx = append(rwx, class.GetName())
+				break
+			}
+		}
+	}
+
+	switch {
+	case len(defaults) > 0 && len(rwx) > 0:
+		return []CheckResult{{Name: name, Status: Pass, Message: fmt.Sprintf("default %s; RWX-capable %s", strings.Join(defaults, ", "), strings.Join(rwx, ", "))}}
+	case len(defaults) > 0:
+		return []CheckResult{{Name: name, Status: Pass, Message: fmt.Sprintf("default %s", strings.Join(defaults, ", "))}}
+	case len(rwx) > 0:
+		return []CheckResult{{
+			Name:        name,
+			Status:      Pass,
+			Message:     fmt.Sprintf("no default StorageClass; RWX-capable %s", strings.Join(rwx, ", ")),
+			Remediation: fmt.Sprintf("Pass --pvc-storage-class=%s when creating PVCs.", rwx[0]),
+		}}
+	default:
+		return []CheckResult{{
+			Name:        name,
+			Status:      Warn,
+			Message:     "no default or known RWX-capable StorageClass",
+			Remediation: fmt.Sprintf("Mark a StorageClass as default (annotation %s=true) or pass --pvc-storage-class explicitly.", defaultClassKey),
+		}}
+	}
+}
+
+// Checks the image pull secret and the NGC API key secret nim create would use.
+func checkSecrets(ctx context.Context, options *PreflightOptions, k8sClient client.Client) []CheckResult {
+	remediation := fmt.Sprintf("nim create secret ngc --api-key-from-env=%s -n %s", ngcAPIKeyKey, options.Namespace)
+	if options.AuthSecret != util.AuthSecret {
+		remediation += " --auth-secret=" + options.AuthSecret
+	}
+	if options.PullSecret != util.PullSecret {
+		remediation += " --pull-secret=" + options.PullSecret
+	}
+	return []CheckResult{
+		checkSecret(ctx, options, k8sClient, options.PullSecret, corev1.SecretTypeDockerConfigJson, corev1.DockerConfigJsonKey, remediation),
+		checkSecret(ctx, options, k8sClient, options.AuthSecret, corev1.SecretTypeOpaque, ngcAPIKeyKey, remediation),
+	}
+}
+
+func checkSecret(ctx context.Context, options *PreflightOptions, k8sClient client.Client, secretName string, secretType corev1.SecretType, key, remediation string) CheckResult {
+	name := "Secret " + secretName
+	secret, err := k8sClient.KubernetesClient().CoreV1().Secrets(options.Namespace).Get(ctx, secretName, v1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return CheckResult{Name: name, Status: Fail, Message: fmt.Sprintf("not found in namespace %s", options.Namespace), Remediation: remediation}
+	}
+	if err != nil {
+		return CheckResult{Name: name, Status: Warn, Message: fmt.Sprintf("unable to read secret: %v", err),
+			Remediation: "Reading secrets may be restricted; verify the secret exists with your cluster admin."}
+	}
+	if secret.Type != secretType {
+		return CheckResult{Name: name, Status: Fail, Message: fmt.Sprintf("type is %s, expected %s", secret.Type, secretType),
+			Remediation: remediation + " --overwrite"}
+	}
+	if len(secret.Data[key]) == 0 {
+		return CheckResult{Name: name, Status: Fail, Message: fmt.Sprintf("key %s is missing or empty", key),
+			Remediation: remediation + " --overwrite"}
+	}
+	return CheckResult{Name: name, Status: Pass, Message: fmt.Sprintf("%s with key %s", secretType, key)}
+}
+
+// Checks that the caller may create NIMServices and NIMCaches in the namespace.
+func checkRBAC(ctx context.Context, options *PreflightOptions, k8sClient client.Client) []CheckResult {
+	var results []CheckResult
+	for _, resource := range []string{"nimservices", "nimcaches"} {
+		name := "RBAC create " + resource
+		review := &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Namespace: options.Namespace,
+					Verb:      "create",
+					Group:     appsv1alpha1.SchemeGroupVersion.Group,
+					Resource:  resource,
+				},
+			},
+		}
+		response, err := k8sClient.KubernetesClient().AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, v1.CreateOptions{})
+		switch {
+		case err != nil:
+			results = append(results, CheckResult{Name: name, Status: Warn, Message: fmt.Sprintf("unable to review access: %v", err),
+				Remediation: fmt.Sprintf("Verify manually: kubectl auth can-i create %s.%s -n %s", resource, appsv1alpha1.SchemeGroupVersion.Group, options.Namespace)})
+		case !response.Status.Allowed:
+			message := fmt.Sprintf("not allowed in namespace %s", options.Namespace)
+			if response.Status.Reason != "" {
+				message += ": " + response.Status.Reason
+			}
+			results = append(results, CheckResult{Name: name, Status: Fail, Message: message,
+				Remediation: fmt.Sprintf("Ask a cluster admin to bind a Role granting create on %s.%s in %s.", resource, appsv1alpha1.SchemeGroupVersion.Group, options.Namespace)})
+		default:
+			results = append(results, CheckResult{Name: name, Status: Pass, Message: fmt.Sprintf("allowed in namespace %s", options.Namespace)})
+		}
+	}
+	return results
+}
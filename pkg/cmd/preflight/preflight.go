@@ -0,0 +1,152 @@
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	"k8s-nim-operator-cli/pkg/util"
+	"k8s-nim-operator-cli/pkg/util/client"
+	nimconfig "k8s-nim-operator-cli/pkg/util/config"
+)
+
+type CheckStatus string
+
+const (
+	Pass CheckStatus = "PASS"
+	Warn CheckStatus = "WARN"
+	Fail CheckStatus = "FAIL"
+)
+
+// CheckResult is the outcome of a single preflight check. Remediation is a hint shown for warnings and failures.
+type CheckResult struct {
+	Name        string
+	Status      CheckStatus
+	Message     string
+	Remediation string
+}
+
+type check func(context.Context, *PreflightOptions, client.Client) []CheckResult
+
+// Checks in the order they are reported.
+var checks = []check{
+	checkCRDs,
+	checkOperator,
+	checkGPUNodes,
+	checkStorageClasses,
+	checkSecrets,
+	checkRBAC,
+}
+
+type PreflightOptions struct {
+	*util.FetchResourceOptions
+	// OperatorNamespace limits the operator lookup; empty searches all namespaces.
+	OperatorNamespace string
+	// Secrets nim create would use, from the flags or the config.
+	AuthSecret string
+	PullSecret string
+}
+
+func NewPreflightCommand(cmdFactory cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	options := &PreflightOptions{FetchResourceOptions: util.NewFetchResourceOptions(cmdFactory, streams)}
+
+	cmd := &cobra.Command{
+		Use:   "preflight",
+		Short: "Check that a cluster and namespace are ready for NIM Operator resources",
+		Long: `Run readiness checks before creating NIMServices and NIMCaches and print PASS, WARN or FAIL with remediation hints.
+
+Checks:
+  - the NIMService and NIMCache CRDs are served and the operator deployment is available;
+  - nodes labelled nvidia.com/gpu.present=true have allocatable nvidia.com/gpu;
+  - a default or ReadWriteMany-capable StorageClass exists;
+  - the pull secret and the NGC API key secret (ngc-secret and ngc-api-secret unless configured or set with
+    --pull-secret and --auth-secret) exist in the namespace with the right type and keys;
+  - the caller may create NIMServices and NIMCaches in the namespace.
+
+The command exits with an error if any check fails.`,
+		Example: `  nim preflight
+  nim preflight -n nim-service`,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := options.CompleteNamespace(args, cmd); err != nil {
+				return err
+			}
+			k8sClient, err := client.NewClient(cmdFactory)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			return Run(cmd.Context(), options, k8sClient)
+		},
+	}
+
+	cmd.Flags().StringVar(&options.OperatorNamespace, "operator-namespace", "", "Namespace of the NIM Operator. All namespaces are searched if not set.")
+	cmd.Flags().StringVar(&options.AuthSecret, "auth-secret", util.AuthSecret, "Name of the opaque secret holding NGC_API_KEY to check.")
+	cmd.Flags().StringVar(&options.PullSecret, "pull-secret", util.PullSecret, "Name of the image pull secret to check.")
+
+	nimconfig.Bind(cmd.Flags(), "operator-namespace", nimconfig.OperatorNamespace)
+	nimconfig.Bind(cmd.Flags(), "auth-secret", nimconfig.AuthSecret)
+	nimconfig.Bind(cmd.Flags(), "pull-secret", nimconfig.PullSecrets)
+
+	return cmd
+}
+
+func Run(ctx context.Context, options *PreflightOptions, k8sClient client.Client) error {
+	var results []CheckResult
+	for _, check := range checks {
+		results = append(results, check(ctx, options, k8sClient)...)
+	}
+
+	if err := printResults(results, options.IoStreams.Out); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Status == Fail {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d preflight check(s) failed", failed)
+	}
+	return nil
+}
+
+func printResults(results []CheckResult, output io.Writer) error {
+	resultTablePrinter := printers.NewTablePrinter(printers.PrintOptions{})
+
+	resTable := &v1.Table{
+		ColumnDefinitions: []v1.TableColumnDefinition{
+			{Name: "Check", Type: "string"},
+			{Name: "Result", Type: "string"},
+			{Name: "Message", Type: "string"},
+		},
+	}
+	for _, result := range results {
+		resTable.Rows = append(resTable.Rows, v1.TableRow{
+			Cells: []interface{}{result.Name, string(result.Status), result.Message},
+		})
+	}
+	if err := resultTablePrinter.PrintObj(resTable, output); err != nil {
+		return err
+	}
+
+	var hints []string
+	for _, result := range results {
+		if result.Remediation != "" {
+			hints = append(hints, fmt.Sprintf("  - %s: %s", result.Name, result.Remediation))
+		}
+	}
+	if len(hints) > 0 {
+		fmt.Fprintf(output, "\nRemediation:\n%s\n", strings.Join(hints, "\n"))
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package preflight
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"k8s-nim-operator-cli/pkg/util"
+	"k8s-nim-operator-cli/pkg/util/client/fake"
+)
+
+func newGPUNode(name string, gpus int64) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"nvidia.com/gpu.present": "true"}},
+		Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+			gpuResource: *resource.NewQuantity(gpus, resource.DecimalSI),
+		}},
+	}
+}
+
+// newHealthyCluster returns objects for a cluster that passes every check.
+func newHealthyCluster() []runtime.Object {
+	return []runtime.Object{
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "k8s-nim-operator", Namespace: "nim-operator", Labels: map[string]string{"app.kubernetes.io/name": "k8s-nim-operator"}},
+			Status:     appsv1.DeploymentStatus{AvailableReplicas: 1},
+		},
+		newGPUNode("gpu-a", 8),
+		&storagev1.StorageClass{
+			ObjectMeta:  metav1.ObjectMeta{Name: "standard", Annotations: map[string]string{defaultClassKey: "true"}},
+			Provisioner: "ebs.csi.aws.com",
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: util.PullSecret, Namespace: "nim"},
+			Type:       corev1.SecretTypeDockerConfigJson,
+			Data:       map[string][]byte{corev1.DockerConfigJsonKey: []byte(`{"auths":{}}`)},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: util.AuthSecret, Namespace: "nim"},
+			Type:       corev1.SecretTypeOpaque,
+			Data:       map[string][]byte{ngcAPIKeyKey: []byte("nvapi-123")},
+		},
+	}
+}
+
+func newTestClient(withCRDs, allowed bool, objects ...runtime.Object) *fake.Client {
+	client := fake.NewFakeClient(objects, nil)
+	if withCRDs {
+		client.Kube.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{{
+			GroupVersion: "apps.nvidia.com/v1alpha1",
+			APIResources: []metav1.APIResource{{Name: "nimservices"}, {Name: "nimcaches"}},
+		}}
+	}
+	client.Kube.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		review.Status.Allowed = allowed
+		return true, review, nil
+	})
+	return client
+}
+
+func newTestOptions(out *bytes.Buffer) *PreflightOptions {
+	options := &PreflightOptions{
+		FetchResourceOptions: util.NewFetchResourceOptions(nil, genericclioptions.IOStreams{Out: out, ErrOut: &bytes.Buffer{}}),
+		AuthSecret:           util.AuthSecret,
+		PullSecret:           util.PullSecret,
+	}
+	options.Namespace = "nim"
+	return options
+}
+
+func Test_Run_AllPass(t *testing.T) {
+	out := &bytes.Buffer{}
+	if err := Run(context.Background(), newTestOptions(out), newTestClient(true, true, newHealthyCluster()...)); err != nil {
+		t.Fatalf("Run error: %v\n%s", err, out.String())
+	}
+	if strings.Contains(out.String(), string(Fail)) || strings.Contains(out.String(), string(Warn)) {
+		t.Fatalf("expected only passing checks:\n%s", out.String())
+	}
+	if strings.Contains(out.String(), "Remediation:") {
+		t.Fatalf("expected no remediation hints:\n%s", out.String())
+	}
+}
+
+func Test_Run_ReportsFailures(t *testing.T) {
+	objects := []runtime.Object{
+		newGPUNode("gpu-a", 4),
+		newGPUNode("gpu-b", 0),
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "local"}, Provisioner: "rancher.io/local-path"},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: util.PullSecret, Namespace: "nim"},
+			Type:       corev1.SecretTypeOpaque,
+		},
+	}
+	out := &bytes.Buffer{}
+	err := Run(context.Background(), newTestOptions(out), newTestClient(false, false, objects...))
+	if err == nil || err.Error() != "6 preflight check(s) failed" {
+		t.Fatalf("expected 6 failed checks, got %v\n%s", err, out.String())
+	}
+
+	results := map[string]string{}
+	for _, line := range strings.Split(out.String(), "\n") {
+		for _, status := range []CheckStatus{Pass, Warn, Fail} {
+			if i := strings.Index(line, " "+string(status)+" "); i > 0 {
+				results[strings.TrimSpace(line[:i])] = string(status)
+			}
+		}
+	}
+	expected := map[string]string{
+		"CRDs":                    "FAIL",
+		"Operator":                "FAIL",
+		"GPU nodes":               "WARN",
+		"StorageClass":            "WARN",
+		"Secret ngc-secret":       "FAIL",
+		"Secret ngc-api-secret":   "FAIL",
+		"RBAC create nimservices": "FAIL",
+		"RBAC create nimcaches":   "FAIL",
+	}
+	for name, status := range expected {
+		if results[name] != status {
+			t.Errorf("check %q: expected %s, got %q", name, status, results[name])
+		}
+	}
+	for _, want := range []string{"Remediation:", "no allocatable nvidia.com/gpu on gpu-b", "type is Opaque", "nim create secret ngc --api-key-from-env=NGC_API_KEY -n nim"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in output:\n%s", want, out.String())
+		}
+	}
+}
+
+func Test_checkGPUNodes_NoGPUs(t *testing.T) {
+	results := checkGPUNodes(context.Background(), newTestOptions(&bytes.Buffer{}), newTestClient(true, true))
+	if len(results) != 1 || results[0].Status != Fail || results[0].Remediation == "" {
+		t.Fatalf("expected failure with remediation, got %+v", results)
+	}
+}
+
+func Test_checkSecrets_ConfiguredNames(t *testing.T) {
+	options := newTestOptions(&bytes.Buffer{})
+	options.AuthSecret = "team-api-key"
+	options.PullSecret = "team-pull"
+	objects := []runtime.Object{&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-api-key", Namespace: "nim"},
+		Type:       corev1.SecretTypeOpaque,
+		Data:       map[string][]byte{ngcAPIKeyKey: []byte("nvapi-123")},
+	}}
+
+	results := checkSecrets(context.Background(), options, newTestClient(true, true, objects...))
+	if len(results) != 2 || results[0].Name != "Secret team-pull" || results[1].Name != "Secret team-api-key" {
+		t.Fatalf("expected the configured secrets to be checked, got %+v", results)
+	}
+	if results[0].Status != Fail || results[0].Remediation != "nim create secret ngc --api-key-from-env=NGC_API_KEY -n nim --auth-secret=team-api-key --pull-secret=team-pull" {
+		t.Errorf("unexpected pull secret result %+v", results[0])
+	}
+	if results[1].Status != Pass {
+		t.Errorf("expected the auth secret to pass, got %+v", results[1])
+	}
+}
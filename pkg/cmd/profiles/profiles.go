@@ -0,0 +1,194 @@
+package profiles
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	"k8s-nim-operator-cli/pkg/util"
+	"k8s-nim-operator-cli/pkg/util/client"
+	nimconfig "k8s-nim-operator-cli/pkg/util/config"
+)
+
+type ProfilesOptions struct {
+	*util.FetchResourceOptions
+	Filter util.ProfileFilter
+	Output string
+}
+
+func NewProfilesCommand(cmdFactory cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	options := &ProfilesOptions{FetchResourceOptions: util.NewFetchResourceOptions(cmdFactory, streams)}
+
+	cmd := &cobra.Command{
+		Use:   "profiles RESOURCE NAME",
+		Short: "List the model profiles cached by a NIMCache",
+		Long: `List the profiles reported in a NIMCache's status as a table of their config tags: engine, precision, tensor and
+pipeline parallelism, GPU, LLM engine, LoRA support and QoS profile.
+
+Filters narrow the list down. When a single profile is left, its ID is printed ready to paste into
+"nim create nimservice --nimcache-storage-profile" or "nim create nimcache --profiles". Use -o id to print only IDs.`,
+		Example: `  nim profiles nimcache llama3-nimcache
+  nim profiles nimcache llama3-nimcache --gpu=h100 --precision=fp8
+  nim profiles nimcache llama3-nimcache --engine=vllm -o id`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch len(args) {
+			case 0:
+				// Show help if no args provided.
+				cmd.HelpFunc()(cmd, args)
+			case 2:
+				if err := options.CompleteNamespace(args, cmd); err != nil {
+					return err
+				}
+				if options.ResourceType != util.NIMCache {
+					return fmt.Errorf("profiles only supports nimcache, got %q", args[0])
+				}
+				if options.Output != "table" && options.Output != "id" {
+					return fmt.Errorf("unsupported output format %q. Valid formats are: table, id", options.Output)
+				}
+				k8sClient, err := client.NewClient(cmdFactory)
+				if err != nil {
+					return fmt.Errorf("failed to create client: %w", err)
+				}
+				return Run(cmd.Context(), options, k8sClient)
+			default:
+				fmt.Println(fmt.Errorf("unknown command(s) %q", strings.Join(args, " ")))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&options.Filter.GPU, "gpu", "", "Only show profiles for this GPU product, e.g. h100. Matches substrings.")
+	cmd.Flags().StringVar(&options.Filter.Precision, "precision", "", "Only show profiles with this precision, e.g. fp8 or bf16.")
+	cmd.Flags().StringVar(&options.Filter.Engine, "engine", "", "Only show profiles with this engine or LLM engine, e.g. tensorrt_llm or vllm.")
+	cmd.Flags().StringVar(&options.Filter.TP, "tp", "", "Only show profiles with this tensor parallelism.")
+	cmd.Flags().StringVar(&options.Filter.Lora, "lora", "", "Only show profiles with (true) or without (false) LoRA support.")
+	cmd.Flags().StringVar(&options.Filter.Profile, "qos-profile", "", "Only show profiles optimized for 'throughput' or 'latency'.")
+	cmd.Flags().StringVarP(&options.Output, "output", "o", "table", "Output format. One of: table, id.")
+	cmd.SetHelpTemplate(helpTemplate)
+
+	nimconfig.BindOutput(cmd.Flags(), "table", "id")
+
+	return cmd
+}
+
+func Run(ctx context.Context, options *ProfilesOptions, k8sClient client.Client) error {
+	nimcache, err := util.FetchNIMCache(ctx, options.FetchResourceOptions, k8sClient)
+	if err != nil {
+		return err
+	}
+	if len(nimcache.Status.Profiles) == 0 {
+		fmt.Fprintf(options.IoStreams.ErrOut, "NIMCache %s/%s does not report any cached profiles yet (state: %s).\n",
+			nimcache.GetNamespace(), nimcache.GetName(), nimcache.Status.State)
+		return nil
+	}
+
+	var matched []util.ProfileInfo
+	for _, profile := range nimcache.Status.Profiles {
+		info := util.NewProfileInfo(profile)
+		if options.Filter.Matches(info) {
+			matched = append(matched, info)
+		}
+	}
+	if len(matched) == 0 {
+		return fmt.Errorf("none of the %d cached profiles match the filters", len(nimcache.Status.Profiles))
+	}
+	sortProfiles(matched)
+
+	out := options.IoStreams.Out
+	if options.Output == "id" {
+		for _, profile := range matched {
+			fmt.Fprintln(out, profile.ID)
+		}
+		return nil
+	}
+
+	if err := printProfiles(matched, out); err != nil {
+		return err
+	}
+	if len(matched) == 1 {
+		fmt.Fprintf(out, "\nSelected profile: %s\n  nim create nimservice ... --nimcache-storage-name=%s --nimcache-storage-profile=%s\n  nim create nimcache ... --profiles=%s\n",
+			matched[0].ID, nimcache.GetName(), matched[0].ID, matched[0].ID)
+	}
+	return nil
+}
+
+// Orders profiles by GPU, engine, precision and tensor parallelism so related profiles are listed together.
+func sortProfiles(profiles []util.ProfileInfo) {
+	sort.SliceStable(profiles, func(i, j int) bool {
+		a, b := profiles[i], profiles[j]
+		for _, pair := range [][2]string{{a.GPU, b.GPU}, {a.Engine, b.Engine}, {a.Precision, b.Precision}} {
+			if pair[0] != pair[1] {
+				return pair[0] < pair[1]
+			}
+		}
+		if len(a.TP) != len(b.TP) {
+			return len(a.TP) < len(b.TP)
+		}
+		return a.TP < b.TP
+	})
+}
+
+func printProfiles(profiles []util.ProfileInfo, output io.Writer) error {
+	resultTablePrinter := printers.NewTablePrinter(printers.PrintOptions{})
+
+	resTable := &v1.Table{
+		ColumnDefinitions: []v1.TableColumnDefinition{
+			{Name: "Profile ID", Type: "string"},
+			{Name: "Engine", Type: "string"},
+			{Name: "Precision", Type: "string"},
+			{Name: "TP", Type: "string"},
+			{Name: "PP", Type: "string"},
+			{Name: "GPU", Type: "string"},
+			{Name: "LLM Engine", Type: "string"},
+			{Name: "LoRA", Type: "string"},
+			{Name: "QoS Profile", Type: "string"},
+		},
+	}
+
+	for _, profile := range profiles {
+		resTable.Rows = append(resTable.Rows, v1.TableRow{
+			Cells: []interface{}{
+				profile.ID,
+				util.OrNone(profile.Engine),
+				util.OrNone(profile.Precision),
+				util.OrNone(profile.TP),
+				util.OrNone(profile.PP),
+				util.OrNone(profile.GPU),
+				util.OrNone(profile.LLMEngine),
+				util.OrNone(profile.Lora),
+				util.OrNone(profile.Profile),
+			},
+		})
+	}
+
+	return resultTablePrinter.PrintObj(resTable, output)
+}
+
+// Custom help message template. Needed to show supported resource types as a custom category to be consistent with "Available Commands" for get and status.
+const helpTemplate = `{{- if .Long }}{{ .Long }}{{- else }}{{ .Short }}{{- end }}
+
+Usage:{{if .Runnable}}
+  {{.UseLine}}{{end}}
+
+Supported RESOURCE types:
+  nimcache     List the model profiles cached by a NIMCache.
+
+{{if .HasExample}}Examples:
+{{ .Example }}
+
+{{end}}{{if .HasAvailableLocalFlags}}Flags:
+{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}
+
+{{end}}{{if .HasAvailableInheritedFlags}}Global Flags:
+{{.InheritedFlags.FlagUsages | trimTrailingWhitespaces}}
+
+{{end}}`
@@ -0,0 +1,86 @@
+package profiles
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"k8s-nim-operator-cli/pkg/util"
+	"k8s-nim-operator-cli/pkg/util/client/fake"
+)
+
+func newTestClient() *fake.Client {
+	nimcache := &appsv1alpha1.NIMCache{ObjectMeta: metav1.ObjectMeta{Name: "llama", Namespace: "nim"}}
+	nimcache.Status.Profiles = []appsv1alpha1.NIMProfile{
+		{Name: "vllm-bf16-tp1", Config: map[string]string{"engine": "vllm", "precision": "bf16", "tp": "1", "pp": "1", "feat_lora": "true"}},
+		{Name: "trt-fp8-tp2-h100", Config: map[string]string{"engine": "tensorrt_llm", "precision": "fp8", "tp": "2", "pp": "1", "gpu": "H100", "llm_engine": "tensorrt_llm", "profile": "throughput"}},
+		{Name: "trt-fp8-tp1-l40s", Config: map[string]string{"engine": "tensorrt_llm", "precision": "fp8", "tp": "1", "pp": "1", "gpu": "L40S", "llm_engine": "tensorrt_llm", "profile": "latency"}},
+	}
+	return fake.NewFakeClient(nil, []runtime.Object{nimcache})
+}
+
+func newTestOptions(out *bytes.Buffer) *ProfilesOptions {
+	options := &ProfilesOptions{
+		FetchResourceOptions: util.NewFetchResourceOptions(nil, genericclioptions.IOStreams{Out: out, ErrOut: &bytes.Buffer{}}),
+		Output:               "table",
+	}
+	options.Namespace = "nim"
+	options.ResourceName = "llama"
+	return options
+}
+
+func Test_Run_Table(t *testing.T) {
+	out := &bytes.Buffer{}
+	if err := Run(context.Background(), newTestOptions(out), newTestClient()); err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected header and 3 rows, got:\n%s", out.String())
+	}
+	for _, col := range []string{"PROFILE ID", "ENGINE", "PRECISION", "TP", "PP", "GPU", "LLM ENGINE", "LORA"} {
+		if !strings.Contains(lines[0], col) {
+			t.Fatalf("missing column %q in header %q", col, lines[0])
+		}
+	}
+	// Profiles without a GPU tag sort first, then by GPU.
+	if !strings.HasPrefix(lines[1], "vllm-bf16-tp1") || !strings.HasPrefix(lines[2], "trt-fp8-tp2-h100") || !strings.HasPrefix(lines[3], "trt-fp8-tp1-l40s") {
+		t.Fatalf("unexpected order:\n%s", out.String())
+	}
+	if strings.Contains(out.String(), "Selected profile") {
+		t.Fatalf("no profile should be selected when several match")
+	}
+}
+
+func Test_Run_FilterSelectsProfile(t *testing.T) {
+	out := &bytes.Buffer{}
+	options := newTestOptions(out)
+	options.Filter = util.ProfileFilter{GPU: "h100", Precision: "fp8"}
+	if err := Run(context.Background(), options, newTestClient()); err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	if !strings.Contains(out.String(), "--nimcache-storage-profile=trt-fp8-tp2-h100") || !strings.Contains(out.String(), "--profiles=trt-fp8-tp2-h100") {
+		t.Fatalf("expected paste-ready profile ID:\n%s", out.String())
+	}
+
+	out.Reset()
+	options.Filter = util.ProfileFilter{Engine: "tensorrt_llm"}
+	options.Output = "id"
+	if err := Run(context.Background(), options, newTestClient()); err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	if out.String() != "trt-fp8-tp2-h100\ntrt-fp8-tp1-l40s\n" {
+		t.Fatalf("unexpected id output: %q", out.String())
+	}
+
+	options.Filter = util.ProfileFilter{GPU: "a100"}
+	if err := Run(context.Background(), options, newTestClient()); err == nil {
+		t.Fatalf("expected error when no profile matches")
+	}
+}
@@ -48,7 +48,10 @@ func Run (ctx context.Context, options *util.FetchResourceOptions, k8sClient cli
 		if !ok {
 			return fmt.Errorf("failed to cast resourceList to NIMServiceList")
 		}
-		return printNIMServices(nimServiceList, options.IoStreams.Out)
+		if err := printNIMServices(nimServiceList, options.IoStreams.Out); err != nil {
+			return err
+		}
+		return util.PrintMultiNodePods(ctx, k8sClient.KubernetesClient(), nimServiceList, options.IoStreams.Out)
 
 	case util.NIMCache:
 		// Cast resourceList to NIMCacheList.
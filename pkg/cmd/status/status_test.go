@@ -2,12 +2,17 @@ package status
 
 import (
 	"bytes"
+	"context"
 	"strings"
 	"testing"
 	"time"
 
 	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"k8s-nim-operator-cli/pkg/util"
 )
 
 // NIMCache tests.
@@ -192,3 +197,47 @@ func Test_printNIMServices_status(t *testing.T) {
 		t.Fatalf("expected <unknown> age for zero timestamp row:\n%s", out)
 	}
 }
+
+func Test_PrintMultiNodePods(t *testing.T) {
+	single := newNIMService("single", "ns1")
+	multi := newNIMService("llama-405b", "ns1")
+	multi.Spec.MultiNode = &appsv1alpha1.NimServiceMultiNodeConfig{Size: 3, GPUSPerPod: 8}
+
+	pod := func(name, index string, ready bool) *corev1.Pod {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns1", Labels: map[string]string{
+				util.LWSNameLabel:        "llama-405b-lws",
+				util.LWSWorkerIndexLabel: index,
+			}},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning, Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}}},
+		}
+	}
+	kube := k8sfake.NewSimpleClientset(
+		pod("llama-405b-lws-0", "0", true),
+		pod("llama-405b-lws-0-1", "1", true),
+		pod("llama-405b-lws-0-2", "2", false),
+	)
+
+	var buf bytes.Buffer
+	list := &appsv1alpha1.NIMServiceList{Items: []appsv1alpha1.NIMService{single, multi}}
+	if err := util.PrintMultiNodePods(context.Background(), kube, list, &buf); err != nil {
+		t.Fatalf("PrintMultiNodePods error: %v", err)
+	}
+	out := buf.String()
+	if strings.Contains(out, "single") {
+		t.Fatalf("single-node NIMService should not be listed:\n%s", out)
+	}
+	if fields := strings.Fields(strings.Split(strings.TrimSpace(out), "\n")[1]); strings.Join(fields, " ") != "llama-405b ns1 3 8 1/1 1/2" {
+		t.Fatalf("unexpected multi-node row:\n%s", out)
+	}
+
+	// Nothing is printed when no NIMService is multi-node.
+	buf.Reset()
+	if err := util.PrintMultiNodePods(context.Background(), kube, &appsv1alpha1.NIMServiceList{Items: []appsv1alpha1.NIMService{single}}, &buf); err != nil || buf.Len() != 0 {
+		t.Fatalf("expected no output, got %q (err %v)", buf.String(), err)
+	}
+}
@@ -0,0 +1,196 @@
+package top
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	metricsAPIPath = "/apis/metrics.k8s.io/v1beta1"
+
+	// Default port of the DCGM exporter, used when its pod declares none.
+	dcgmDefaultPort = 9400
+
+	dcgmGPUUtil = "DCGM_FI_DEV_GPU_UTIL"
+	dcgmFBUsed  = "DCGM_FI_DEV_FB_USED"
+	dcgmFBFree  = "DCGM_FI_DEV_FB_FREE"
+)
+
+// Labels of the DCGM exporter pods: as deployed by the GPU Operator, and by the exporter's own Helm chart.
+var dcgmSelectors = []string{"app=nvidia-dcgm-exporter", "app.kubernetes.io/name=dcgm-exporter"}
+
+var errNoExporter = errors.New("no DCGM exporter found on the nodes running the pods")
+
+// The parts of metrics.k8s.io/v1beta1 PodMetrics that top reads.
+type podMetrics struct {
+	metav1.ObjectMeta `json:"metadata"`
+	Containers        []struct {
+		Name  string              `json:"name"`
+		Usage corev1.ResourceList `json:"usage"`
+	} `json:"containers"`
+}
+
+type podMetricsList struct {
+	Items []podMetrics `json:"items"`
+}
+
+// Returns a function listing pod metrics from the metrics.k8s.io API, in a namespace or in all of them.
+func metricsAPI(kube kubernetes.Interface) func(ctx context.Context, namespace string) ([]podMetrics, error) {
+	return func(ctx context.Context, namespace string) ([]podMetrics, error) {
+		path := metricsAPIPath + "/pods"
+		if namespace != "" {
+			path = metricsAPIPath + "/namespaces/" + namespace + "/pods"
+		}
+		data, err := kube.CoreV1().RESTClient().Get().AbsPath(path).DoRaw(ctx)
+		if err != nil {
+			return nil, err
+		}
+		list := &podMetricsList{}
+		if err := json.Unmarshal(data, list); err != nil {
+			return nil, fmt.Errorf("invalid response from the metrics API: %w", err)
+		}
+		return list.Items, nil
+	}
+}
+
+// Usage of one GPU, as reported by the DCGM exporter.
+type gpuSample struct {
+	util           float64
+	usedMiB        float64
+	freeMiB        float64
+	hasUtil, hasFB bool
+}
+
+type podKey struct {
+	namespace, name string
+}
+
+// GPU samples by the pod they are assigned to and the GPU's UUID.
+type gpuUsage map[podKey]map[string]*gpuSample
+
+// Scrapes the DCGM exporters running on the given nodes through the API server's pod proxy. Exporters that cannot be
+// scraped are reported on warnings; errNoExporter is returned when none run on those nodes.
+func scrapeDCGM(ctx context.Context, kube kubernetes.Interface, nodes map[string]bool, warnings io.Writer) (gpuUsage, error) {
+	var exporters []corev1.Pod
+	for _, selector := range dcgmSelectors {
+		pods, err := kube.CoreV1().Pods("").List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list DCGM exporter pods: %w", err)
+		}
+		for _, pod := range pods.Items {
+			if nodes[pod.Spec.NodeName] && pod.Status.Phase == corev1.PodRunning {
+				exporters = append(exporters, pod)
+			}
+		}
+		if len(exporters) > 0 {
+			break
+		}
+	}
+	if len(exporters) == 0 {
+		return nil, errNoExporter
+	}
+
+	usage := gpuUsage{}
+	for _, exporter := range exporters {
+		data, err := kube.CoreV1().Pods(exporter.Namespace).ProxyGet("http", exporter.Name, exporterPort(&exporter), "/metrics", nil).DoRaw(ctx)
+		if err != nil {
+			fmt.Fprintf(warnings, "Warning: failed to scrape DCGM exporter %s/%s: %v\n", exporter.Namespace, exporter.Name, err)
+			continue
+		}
+		if err := usage.parse(data); err != nil {
+			fmt.Fprintf(warnings, "Warning: invalid metrics from DCGM exporter %s/%s: %v\n", exporter.Namespace, exporter.Name, err)
+		}
+	}
+	return usage, nil
+}
+
+func exporterPort(pod *corev1.Pod) string {
+	for _, container := range pod.Spec.Containers {
+		for _, port := range container.Ports {
+			if port.ContainerPort != 0 {
+				return strconv.Itoa(int(port.ContainerPort))
+			}
+		}
+	}
+	return strconv.Itoa(dcgmDefaultPort)
+}
+
+// Adds the GPU utilisation and framebuffer samples of pods from a DCGM exporter's metrics. GPUs not assigned to a pod
+// are skipped.
+func (u gpuUsage) parse(data []byte) error {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	for _, name := range []string{dcgmGPUUtil, dcgmFBUsed, dcgmFBFree} {
+		family, ok := families[name]
+		if !ok {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			// Older exporters prefix the pod labels.
+			pod := podKey{firstOf(labels, "namespace", "pod_namespace"), firstOf(labels, "pod", "pod_name")}
+			if pod.name == "" {
+				continue
+			}
+			gpu := firstOf(labels, "UUID", "uuid")
+			if gpu == "" {
+				gpu = labels["Hostname"] + "/" + labels["gpu"]
+			}
+			if u[pod] == nil {
+				u[pod] = map[string]*gpuSample{}
+			}
+			sample := u[pod][gpu]
+			if sample == nil {
+				sample = &gpuSample{}
+				u[pod][gpu] = sample
+			}
+			value := metricValue(metric)
+			switch name {
+			case dcgmGPUUtil:
+				sample.util, sample.hasUtil = value, true
+			case dcgmFBUsed:
+				sample.usedMiB, sample.hasFB = value, true
+			case dcgmFBFree:
+				sample.freeMiB = value
+			}
+		}
+	}
+	return nil
+}
+
+func firstOf(labels map[string]string, names ...string) string {
+	for _, name := range names {
+		if value := labels[name]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// DCGM declares its fields as gauges; some deployments relabel them as untyped.
+func metricValue(metric *dto.Metric) float64 {
+	if metric.GetGauge() != nil {
+		return metric.GetGauge().GetValue()
+	}
+	if metric.GetCounter() != nil {
+		return metric.GetCounter().GetValue()
+	}
+	return metric.GetUntyped().GetValue()
+}
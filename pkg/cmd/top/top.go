@@ -0,0 +1,297 @@
+package top
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	"k8s-nim-operator-cli/pkg/util"
+	"k8s-nim-operator-cli/pkg/util/client"
+)
+
+const gpuResource = corev1.ResourceName("nvidia.com/gpu")
+
+type TopOptions struct {
+	*util.FetchResourceOptions
+	// Lists pod metrics from the metrics.k8s.io API. Set in tests, since the fake clientset serves no metrics API.
+	podMetrics func(ctx context.Context, namespace string) ([]podMetrics, error)
+}
+
+func NewTopCommand(cmdFactory cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	options := &TopOptions{FetchResourceOptions: util.NewFetchResourceOptions(cmdFactory, streams)}
+
+	cmd := &cobra.Command{
+		Use:   "top RESOURCE [NAME]",
+		Short: "Show the CPU, memory and GPU usage of NIMServices or NIMCaches",
+		Long: `Show what each NIMService or NIMCache consumes, summed over its running pods: CPU and memory from the metrics.k8s.io
+API (metrics-server), and GPU utilisation and framebuffer memory from the DCGM exporter on the pods' nodes, next to the
+nvidia.com/gpu limit of the pods.
+
+GPU utilisation is the average over the GPUs assigned to the pods; GPU memory is used over total. Columns show "-" when
+the metrics API or the DCGM exporter is not available; GPU metrics need the exporter's Kubernetes pod mapping, which the
+GPU Operator enables by default.`,
+		Example: `  nim top nimservice -n nim
+  nim top nimservice llama3 -n nim
+  nim top nimcache -A`,
+		SilenceUsage: true,
+		Args:         cobra.MaximumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				cmd.HelpFunc()(cmd, args)
+				return nil
+			}
+			switch util.ResourceType(strings.TrimSuffix(strings.ToLower(args[0]), "s")) {
+			case util.NIMService:
+				options.ResourceType = util.NIMService
+			case util.NIMCache:
+				options.ResourceType = util.NIMCache
+			default:
+				return fmt.Errorf("invalid resource type %q. Valid types are: nimservice, nimcache", args[0])
+			}
+			if err := options.CompleteNamespace(args[1:], cmd); err != nil {
+				return err
+			}
+			k8sClient, err := client.NewClient(cmdFactory)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			return Run(cmd.Context(), options, k8sClient)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&options.AllNamespaces, "all-namespaces", "A", false, "If present, show the requested objects across all namespaces.")
+
+	return cmd
+}
+
+// A NIMService or NIMCache and the usage of its pods.
+type usage struct {
+	resourceType    util.ResourceType
+	namespace, name string
+	// Name of the LeaderWorkerSet of a multi-node NIMService, whose pods carry its label.
+	lwsName string
+
+	pods        []*corev1.Pod
+	cpu, memory resource.Quantity
+	gpuLimit    int64
+	gpus        map[string]*gpuSample
+}
+
+func (u *usage) owns(pod *corev1.Pod) bool {
+	if pod.Namespace != u.namespace {
+		return false
+	}
+	if u.lwsName != "" && pod.Labels[util.LWSNameLabel] == u.lwsName {
+		return true
+	}
+	resourceType, name := util.PodOwner(pod)
+	return resourceType == u.resourceType && name == u.name
+}
+
+func Run(ctx context.Context, options *TopOptions, k8sClient client.Client) error {
+	usages, err := fetchUsages(ctx, options, k8sClient)
+	if err != nil {
+		return err
+	}
+	if len(usages) == 0 {
+		if options.AllNamespaces {
+			return fmt.Errorf("no %ss found in any namespace", options.ResourceType)
+		}
+		return fmt.Errorf("no %ss found in namespace %s", options.ResourceType, options.Namespace)
+	}
+
+	namespace := options.Namespace
+	if options.AllNamespaces {
+		namespace = ""
+	}
+	kube := k8sClient.KubernetesClient()
+	pods, err := kube.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list pods: %w", err)
+	}
+	nodes := map[string]bool{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		for _, u := range usages {
+			if u.owns(pod) {
+				u.pods = append(u.pods, pod)
+				u.gpuLimit += gpuLimit(pod)
+				if pod.Spec.NodeName != "" {
+					nodes[pod.Spec.NodeName] = true
+				}
+				break
+			}
+		}
+	}
+
+	warnings := options.IoStreams.ErrOut
+	podMetricsFunc := options.podMetrics
+	if podMetricsFunc == nil {
+		podMetricsFunc = metricsAPI(kube)
+	}
+	metrics, err := podMetricsFunc(ctx, namespace)
+	hasMetrics := err == nil
+	if err != nil {
+		fmt.Fprintf(warnings, "Warning: CPU and memory unavailable, the metrics API did not answer (is metrics-server installed?): %v\n", err)
+	}
+	metricsByPod := map[podKey]*podMetrics{}
+	for i := range metrics {
+		metricsByPod[podKey{metrics[i].Namespace, metrics[i].Name}] = &metrics[i]
+	}
+
+	var gpus gpuUsage
+	if len(nodes) > 0 {
+		gpus, err = scrapeDCGM(ctx, kube, nodes, warnings)
+		if errors.Is(err, errNoExporter) {
+			fmt.Fprintf(warnings, "GPU utilisation and memory unavailable: %v.\n", err)
+		} else if err != nil {
+			fmt.Fprintf(warnings, "Warning: GPU utilisation and memory unavailable: %v\n", err)
+		}
+	}
+
+	for _, u := range usages {
+		u.gpus = map[string]*gpuSample{}
+		for _, pod := range u.pods {
+			key := podKey{pod.Namespace, pod.Name}
+			if m := metricsByPod[key]; m != nil {
+				for _, container := range m.Containers {
+					u.cpu.Add(container.Usage[corev1.ResourceCPU])
+					u.memory.Add(container.Usage[corev1.ResourceMemory])
+				}
+			}
+			for uuid, sample := range gpus[key] {
+				u.gpus[uuid] = sample
+			}
+		}
+	}
+
+	return printUsages(usages, options.ResourceType, hasMetrics, gpus != nil, options.AllNamespaces, options.IoStreams.Out)
+}
+
+// Lists the requested NIMServices or NIMCaches. The name is matched here as well, since FetchResources' field selector
+// is not applied by every client.
+func fetchUsages(ctx context.Context, options *TopOptions, k8sClient client.Client) ([]*usage, error) {
+	resourceList, err := util.FetchResources(ctx, options.FetchResourceOptions, k8sClient)
+	if err != nil {
+		return nil, err
+	}
+	var usages []*usage
+	switch list := resourceList.(type) {
+	case *appsv1alpha1.NIMServiceList:
+		for i := range list.Items {
+			nimService := &list.Items[i]
+			u := &usage{resourceType: util.NIMService, namespace: nimService.Namespace, name: nimService.Name}
+			if util.IsMultiNode(nimService) {
+				u.lwsName = nimService.GetLWSName()
+			}
+			usages = append(usages, u)
+		}
+	case *appsv1alpha1.NIMCacheList:
+		for i := range list.Items {
+			usages = append(usages, &usage{resourceType: util.NIMCache, namespace: list.Items[i].Namespace, name: list.Items[i].Name})
+		}
+	}
+
+	filtered := usages[:0]
+	for _, u := range usages {
+		if options.ResourceName == "" || u.name == options.ResourceName {
+			filtered = append(filtered, u)
+		}
+	}
+	sort.Slice(filtered, func(i, j int) bool {
+		if filtered[i].namespace != filtered[j].namespace {
+			return filtered[i].namespace < filtered[j].namespace
+		}
+		return filtered[i].name < filtered[j].name
+	})
+	return filtered, nil
+}
+
+// The pod's nvidia.com/gpu limit, summed over its containers. Extended resources need limits, so requests are only a
+// fallback.
+func gpuLimit(pod *corev1.Pod) int64 {
+	var total int64
+	for _, container := range pod.Spec.Containers {
+		limit, ok := container.Resources.Limits[gpuResource]
+		if !ok {
+			limit = container.Resources.Requests[gpuResource]
+		}
+		total += limit.Value()
+	}
+	return total
+}
+
+func printUsages(usages []*usage, resourceType util.ResourceType, hasMetrics, hasGPUMetrics, allNamespaces bool, output io.Writer) error {
+	columns := []metav1.TableColumnDefinition{
+		{Name: string(resourceType), Type: "string"},
+		{Name: "Pods", Type: "string"},
+		{Name: "CPU(cores)", Type: "string"},
+		{Name: "Memory(bytes)", Type: "string"},
+		{Name: "GPU Limit", Type: "string"},
+		{Name: "GPU Util", Type: "string"},
+		{Name: "GPU Memory", Type: "string"},
+	}
+	if allNamespaces {
+		columns = append([]metav1.TableColumnDefinition{{Name: "Namespace", Type: "string"}}, columns...)
+	}
+	table := &metav1.Table{ColumnDefinitions: columns}
+
+	for _, u := range usages {
+		cpu, memory := "-", "-"
+		if hasMetrics && len(u.pods) > 0 {
+			cpu = fmt.Sprintf("%dm", u.cpu.MilliValue())
+			memory = fmt.Sprintf("%dMi", u.memory.Value()/(1024*1024))
+		}
+		gpuUtil, gpuMemory := "-", "-"
+		if hasGPUMetrics {
+			gpuUtil, gpuMemory = formatGPUs(u.gpus)
+		}
+		cells := []interface{}{u.name, fmt.Sprintf("%d", len(u.pods)), cpu, memory, fmt.Sprintf("%d", u.gpuLimit), gpuUtil, gpuMemory}
+		if allNamespaces {
+			cells = append([]interface{}{u.namespace}, cells...)
+		}
+		table.Rows = append(table.Rows, metav1.TableRow{Cells: cells})
+	}
+
+	return printers.NewTablePrinter(printers.PrintOptions{}).PrintObj(table, output)
+}
+
+// Formats the average utilisation and the used and total framebuffer memory of the GPUs.
+func formatGPUs(gpus map[string]*gpuSample) (string, string) {
+	var utilSum, used, total float64
+	var withUtil, withFB int
+	for _, gpu := range gpus {
+		if gpu.hasUtil {
+			utilSum += gpu.util
+			withUtil++
+		}
+		if gpu.hasFB {
+			used += gpu.usedMiB
+			total += gpu.usedMiB + gpu.freeMiB
+			withFB++
+		}
+	}
+	util, memory := "-", "-"
+	if withUtil > 0 {
+		util = fmt.Sprintf("%.0f%%", utilSum/float64(withUtil))
+	}
+	if withFB > 0 {
+		memory = fmt.Sprintf("%.1fGi/%.1fGi", used/1024, total/1024)
+	}
+	return util, memory
+}
@@ -0,0 +1,248 @@
+package top
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	restclient "k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+
+	"k8s-nim-operator-cli/pkg/util"
+	"k8s-nim-operator-cli/pkg/util/client/fake"
+)
+
+type rawResponse []byte
+
+func (r rawResponse) DoRaw(context.Context) ([]byte, error) { return r, nil }
+func (r rawResponse) Stream(context.Context) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(r)), nil
+}
+
+const dcgmMetrics = `# HELP DCGM_FI_DEV_GPU_UTIL GPU utilization (in %).
+# TYPE DCGM_FI_DEV_GPU_UTIL gauge
+DCGM_FI_DEV_GPU_UTIL{gpu="0",UUID="GPU-a",Hostname="gpu-1",namespace="nim",pod="llama3-0",container="nim"} 80
+DCGM_FI_DEV_GPU_UTIL{gpu="1",UUID="GPU-b",Hostname="gpu-1",namespace="nim",pod="llama3-0",container="nim"} 60
+DCGM_FI_DEV_GPU_UTIL{gpu="2",UUID="GPU-c",Hostname="gpu-1"} 0
+# HELP DCGM_FI_DEV_FB_USED Framebuffer memory used (in MiB).
+# TYPE DCGM_FI_DEV_FB_USED gauge
+DCGM_FI_DEV_FB_USED{gpu="0",UUID="GPU-a",Hostname="gpu-1",namespace="nim",pod="llama3-0",container="nim"} 40960
+DCGM_FI_DEV_FB_USED{gpu="1",UUID="GPU-b",Hostname="gpu-1",namespace="nim",pod="llama3-0",container="nim"} 20480
+# HELP DCGM_FI_DEV_FB_FREE Framebuffer memory free (in MiB).
+# TYPE DCGM_FI_DEV_FB_FREE gauge
+DCGM_FI_DEV_FB_FREE{gpu="0",UUID="GPU-a",Hostname="gpu-1",namespace="nim",pod="llama3-0",container="nim"} 40960
+DCGM_FI_DEV_FB_FREE{gpu="1",UUID="GPU-b",Hostname="gpu-1",namespace="nim",pod="llama3-0",container="nim"} 61440
+`
+
+func nimPod(namespace, name, instance, node string, gpus int64) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{util.InstanceLabel: instance}},
+		Spec: corev1.PodSpec{
+			NodeName:   node,
+			Containers: []corev1.Container{{Name: "nim"}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	if gpus > 0 {
+		pod.Spec.Containers[0].Resources.Limits = corev1.ResourceList{gpuResource: *resource.NewQuantity(gpus, resource.DecimalSI)}
+	}
+	return pod
+}
+
+func exporterPod() *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "dcgm-exporter-x", Namespace: "gpu-operator", Labels: map[string]string{"app": "nvidia-dcgm-exporter"}},
+		Spec: corev1.PodSpec{
+			NodeName:   "gpu-1",
+			Containers: []corev1.Container{{Name: "exporter", Ports: []corev1.ContainerPort{{ContainerPort: 9400}}}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func metricsFor(namespace, name, cpu, memory string) podMetrics {
+	m := podMetrics{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	m.Containers = append(m.Containers, struct {
+		Name  string              `json:"name"`
+		Usage corev1.ResourceList `json:"usage"`
+	}{Name: "nim", Usage: corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(cpu),
+		corev1.ResourceMemory: resource.MustParse(memory),
+	}})
+	return m
+}
+
+func newTestOptions(resourceType util.ResourceType, name string, out, errOut *bytes.Buffer) *TopOptions {
+	streams := genericclioptions.IOStreams{In: &bytes.Buffer{}, Out: out, ErrOut: errOut}
+	options := &TopOptions{FetchResourceOptions: util.NewFetchResourceOptions(nil, streams)}
+	options.Namespace = "nim"
+	options.ResourceType = resourceType
+	options.ResourceName = name
+	options.podMetrics = func(ctx context.Context, namespace string) ([]podMetrics, error) {
+		all := []podMetrics{
+			metricsFor("nim", "llama3-0", "1500m", "8Gi"),
+			metricsFor("nim", "llama3-1", "500m", "2Gi"),
+			metricsFor("other", "mistral-0", "250m", "1Gi"),
+			metricsFor("nim", "llama3-cache-job-x", "200m", "512Mi"),
+		}
+		var metrics []podMetrics
+		for _, m := range all {
+			if namespace == "" || m.Namespace == namespace {
+				metrics = append(metrics, m)
+			}
+		}
+		return metrics, nil
+	}
+	return options
+}
+
+func newTestClient(objects ...*corev1.Pod) *fake.Client {
+	client := fake.NewFakeClient(nil, []runtime.Object{
+		&appsv1alpha1.NIMService{ObjectMeta: metav1.ObjectMeta{Name: "llama3", Namespace: "nim"}},
+		&appsv1alpha1.NIMService{ObjectMeta: metav1.ObjectMeta{Name: "idle", Namespace: "nim"}},
+		&appsv1alpha1.NIMService{ObjectMeta: metav1.ObjectMeta{Name: "mistral", Namespace: "other"}},
+		&appsv1alpha1.NIMCache{ObjectMeta: metav1.ObjectMeta{Name: "llama3-cache", Namespace: "nim"}},
+	})
+	for _, pod := range objects {
+		_ = client.Kube.Tracker().Add(pod)
+	}
+	client.Kube.PrependProxyReactor("pods", func(action k8stesting.Action) (bool, restclient.ResponseWrapper, error) {
+		proxy := action.(k8stesting.ProxyGetAction)
+		if proxy.GetName() != "dcgm-exporter-x" || proxy.GetPort() != "9400" || proxy.GetPath() != "/metrics" {
+			return true, nil, errors.New("unexpected proxy request")
+		}
+		return true, rawResponse(dcgmMetrics), nil
+	})
+	return client
+}
+
+func nimPods() []*corev1.Pod {
+	done := nimPod("nim", "llama3-old", "llama3", "gpu-1", 2)
+	done.Status.Phase = corev1.PodFailed
+	return []*corev1.Pod{
+		nimPod("nim", "llama3-0", "llama3", "gpu-1", 2),
+		nimPod("nim", "llama3-1", "llama3", "gpu-2", 1),
+		done,
+		nimPod("other", "mistral-0", "mistral", "gpu-2", 1),
+	}
+}
+
+func TestRunNIMService(t *testing.T) {
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	k8sClient := newTestClient(append(nimPods(), exporterPod())...)
+
+	if err := Run(context.Background(), newTestOptions(util.NIMService, "", out, errOut), k8sClient); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	want := `NIMSERVICE   PODS   CPU(CORES)   MEMORY(BYTES)   GPU LIMIT   GPU UTIL   GPU MEMORY
+idle         0      -            -               0           -          -
+llama3       2      2000m        10240Mi         3           70%        60.0Gi/160.0Gi
+`
+	if out.String() != want {
+		t.Errorf("output:\n%s\nwant:\n%s", out.String(), want)
+	}
+	if errOut.Len() != 0 {
+		t.Errorf("unexpected warnings: %s", errOut.String())
+	}
+}
+
+func TestRunNIMCache(t *testing.T) {
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	// The operator labels caching job pods with the NIMCache's name only.
+	job := nimPod("nim", "llama3-cache-job-x", "", "gpu-1", 1)
+	job.Labels = map[string]string{util.NameLabel: "llama3-cache"}
+	k8sClient := newTestClient(append(nimPods(), job, exporterPod())...)
+
+	if err := Run(context.Background(), newTestOptions(util.NIMCache, "", out, errOut), k8sClient); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	want := `NIMCACHE       PODS   CPU(CORES)   MEMORY(BYTES)   GPU LIMIT   GPU UTIL   GPU MEMORY
+llama3-cache   1      200m         512Mi           1           -          -
+`
+	if out.String() != want {
+		t.Errorf("output:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestRunByName(t *testing.T) {
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	k8sClient := newTestClient(append(nimPods(), exporterPod())...)
+
+	if err := Run(context.Background(), newTestOptions(util.NIMService, "llama3", out, errOut), k8sClient); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if strings.Contains(out.String(), "idle") || !strings.Contains(out.String(), "llama3") {
+		t.Errorf("expected only llama3, got:\n%s", out.String())
+	}
+
+	err := Run(context.Background(), newTestOptions(util.NIMService, "missing", out, errOut), k8sClient)
+	if err == nil || !strings.Contains(err.Error(), "no nimservices found in namespace nim") {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
+
+func TestRunAllNamespaces(t *testing.T) {
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	k8sClient := newTestClient(append(nimPods(), exporterPod())...)
+	options := newTestOptions(util.NIMService, "", out, errOut)
+	options.AllNamespaces = true
+
+	if err := Run(context.Background(), options, k8sClient); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	want := `NAMESPACE   NIMSERVICE   PODS   CPU(CORES)   MEMORY(BYTES)   GPU LIMIT   GPU UTIL   GPU MEMORY
+nim         idle         0      -            -               0           -          -
+nim         llama3       2      2000m        10240Mi         3           70%        60.0Gi/160.0Gi
+other       mistral      1      250m         1024Mi          1           -          -
+`
+	if out.String() != want {
+		t.Errorf("output:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestRunWithoutExporterOrMetricsAPI(t *testing.T) {
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	k8sClient := newTestClient(nimPods()...)
+	options := newTestOptions(util.NIMService, "llama3", out, errOut)
+	options.podMetrics = func(context.Context, string) ([]podMetrics, error) {
+		return nil, errors.New("the server could not find the requested resource")
+	}
+
+	if err := Run(context.Background(), options, k8sClient); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	want := `NIMSERVICE   PODS   CPU(CORES)   MEMORY(BYTES)   GPU LIMIT   GPU UTIL   GPU MEMORY
+llama3       2      -            -               3           -          -
+`
+	if out.String() != want {
+		t.Errorf("output:\n%s\nwant:\n%s", out.String(), want)
+	}
+	for _, warning := range []string{"the metrics API did not answer", "no DCGM exporter found"} {
+		if !strings.Contains(errOut.String(), warning) {
+			t.Errorf("expected warning %q, got: %s", warning, errOut.String())
+		}
+	}
+}
+
+func TestParseDCGM(t *testing.T) {
+	usage := gpuUsage{}
+	if err := usage.parse([]byte(dcgmMetrics)); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(usage) != 1 {
+		t.Fatalf("expected samples for one pod, got %v", usage)
+	}
+	gpus := usage[podKey{"nim", "llama3-0"}]
+	if len(gpus) != 2 || gpus["GPU-a"].util != 80 || gpus["GPU-b"].freeMiB != 61440 {
+		t.Errorf("unexpected samples: %+v", gpus)
+	}
+}
@@ -0,0 +1,91 @@
+package validate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	"k8s-nim-operator-cli/pkg/util"
+	"k8s-nim-operator-cli/pkg/util/validation"
+)
+
+type ValidateOptions struct {
+	IoStreams *genericclioptions.IOStreams
+	Filenames []string
+}
+
+func NewValidateCommand(cmdFactory cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	options := &ValidateOptions{IoStreams: &streams}
+
+	cmd := &cobra.Command{
+		Use:   "validate -f FILENAME",
+		Short: "Validate NIMService and NIMCache manifests without a cluster",
+		Long: `Check NIMService and NIMCache manifests against the rules of the NIM Operator's admission webhooks and CRD schema,
+and the rules the operator only enforces when it reconciles, and report every problem with its field path.
+
+Files may hold several YAML documents. A directory contributes its .yaml, .yml and .json files, and "-" reads from
+standard input. Unknown fields are reported, since the API server would drop them. Objects of other kinds are listed as
+skipped. The command needs no cluster and exits with an error if any object is invalid.`,
+		Example: `  nim validate -f llama3-nimservice.yaml
+  nim validate -f deploy/
+  kubectl get nimservice llama3-nimservice -o yaml | nim validate -f -`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				return fmt.Errorf("unknown argument(s) %q; pass manifests with -f", strings.Join(args, " "))
+			}
+			if len(options.Filenames) == 0 {
+				cmd.HelpFunc()(cmd, args)
+				return nil
+			}
+			return Run(options)
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&options.Filenames, "filename", "f", nil, "File or directory of manifests to validate, or - for standard input. Can be repeated.")
+
+	return cmd
+}
+
+func Run(options *ValidateOptions) error {
+	objects, err := util.ReadObjects(options.Filenames, options.IoStreams.In)
+	if err != nil {
+		return err
+	}
+	if len(objects) == 0 {
+		return fmt.Errorf("no objects found in %s", strings.Join(options.Filenames, ", "))
+	}
+
+	out := options.IoStreams.Out
+	checked, invalid := 0, 0
+	for _, object := range objects {
+		obj := object.Object
+		name := fmt.Sprintf("%s/%s", obj.GetKind(), obj.GetName())
+		errs, supported, err := validation.ValidateObject(obj)
+		if !supported {
+			fmt.Fprintf(out, "%s: %s: skipped, only NIMService and NIMCache are validated\n", object.Source, name)
+			continue
+		}
+		checked++
+		if err == nil && len(errs) == 0 {
+			fmt.Fprintf(out, "%s: %s: valid\n", object.Source, name)
+			continue
+		}
+		invalid++
+		fmt.Fprintf(out, "%s: %s: invalid\n", object.Source, name)
+		if err != nil {
+			fmt.Fprintf(out, "  * %s\n", err.Error())
+		}
+		for _, err := range errs {
+			fmt.Fprintf(out, "  * %s\n", err.Error())
+		}
+	}
+
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d objects are invalid", invalid, checked)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package validate
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+const validNIMService = `apiVersion: apps.nvidia.com/v1alpha1
+kind: NIMService
+metadata:
+  name: llama3
+spec:
+  image:
+    repository: nvcr.io/nim/meta/llama3-8b-instruct
+    tag: 1.0.3
+  authSecret: ngc-api-secret
+  storage:
+    nimCache:
+      name: llama3-cache
+  expose:
+    service:
+      port: 8000
+`
+
+const invalidNIMCache = `apiVersion: apps.nvidia.com/v1alpha1
+kind: NIMCache
+metadata:
+  name: llama3-cache
+spec:
+  source:
+    ngc:
+      authSecret: ngc-api-secret
+      model:
+        profiles: [all, trt-fp8-tp1]
+  storage:
+    pvc:
+      create: true
+      volumeAccessMode: ReadWriteMany
+`
+
+const configMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+`
+
+func newTestOptions(in string, filenames ...string) (*ValidateOptions, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	streams := genericclioptions.IOStreams{In: strings.NewReader(in), Out: out, ErrOut: &bytes.Buffer{}}
+	return &ValidateOptions{IoStreams: &streams, Filenames: filenames}, out
+}
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func Test_Run_Directory(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a-nimservice.yaml", validNIMService+"---\n"+configMap)
+	writeFile(t, dir, "b-nimcache.yml", invalidNIMCache)
+	writeFile(t, dir, "README.md", "not a manifest")
+
+	options, out := newTestOptions("", dir)
+	err := Run(options)
+	if err == nil || err.Error() != "1 of 2 objects are invalid" {
+		t.Fatalf("expected one invalid object, got %v", err)
+	}
+
+	got := out.String()
+	for _, want := range []string{
+		"a-nimservice.yaml: NIMService/llama3: valid",
+		"a-nimservice.yaml: ConfigMap/settings: skipped",
+		"b-nimcache.yml: NIMCache/llama3-cache: invalid",
+		// Every error of the object is reported, not only the first.
+		"spec.source.ngc.modelPuller: Required value",
+		"spec.source.ngc.model.profiles",
+		"spec.storage.pvc.size: Required value",
+		"spec.storage.pvc.storageClass: Required value",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "README") {
+		t.Errorf("non-manifest files should be ignored:\n%s", got)
+	}
+}
+
+func Test_Run_Stdin(t *testing.T) {
+	options, out := newTestOptions(validNIMService, "-")
+	if err := Run(options); err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	if !strings.Contains(out.String(), "<stdin>: NIMService/llama3: valid") {
+		t.Fatalf("unexpected output: %s", out.String())
+	}
+}
+
+func Test_Run_InvalidNIMService(t *testing.T) {
+	cases := map[string]struct {
+		from, to string
+		want     string
+	}{
+		"unknown field":       {"  authSecret:", "  authSecrets:", `unknown field "spec.authSecrets"`},
+		"api version":         {"apps.nvidia.com/v1alpha1", "apps.nvidia.com/v1beta1", "apiVersion: Unsupported value"},
+		"two storage sources": {"      name: llama3-cache\n", "      name: llama3-cache\n    hostPath: /mnt/models\n", "only one of nimCache, pvc or hostPath"},
+		"service type":        {"      port: 8000\n", "      port: 8000\n      type: Internal\n", "spec.expose.service.type: Unsupported value"},
+		"min above max": {"  authSecret: ngc-api-secret\n", "  authSecret: ngc-api-secret\n  scale:\n    enabled: true\n    hpa:\n      minReplicas: 4\n      maxReplicas: 2\n",
+			"spec.scale.hpa.minReplicas: Invalid value: 4: must not be greater than maxReplicas (2)"},
+		"gpu request above limit": {"  authSecret: ngc-api-secret\n", "  authSecret: ngc-api-secret\n  resources:\n    limits:\n      nvidia.com/gpu: 1\n    requests:\n      nvidia.com/gpu: 2\n",
+			"spec.resources.requests[nvidia.com/gpu]"},
+		"multi-node with autoscaling": {"  authSecret: ngc-api-secret\n", "  authSecret: ngc-api-secret\n  multiNode:\n    backendType: lws\n    size: 2\n  scale:\n    enabled: true\n    hpa:\n      maxReplicas: 2\n",
+			"spec.multiNode: Forbidden"},
+		"name": {"name: llama3", "name: Llama_3", "metadata.name: Invalid value"},
+	}
+	for name, tc := range cases {
+		options, out := newTestOptions(strings.Replace(validNIMService, tc.from, tc.to, 1), "-")
+		if err := Run(options); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+		if !strings.Contains(out.String(), tc.want) {
+			t.Errorf("%s: expected %q in output:\n%s", name, tc.want, out.String())
+		}
+	}
+}
+
+func Test_Run_NoObjects(t *testing.T) {
+	options, _ := newTestOptions("---\n", "-")
+	if err := Run(options); err == nil {
+		t.Fatalf("expected error for empty input")
+	}
+	options, _ = newTestOptions("", filepath.Join(t.TempDir(), "missing.yaml"))
+	if err := Run(options); err == nil {
+		t.Fatalf("expected error for a missing file")
+	}
+}
@@ -0,0 +1,162 @@
+package util
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Metadata the API server or the operator sets, which would tie the object to its cluster.
+var serverMetadata = []string{
+	"uid",
+	"resourceVersion",
+	"generation",
+	"creationTimestamp",
+	"deletionTimestamp",
+	"deletionGracePeriodSeconds",
+	"managedFields",
+	"selfLink",
+	"ownerReferences",
+}
+
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// A field the CRD schema defaults, with the default value. The field is dropped when it holds the default, so the
+// export only shows what was chosen. Paths with a "*" element apply to every item of the list at that point.
+type defaultedField struct {
+	path  []string
+	value interface{}
+}
+
+var nimServiceDefaults = []defaultedField{
+	{[]string{"spec", "replicas"}, int64(1)},
+	{[]string{"spec", "inferencePlatform"}, "standalone"},
+	{[]string{"spec", "expose", "service", "port"}, int64(8000)},
+	{[]string{"spec", "expose", "httpRoute", "spec", "paths", "*", "type"}, "PathPrefix"},
+	{[]string{"spec", "expose", "httpRoute", "spec", "paths", "*", "value"}, "/"},
+	{[]string{"spec", "multiNode", "backendType"}, "lws"},
+	{[]string{"spec", "multiNode", "size"}, int64(1)},
+	{[]string{"spec", "multiNode", "gpusPerPod"}, int64(1)},
+	{[]string{"spec", "multiNode", "mpi", "mpiStartTimeout"}, int64(300)},
+	// Not a CRD default: the typed HPA spec serializes maxReplicas even when autoscaling was never configured. It must
+	// be at least 1, so 0 is never chosen.
+	{[]string{"spec", "scale", "hpa", "maxReplicas"}, int64(0)},
+}
+
+var nimCacheDefaults = []defaultedField{
+	{[]string{"spec", "source", "dataStore", "namespace"}, "default"},
+	// Not CRD defaults: the typed resources serialize zero quantities when unset, and the operator ignores them.
+	{[]string{"spec", "resources", "cpu"}, "0"},
+	{[]string{"spec", "resources", "memory"}, "0"},
+}
+
+// The defaulted fields of each kind. Other kinds, such as the secrets and claims nim backup saves, keep every field.
+var kindDefaults = map[string][]defaultedField{
+	"NIMService": nimServiceDefaults,
+	"NIMCache":   nimCacheDefaults,
+}
+
+// Clean strips what ties an exported object to the cluster it came from: status, server-set metadata, the
+// operator's finalizers, kubectl's last-applied annotation, the Route URL nim create records and, for NIMServices and
+// NIMCaches, fields holding their CRD default. Empty maps and lists left over from zero-valued structs are dropped.
+// The namespace is kept only when keepNamespace is set.
+func Clean(obj *unstructured.Unstructured, keepNamespace bool) {
+	unstructured.RemoveNestedField(obj.Object, "status")
+	for _, field := range serverMetadata {
+		unstructured.RemoveNestedField(obj.Object, "metadata", field)
+	}
+	if !keepNamespace {
+		unstructured.RemoveNestedField(obj.Object, "metadata", "namespace")
+	}
+
+	var finalizers []string
+	for _, finalizer := range obj.GetFinalizers() {
+		if !isOperatorFinalizer(finalizer) {
+			finalizers = append(finalizers, finalizer)
+		}
+	}
+	obj.SetFinalizers(finalizers)
+
+	annotations := obj.GetAnnotations()
+	delete(annotations, lastAppliedAnnotation)
+	delete(annotations, RouteURLAnnotation)
+	obj.SetAnnotations(annotations)
+
+	for _, field := range kindDefaults[obj.GetKind()] {
+		removeDefault(obj.Object, field.path, field.value)
+	}
+
+	pruneEmpty(obj.Object)
+}
+
+func isOperatorFinalizer(finalizer string) bool {
+	return strings.HasPrefix(finalizer, "finalizer.") && strings.HasSuffix(finalizer, ".apps.nvidia.com")
+}
+
+func removeDefault(obj map[string]interface{}, path []string, value interface{}) {
+	for i, element := range path {
+		if element == "*" {
+			items, _ := obj[path[i-1]].([]interface{})
+			for _, item := range items {
+				if m, ok := item.(map[string]interface{}); ok {
+					removeDefault(m, path[i+1:], value)
+				}
+			}
+			return
+		}
+		if i == len(path)-1 {
+			if current, ok := obj[element]; ok && equalValue(current, value) {
+				delete(obj, element)
+			}
+			return
+		}
+		// Look one element ahead, so a list is left in place for the "*" step above.
+		if path[i+1] == "*" {
+			continue
+		}
+		next, ok := obj[element].(map[string]interface{})
+		if !ok {
+			return
+		}
+		obj = next
+	}
+}
+
+// Compares scalars from runtime.DefaultUnstructuredConverter, which produces int64 for integers.
+func equalValue(current, value interface{}) bool {
+	switch v := current.(type) {
+	case int64:
+		want, ok := value.(int64)
+		return ok && v == want
+	case float64:
+		want, ok := value.(int64)
+		return ok && v == float64(want)
+	default:
+		return current == value
+	}
+}
+
+// Drops nil values and empty maps and lists from maps, recursively. Empty list items are kept, since their position
+// matters.
+func pruneEmpty(obj map[string]interface{}) {
+	for key, value := range obj {
+		switch v := value.(type) {
+		case nil:
+			delete(obj, key)
+		case map[string]interface{}:
+			pruneEmpty(v)
+			if len(v) == 0 {
+				delete(obj, key)
+			}
+		case []interface{}:
+			for _, item := range v {
+				if m, ok := item.(map[string]interface{}); ok {
+					pruneEmpty(m)
+				}
+			}
+			if len(v) == 0 {
+				delete(obj, key)
+			}
+		}
+	}
+}
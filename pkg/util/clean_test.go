@@ -0,0 +1,84 @@
+package util
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+func Test_Clean_Defaults(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind":     "NIMService",
+		"metadata": map[string]interface{}{"name": "llama3", "namespace": "nim"},
+		"spec": map[string]interface{}{
+			"replicas": int64(3),
+			"multiNode": map[string]interface{}{
+				"backendType": "lws",
+				"size":        int64(2),
+				"mpi":         map[string]interface{}{"mpiStartTimeout": int64(300)},
+			},
+			"expose": map[string]interface{}{
+				"httpRoute": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"paths": []interface{}{
+							map[string]interface{}{"type": "PathPrefix", "value": "/v1"},
+							map[string]interface{}{"type": "Exact", "value": "/"},
+						},
+					},
+				},
+			},
+		},
+	}}
+	Clean(obj, true)
+
+	want := map[string]interface{}{
+		"replicas":  int64(3),
+		"multiNode": map[string]interface{}{"size": int64(2)},
+		"expose": map[string]interface{}{
+			"httpRoute": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"paths": []interface{}{
+						map[string]interface{}{"value": "/v1"},
+						map[string]interface{}{"type": "Exact"},
+					},
+				},
+			},
+		},
+	}
+	got, _, _ := unstructured.NestedMap(obj.Object, "spec")
+	gotYAML, _ := yaml.Marshal(got)
+	wantYAML, _ := yaml.Marshal(want)
+	if string(gotYAML) != string(wantYAML) {
+		t.Errorf("unexpected spec:\n%s\nwant:\n%s", gotYAML, wantYAML)
+	}
+	if obj.GetNamespace() != "nim" {
+		t.Errorf("namespace should be kept")
+	}
+}
+
+func Test_Clean_Annotations(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"kind": "NIMService"}}
+	obj.SetAnnotations(map[string]string{
+		"kubectl.kubernetes.io/last-applied-configuration": "{}",
+		RouteURLAnnotation: "https://llama3.apps.example.com",
+		"team":             "llm",
+	})
+	Clean(obj, false)
+
+	if annotations := obj.GetAnnotations(); len(annotations) != 1 || annotations["team"] != "llm" {
+		t.Errorf("expected only the user's annotation to be kept, got %v", annotations)
+	}
+}
+
+func Test_Clean_OtherKinds(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind": "Deployment",
+		"spec": map[string]interface{}{"replicas": int64(1)},
+	}}
+	Clean(obj, false)
+
+	if replicas, _, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas"); replicas != 1 {
+		t.Errorf("only NIMService and NIMCache defaults should be removed, got %v", obj.Object)
+	}
+}
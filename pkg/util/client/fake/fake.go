@@ -0,0 +1,29 @@
+// Package fake provides a client.Client backed by in-memory clientsets, for
+// use in command tests.
+package fake
+
+import (
+	nimclientset "github.com/NVIDIA/k8s-nim-operator/api/versioned"
+	nimfake "github.com/NVIDIA/k8s-nim-operator/api/versioned/fake"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+// Client serves Kubernetes and NIM objects from fake clientsets. The
+// clientsets are exposed so tests can add reactors or inspect what was written.
+type Client struct {
+	Kube *k8sfake.Clientset
+	NIM  *nimfake.Clientset
+}
+
+// NewFakeClient returns a Client seeded with kubeObjs and nimObjs.
+func NewFakeClient(kubeObjs []runtime.Object, nimObjs []runtime.Object) *Client {
+	return &Client{
+		Kube: k8sfake.NewSimpleClientset(kubeObjs...),
+		NIM:  nimfake.NewSimpleClientset(nimObjs...),
+	}
+}
+
+func (c *Client) KubernetesClient() kubernetes.Interface { return c.Kube }
+func (c *Client) NIMClient() nimclientset.Interface      { return c.NIM }
@@ -0,0 +1,525 @@
+// Package config layers user defaults over the CLI's built-in flag defaults.
+//
+// Values are read, lowest precedence first, from the built-in defaults, the user file
+// ($XDG_CONFIG_HOME or ~/.config)/kubectl-nim/config.yaml, the nearest project .nim.yaml at or above the working
+// directory, and NIM_* environment variables. Flags set on the command line always win. Within a file, settings for
+// the current kube context override the file's defaults, and settings for the current namespace override both.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/yaml"
+
+	util "k8s-nim-operator-cli/pkg/util"
+)
+
+const (
+	AuthSecret          = "authSecret"
+	PullSecrets         = "pullSecrets"
+	PVCVolumeAccessMode = "pvcVolumeAccessMode"
+	ServiceType         = "serviceType"
+	GPULimit            = "gpuLimit"
+	StorageClass        = "storageClass"
+	OperatorNamespace   = "operatorNamespace"
+	Output              = "output"
+
+	// ProjectFileName is looked up in the working directory and its parents.
+	ProjectFileName = ".nim.yaml"
+
+	// Flag annotations naming the key a flag takes its default from, and the output formats a command supports.
+	keyAnnotation     = "kubectl-nim/config-key"
+	formatsAnnotation = "kubectl-nim/output-formats"
+)
+
+// Key describes a configurable default.
+type Key struct {
+	Name        string
+	Env         string
+	Default     string
+	Description string
+	validate    func(string) error
+}
+
+// Keys lists every configurable default.
+var Keys = []Key{
+	{AuthSecret, "NIM_AUTH_SECRET", util.AuthSecret, "Secret holding NGC_API_KEY (--auth-secret).", nil},
+	{PullSecrets, "NIM_PULL_SECRETS", strings.Join(util.PullSecrets, ","), "Comma-separated image pull secrets (--pull-secrets, --pull-secret).", nil},
+	{PVCVolumeAccessMode, "NIM_PVC_VOLUME_ACCESS_MODE", util.PVCVolumeAccessMode, "Access mode of new PVCs (--pvc-volume-access-mode).",
+		oneOf(string(corev1.ReadWriteOnce), string(corev1.ReadOnlyMany), string(corev1.ReadWriteMany), string(corev1.ReadWriteOncePod))},
+	{ServiceType, "NIM_SERVICE_TYPE", util.ServiceType, "Type of the NIMService's Service (--service-type).",
+		oneOf(string(corev1.ServiceTypeClusterIP), string(corev1.ServiceTypeNodePort), string(corev1.ServiceTypeLoadBalancer))},
+	{GPULimit, "NIM_GPU_LIMIT", util.GPULimit, "GPUs per NIMService pod (--gpu-limit).", validateGPULimit},
+	{StorageClass, "NIM_STORAGE_CLASS", util.PVCStorageClass, "StorageClass of new PVCs (--pvc-storage-class).", nil},
+	{OperatorNamespace, "NIM_OPERATOR_NAMESPACE", "", "Namespace of the NIM Operator (--operator-namespace).", nil},
+	{Output, "NIM_OUTPUT", "table", "Preferred output format (-o), used by commands that support it.", nil},
+}
+
+// LookupKey returns the key with the given name.
+func LookupKey(name string) (*Key, error) {
+	for i := range Keys {
+		if Keys[i].Name == name {
+			return &Keys[i], nil
+		}
+	}
+	names := make([]string, 0, len(Keys))
+	for _, key := range Keys {
+		names = append(names, key.Name)
+	}
+	return nil, fmt.Errorf("unknown key %q; supported keys: %s", name, strings.Join(names, ", "))
+}
+
+// Validate checks a value for the key. An empty value is always accepted, since it removes the setting.
+func (k *Key) Validate(value string) error {
+	if value == "" || k.validate == nil {
+		return nil
+	}
+	if err := k.validate(value); err != nil {
+		return fmt.Errorf("invalid %s: %w", k.Name, err)
+	}
+	return nil
+}
+
+func oneOf(values ...string) func(string) error {
+	return func(value string) error {
+		for _, v := range values {
+			if value == v {
+				return nil
+			}
+		}
+		return fmt.Errorf("%q, must be one of %s", value, strings.Join(values, ", "))
+	}
+}
+
+func validateGPULimit(value string) error {
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil || quantity.Sign() <= 0 || quantity.MilliValue()%1000 != 0 {
+		return fmt.Errorf("%q, must be a whole number of GPUs", value)
+	}
+	return nil
+}
+
+// Settings maps keys to values.
+type Settings map[string]string
+
+// Scope holds defaults and per-namespace overrides. In a file the defaults are top-level keys next to "namespaces".
+type Scope struct {
+	Defaults   Settings
+	Namespaces map[string]Settings
+}
+
+// File is the content of a config file: a scope with per-context scopes under "contexts", keyed by kube context name.
+type File struct {
+	Scope
+	Contexts map[string]Scope
+}
+
+func (s Scope) fields() map[string]interface{} {
+	fields := map[string]interface{}{}
+	for name, value := range s.Defaults {
+		fields[name] = value
+	}
+	if len(s.Namespaces) > 0 {
+		fields["namespaces"] = s.Namespaces
+	}
+	return fields
+}
+
+func (s Scope) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.fields())
+}
+
+func (s *Scope) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for name, raw := range fields {
+		if name != "namespaces" {
+			if err := setScalar(&s.Defaults, name, raw); err != nil {
+				return err
+			}
+			continue
+		}
+		var namespaces map[string]map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &namespaces); err != nil {
+			return fmt.Errorf("namespaces: %w", err)
+		}
+		s.Namespaces = map[string]Settings{}
+		for namespace, fields := range namespaces {
+			settings := Settings{}
+			for name, raw := range fields {
+				if err := setScalar(&settings, name, raw); err != nil {
+					return fmt.Errorf("namespace %s: %w", namespace, err)
+				}
+			}
+			s.Namespaces[namespace] = settings
+		}
+	}
+	return nil
+}
+
+// Stores a string, number or boolean value as a string, so gpuLimit: 2 works as well as gpuLimit: "2".
+func setScalar(settings *Settings, name string, raw json.RawMessage) error {
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return err
+	}
+	if *settings == nil {
+		*settings = Settings{}
+	}
+	switch v := value.(type) {
+	case string:
+		(*settings)[name] = v
+	case float64, bool:
+		(*settings)[name] = fmt.Sprint(v)
+	default:
+		return fmt.Errorf("%s must be a string", name)
+	}
+	return nil
+}
+
+func (f File) MarshalJSON() ([]byte, error) {
+	fields := f.fields()
+	if len(f.Contexts) > 0 {
+		fields["contexts"] = f.Contexts
+	}
+	return json.Marshal(fields)
+}
+
+func (f *File) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	if raw, ok := fields["contexts"]; ok {
+		if err := json.Unmarshal(raw, &f.Contexts); err != nil {
+			return fmt.Errorf("contexts: %w", err)
+		}
+		delete(fields, "contexts")
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return f.Scope.UnmarshalJSON(data)
+}
+
+// ReadFile reads a config file. A missing file is empty.
+func ReadFile(path string) (*File, error) {
+	file := &File{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return file, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, file); err != nil {
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+	for _, settings := range file.allSettings() {
+		for name := range settings {
+			if _, err := LookupKey(name); err != nil {
+				return nil, fmt.Errorf("config %s: %w", path, err)
+			}
+		}
+	}
+	return file, nil
+}
+
+func (f *File) allSettings() []Settings {
+	all := []Settings{f.Defaults}
+	for _, settings := range f.Namespaces {
+		all = append(all, settings)
+	}
+	for _, scope := range f.Contexts {
+		all = append(all, scope.Defaults)
+		for _, settings := range scope.Namespaces {
+			all = append(all, settings)
+		}
+	}
+	return all
+}
+
+// WriteFile writes the config file, creating its directory.
+func (f *File) WriteFile(path string) error {
+	data, err := yaml.Marshal(f)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Settings returns the settings of the scope named by context and namespace, either of which may be empty, creating
+// them when create is set.
+func (f *File) Settings(context, namespace string, create bool) Settings {
+	scope := &f.Scope
+	if context != "" {
+		if f.Contexts == nil {
+			if !create {
+				return nil
+			}
+			f.Contexts = map[string]Scope{}
+		}
+		s, ok := f.Contexts[context]
+		if !ok && !create {
+			return nil
+		}
+		defer func() { f.Contexts[context] = s }()
+		scope = &s
+	}
+	if namespace == "" {
+		if scope.Defaults == nil && create {
+			scope.Defaults = Settings{}
+		}
+		return scope.Defaults
+	}
+	if scope.Namespaces == nil {
+		if !create {
+			return nil
+		}
+		scope.Namespaces = map[string]Settings{}
+	}
+	if _, ok := scope.Namespaces[namespace]; !ok && create {
+		scope.Namespaces[namespace] = Settings{}
+	}
+	return scope.Namespaces[namespace]
+}
+
+// Prune drops empty scopes, so removing the last setting of a scope removes the scope.
+func (f *File) Prune() {
+	prune := func(scope *Scope) {
+		if len(scope.Defaults) == 0 {
+			scope.Defaults = nil
+		}
+		for namespace, settings := range scope.Namespaces {
+			if len(settings) == 0 {
+				delete(scope.Namespaces, namespace)
+			}
+		}
+		if len(scope.Namespaces) == 0 {
+			scope.Namespaces = nil
+		}
+	}
+	prune(&f.Scope)
+	for context, scope := range f.Contexts {
+		prune(&scope)
+		if scope.Defaults == nil && scope.Namespaces == nil {
+			delete(f.Contexts, context)
+		} else {
+			f.Contexts[context] = scope
+		}
+	}
+	if len(f.Contexts) == 0 {
+		f.Contexts = nil
+	}
+}
+
+// Value is a resolved setting with where it came from.
+type Value struct {
+	Value  string
+	Source string
+}
+
+// Config is the resolved configuration for a kube context and namespace.
+type Config struct {
+	Context   string
+	Namespace string
+	Values    map[string]Value
+}
+
+// Get returns the resolved value of the key.
+func (c *Config) Get(key string) string {
+	return c.Values[key].Value
+}
+
+// Loader finds and reads the config layers.
+type Loader struct {
+	// UserFile and ProjectFile are skipped when empty.
+	UserFile    string
+	ProjectFile string
+	Getenv      func(string) string
+}
+
+// UserFile returns the path of the user config file.
+func UserFile() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "kubectl-nim", "config.yaml")
+}
+
+// ProjectFile returns the nearest .nim.yaml at or above the working directory, or "" if there is none.
+func ProjectFile() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	for {
+		path := filepath.Join(dir, ProjectFileName)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// NewLoader returns a loader for the user file, the project file and the process environment.
+func NewLoader() *Loader {
+	return &Loader{UserFile: UserFile(), ProjectFile: ProjectFile(), Getenv: os.Getenv}
+}
+
+// Load resolves every key for the kube context and namespace.
+func (l *Loader) Load(context, namespace string) (*Config, error) {
+	config := &Config{Context: context, Namespace: namespace, Values: map[string]Value{}}
+	for _, key := range Keys {
+		config.Values[key.Name] = Value{Value: key.Default, Source: "default"}
+	}
+
+	for _, path := range []string{l.UserFile, l.ProjectFile} {
+		if path == "" {
+			continue
+		}
+		file, err := ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		// A namespace's settings override the context's, and a namespace within the context overrides both.
+		type layer struct {
+			settings Settings
+			scope    string
+		}
+		layers := []layer{{file.Settings("", "", false), ""}}
+		if context != "" {
+			layers = append(layers, layer{file.Settings(context, "", false), "context " + context})
+		}
+		layers = append(layers, layer{file.Settings("", namespace, false), "namespace " + namespace})
+		if context != "" {
+			layers = append(layers, layer{file.Settings(context, namespace, false), "context " + context + ", namespace " + namespace})
+		}
+		for _, layer := range layers {
+			for name, value := range layer.settings {
+				source := path
+				if layer.scope != "" {
+					source += " (" + layer.scope + ")"
+				}
+				config.Values[name] = Value{Value: value, Source: source}
+			}
+		}
+	}
+
+	if l.Getenv != nil {
+		for _, key := range Keys {
+			if value := l.Getenv(key.Env); value != "" {
+				if err := key.Validate(value); err != nil {
+					return nil, fmt.Errorf("%s: %w", key.Env, err)
+				}
+				config.Values[key.Name] = Value{Value: value, Source: "env " + key.Env}
+			}
+		}
+	}
+	return config, nil
+}
+
+// Bind makes the flag take its default from the key.
+func Bind(flags *pflag.FlagSet, flag, key string) {
+	_ = flags.SetAnnotation(flag, keyAnnotation, []string{key})
+}
+
+// BindOutput makes the output flag take its default from the preferred output format, when it is one of formats.
+func BindOutput(flags *pflag.FlagSet, formats ...string) {
+	Bind(flags, "output", Output)
+	_ = flags.SetAnnotation("output", formatsAnnotation, formats)
+}
+
+// Apply sets every bound flag that was not set on the command line to its configured value. The flag's default is
+// updated too, so commands can still tell a configured default from a value set on the command line.
+func Apply(flags *pflag.FlagSet, config *Config) error {
+	var err error
+	flags.VisitAll(func(flag *pflag.Flag) {
+		keys := flag.Annotations[keyAnnotation]
+		if err != nil || len(keys) == 0 || flag.Changed {
+			return
+		}
+		value := config.Get(keys[0])
+		if value == "" || value == flag.DefValue {
+			return
+		}
+		if formats, ok := flag.Annotations[formatsAnnotation]; ok && !contains(formats, value) {
+			return
+		}
+		// A single pull secret flag takes the first of the configured pull secrets.
+		if keys[0] == PullSecrets && flag.Value.Type() == "string" {
+			value = strings.Split(value, ",")[0]
+		}
+		if setter, ok := flag.Value.(pflag.SliceValue); ok {
+			if err = setter.Replace(strings.Split(value, ",")); err != nil {
+				err = fmt.Errorf("invalid %s %q for --%s: %w", keys[0], value, flag.Name, err)
+				return
+			}
+		} else if err = flag.Value.Set(value); err != nil {
+			err = fmt.Errorf("invalid %s %q for --%s: %w", keys[0], value, flag.Name, err)
+			return
+		}
+		flag.DefValue = flag.Value.String()
+	})
+	return err
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Current returns the kube context and namespace the command runs against, honouring --context and --namespace.
+// Without a kubeconfig both are empty.
+func Current(configFlags *genericclioptions.ConfigFlags) (string, string) {
+	loader := configFlags.ToRawKubeConfigLoader()
+	context := ""
+	if configFlags.Context != nil && *configFlags.Context != "" {
+		context = *configFlags.Context
+	} else if raw, err := loader.RawConfig(); err == nil {
+		context = raw.CurrentContext
+	}
+	namespace, _, err := loader.Namespace()
+	if err != nil {
+		namespace = ""
+	}
+	return context, namespace
+}
+
+// ApplyToCommand loads the configuration for the current context and namespace and applies it to the command's flags.
+func ApplyToCommand(cmd *cobra.Command, configFlags *genericclioptions.ConfigFlags) error {
+	config, err := NewLoader().Load(Current(configFlags))
+	if err != nil {
+		return err
+	}
+	return Apply(cmd.Flags(), config)
+}
@@ -11,18 +11,27 @@ const (
 	AuthSecret          = "ngc-api-secret"
 	PVCCreate           = false
 	PVCVolumeAccessMode = "ReadWriteMany"
+	Interactive         = false
 )
 var PullSecrets []string = []string{"ngc-secret"}
 
+// Secret-specific values.
+const (
+	NGCRegistry  = "nvcr.io"
+	HFAuthSecret = "hf-api-secret"
+)
+
 // NIMService-specific values.
 const (
 	ImageRepository              = ""
 	Tag                          = ""
 	NIMCacheStorageName          = ""
 	NIMCacheStorageProfile       = ""
+	FromNIMCache                 = ""
 	ScaleMinReplicas       int32 = -1
 	ScaleMaxReplicas       int32 = -1
 	HostPath                     = ""
+	SharedMemorySize             = ""
 
 	PullPolicy              	= "IfNotPresent"
 	ServicePort       int32 	= 8000
@@ -30,6 +39,35 @@ const (
 	GPULimit                	= "1"
 	Replicas               		= 1
 	InferencePlatform       	= "standalone"
+
+	CPURequest                    = ""
+	CPULimit                      = ""
+	MemoryRequest                 = ""
+	MemoryLimit                   = ""
+	RuntimeClass                  = ""
+	// Negative IDs leave the operator's default user and group in place.
+	UserID                  int64 = -1
+	GroupID                 int64 = -1
+
+	ExposeVia                     = ""
+	IngressHost                   = ""
+	IngressClass                  = ""
+	IngressTLSSecret              = ""
+	IngressPath                   = "/"
+	Gateway                       = ""
+
+	Metrics                       = false
+	ServiceMonitorInterval        = ""
+	// Used when --metrics is set without an interval.
+	DefaultServiceMonitorInterval = "30s"
+	ScaleCPUUtilization     int32 = 0
+
+	// Multi-node is disabled while the size and GPUs per pod are unset.
+	MultiNodeSize                 = 0
+	PipelineParallelism           = 0
+	GPUsPerPod                    = 0
+	MultiNodeBackend              = "lws"
+	MPIStartTimeout               = 0
 )
 
 // NIMCache-specific values.
@@ -57,6 +95,32 @@ const (
 
 	// PullSecret for NIMCache, not PullSecrets
 	PullSecret					 = "ngc-secret"
+
+	// Proxy settings of the caching job. UserID and GroupID are reused.
+	ProxyHTTP                    = ""
+	ProxyHTTPS                   = ""
+	NoProxy                      = ""
+	CAConfigMap                  = ""
 )
 var Profiles []string = []string{}
-var GPUs []string = []string{}
\ No newline at end of file
+var GPUs []string = []string{}
+
+// Labels on the pods of a multi-node NIMService.
+const (
+	// Label the LeaderWorkerSet controller sets on every pod of a LeaderWorkerSet.
+	LWSNameLabel = "leaderworkerset.sigs.k8s.io/name"
+	// Index of the pod within its group; the leader is index 0.
+	LWSWorkerIndexLabel = "leaderworkerset.sigs.k8s.io/worker-index"
+	// Role the operator gives each pod, "leader" or "worker".
+	NIMLLMRoleLabel = "nim-llm-role"
+)
+
+// Inference-specific values.
+const (
+	MaxTokens = 1024
+	// Temperature is unset (server default) when negative.
+	Temperature       = -1.0
+	SystemPrompt      = ""
+	InferenceModel    = ""
+	InferenceEndpoint = ""
+)
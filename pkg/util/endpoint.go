@@ -0,0 +1,89 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+)
+
+// ConnectNIMService returns a base URL for the NIMService API that is reachable from this machine.
+// An explicit endpoint wins, then Status.Model.ExternalEndpoint. Otherwise, or when forcePortForward is set, a
+// port-forward to a ready pod is started on a free local port. The returned func releases the port-forward, if any.
+func ConnectNIMService(ctx context.Context, restConfig *rest.Config, kube kubernetes.Interface, nimservice *appsv1alpha1.NIMService, endpoint string, forcePortForward bool, errOut io.Writer) (string, func(), error) {
+	noop := func() {}
+	if endpoint != "" {
+		return NormalizeEndpoint(endpoint), noop, nil
+	}
+	if !forcePortForward && nimservice.Status.Model != nil && nimservice.Status.Model.ExternalEndpoint != "" {
+		return NormalizeEndpoint(nimservice.Status.Model.ExternalEndpoint), noop, nil
+	}
+
+	target, err := ResolvePortForwardTarget(ctx, kube, nimservice)
+	if err != nil {
+		return "", nil, err
+	}
+	forwardCtx, cancel := context.WithCancel(ctx)
+	localPort, _, err := StartPortForward(forwardCtx, restConfig, kube, target, "localhost", 0, io.Discard, errOut)
+	if err != nil {
+		cancel()
+		return "", nil, err
+	}
+	return fmt.Sprintf("http://localhost:%d", localPort), cancel, nil
+}
+
+// NormalizeEndpoint adds an http scheme to bare host[:port] endpoints as reported in NIMService status.
+func NormalizeEndpoint(endpoint string) string {
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "http://" + endpoint
+	}
+	return endpoint
+}
+
+// RouteURLAnnotation is set on a NIMService created with --expose-via=route to the URL of its OpenShift Route, which is
+// not part of the NIMService spec.
+const RouteURLAnnotation = "cli.nim.nvidia.com/route-url"
+
+// ExternalURL returns the URL the NIMService is exposed at through its Ingress, HTTPRoute or OpenShift Route, or "" if it
+// has none. Ingresses use https when the host is covered by a TLS entry; HTTPRoutes are reported as http since TLS is
+// configured on the Gateway, which the NIMService does not know about.
+func ExternalURL(nimservice *appsv1alpha1.NIMService) string {
+	if nimservice.IsIngressEnabled() {
+		spec := nimservice.Spec.Expose.Ingress.Spec
+		for _, rule := range spec.Rules {
+			if rule.Host == "" {
+				continue
+			}
+			scheme := "http"
+			for _, tls := range spec.TLS {
+				for _, host := range tls.Hosts {
+					if host == rule.Host {
+						scheme = "https"
+					}
+				}
+			}
+			path := ""
+			if rule.HTTP != nil && len(rule.HTTP.Paths) > 0 && rule.HTTP.Paths[0].Path != "/" {
+				path = rule.HTTP.Paths[0].Path
+			}
+			return scheme + "://" + rule.Host + path
+		}
+	}
+	if nimservice.IsHTTPRouteEnabled() && nimservice.Spec.Expose.HTTPRoute.Spec != nil {
+		spec := nimservice.Spec.Expose.HTTPRoute.Spec
+		if spec.Host != "" {
+			path := ""
+			if len(spec.Paths) > 0 && spec.Paths[0].Value != nil && *spec.Paths[0].Value != "/" {
+				path = *spec.Paths[0].Value
+			}
+			return "http://" + string(spec.Host) + path
+		}
+	}
+	return nimservice.GetAnnotations()[RouteURLAnnotation]
+}
@@ -0,0 +1,16 @@
+package util
+
+// ExitError is an error that exits the process with Code instead of 1, for commands whose exit code carries a result,
+// like nim diff signalling drift.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
@@ -154,6 +154,46 @@ func FetchResources(ctx context.Context, options *FetchResourceOptions, k8sClien
 	return resourceList, nil
 }
 
+// FetchNIMService returns the single NIMService named in options.
+func FetchNIMService(ctx context.Context, options *FetchResourceOptions, k8sClient client.Client) (*appsv1alpha1.NIMService, error) {
+	options.ResourceType = NIMService
+	resourceList, err := FetchResources(ctx, options, k8sClient)
+	if err != nil {
+		return nil, err
+	}
+	nl, ok := resourceList.(*appsv1alpha1.NIMServiceList)
+	if !ok {
+		return nil, fmt.Errorf("failed to cast resourceList to NIMServiceList")
+	}
+	// Match on the name as well, since not every client honors the field selector.
+	for i := range nl.Items {
+		if nl.Items[i].GetName() == options.ResourceName {
+			return &nl.Items[i], nil
+		}
+	}
+	return nil, fmt.Errorf("NIMService %q not found", options.ResourceName)
+}
+
+// FetchNIMCache returns the single NIMCache named in options.
+func FetchNIMCache(ctx context.Context, options *FetchResourceOptions, k8sClient client.Client) (*appsv1alpha1.NIMCache, error) {
+	options.ResourceType = NIMCache
+	resourceList, err := FetchResources(ctx, options, k8sClient)
+	if err != nil {
+		return nil, err
+	}
+	nl, ok := resourceList.(*appsv1alpha1.NIMCacheList)
+	if !ok {
+		return nil, fmt.Errorf("failed to cast resourceList to NIMCacheList")
+	}
+	// Match on the name as well, since not every client honors the field selector.
+	for i := range nl.Items {
+		if nl.Items[i].GetName() == options.ResourceName {
+			return &nl.Items[i], nil
+		}
+	}
+	return nil, fmt.Errorf("NIMCache %q not found", options.ResourceName)
+}
+
 func messageConditionFrom(conds []v1.Condition) (*v1.Condition, error) {
 	// Prefer a Failed with a non-empty message
 	if failed := apimeta.FindStatusCondition(conds, "Failed"); failed != nil && failed.Message != "" {
@@ -0,0 +1,9 @@
+package util
+
+// OrNone returns s, or "<none>" when it is empty, for table cells and messages.
+func OrNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
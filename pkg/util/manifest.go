@@ -0,0 +1,194 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// Key of the model manifest in the ConfigMap the operator creates for each NIMCache.
+	ModelManifestKey = "model_manifest.yaml"
+	// Suffix the operator appends to the NIMCache name when naming the manifest ConfigMap.
+	ModelManifestSuffix = "-manifest"
+)
+
+// Manifests with a schema_version of 2.x list profiles; older manifests are a map keyed by profile ID.
+var manifestV2Schema = regexp.MustCompile(`^2\.`)
+
+// ModelManifest is a decoded NIM model manifest.
+type ModelManifest struct {
+	SchemaVersion string
+	Profiles      []ProfileInfo
+}
+
+type manifestV1Profile struct {
+	Model   string            `json:"model"`
+	Release string            `json:"release"`
+	Tags    map[string]string `json:"tags"`
+}
+
+type manifestV2 struct {
+	SchemaVersion string `json:"schema_version"`
+	Profiles      []struct {
+		ID   string            `json:"id"`
+		Tags map[string]string `json:"tags"`
+	} `json:"profiles"`
+}
+
+// ParseModelManifest decodes a model manifest in either schema. v1 profiles are sorted by ID, v2 profiles keep the manifest order.
+func ParseModelManifest(data []byte) (*ModelManifest, error) {
+	var header struct {
+		SchemaVersion string `json:"schema_version"`
+	}
+	// A v1 manifest has no schema_version, so it falls through to the v1 decode, which reports any syntax error.
+	_ = yaml.Unmarshal(data, &header)
+
+	if manifestV2Schema.MatchString(header.SchemaVersion) {
+		var manifest manifestV2
+		if err := yaml.Unmarshal(data, &manifest); err != nil {
+			return nil, fmt.Errorf("failed to decode model manifest: %w", err)
+		}
+		result := &ModelManifest{SchemaVersion: manifest.SchemaVersion}
+		for _, profile := range manifest.Profiles {
+			result.Profiles = append(result.Profiles, NewProfileInfoFromTags(profile.ID, profile.Tags, "", ""))
+		}
+		return result, nil
+	}
+
+	var manifest map[string]manifestV1Profile
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to decode model manifest: %w", err)
+	}
+	result := &ModelManifest{SchemaVersion: "1"}
+	for id, profile := range manifest {
+		result.Profiles = append(result.Profiles, NewProfileInfoFromTags(id, profile.Tags, profile.Model, profile.Release))
+	}
+	sort.Slice(result.Profiles, func(i, j int) bool { return result.Profiles[i].ID < result.Profiles[j].ID })
+	return result, nil
+}
+
+// ProfileMismatches returns the reasons the operator would not select the profile for the model spec, or nil if it would.
+// It mirrors the operator's profile selection: discoveredGPUs are the nvidia.com/gpu.product labels of the cluster's nodes,
+// which the operator falls back to when the spec names no GPU that matches.
+func ProfileMismatches(spec appsv1alpha1.ModelSpec, profile ProfileInfo, discoveredGPUs []string) []string {
+	// Explicitly listed profiles override every other selection parameter.
+	if len(spec.Profiles) > 0 {
+		for _, id := range spec.Profiles {
+			if id == profile.ID {
+				return nil
+			}
+		}
+		return []string{"not listed in spec profiles"}
+	}
+
+	tags := profile.Config
+	var reasons []string
+	for _, check := range []struct{ name, want, got string }{
+		{"precision", spec.Precision, tags["precision"]},
+		{"tp", spec.TensorParallelism, tags["tp"]},
+		{"profile", spec.QoSProfile, tags["profile"]},
+	} {
+		if check.want != "" && check.want != check.got {
+			reasons = append(reasons, fmt.Sprintf("%s %s != %s", check.name, OrNone(check.got), check.want))
+		}
+	}
+	if spec.Lora != nil && tags["feat_lora"] != strconv.FormatBool(*spec.Lora) {
+		reasons = append(reasons, fmt.Sprintf("lora %s != %t", OrNone(tags["feat_lora"]), *spec.Lora))
+	}
+	if spec.Buildable != nil && tags["trtllm_buildable"] != strconv.FormatBool(*spec.Buildable) {
+		reasons = append(reasons, fmt.Sprintf("buildable %s != %t", OrNone(tags["trtllm_buildable"]), *spec.Buildable))
+	}
+
+	backend := profileBackend(tags)
+	engineMismatch := spec.Engine != "" && !strings.Contains(backend, strings.TrimSuffix(spec.Engine, "_llm"))
+	if engineMismatch {
+		reasons = append(reasons, fmt.Sprintf("engine %s != %s", OrNone(backend), spec.Engine))
+	}
+
+	// GPUs are only matched for optimized profiles, and only when an optimized engine or GPUs are requested.
+	if tags["trtllm_buildable"] != "true" && (isOptimizedEngine(spec.Engine) || len(spec.GPUs) > 0) {
+		if !isOptimizedEngine(backend) {
+			// A mismatching engine has already been reported.
+			if !engineMismatch {
+				reasons = append(reasons, fmt.Sprintf("engine %s is not optimized", OrNone(backend)))
+			}
+		} else if (len(spec.GPUs) > 0 || len(discoveredGPUs) > 0) && !matchesGPU(spec.GPUs, tags, discoveredGPUs) {
+			if tags["gpu"] == "" {
+				reasons = append(reasons, "gpu unknown, the profile has no gpu tag to match")
+			} else {
+				reasons = append(reasons, fmt.Sprintf("gpu %s not requested or found on nodes", tags["gpu"]))
+			}
+		}
+	}
+	return reasons
+}
+
+// Returns the engine the operator matches against: llm_engine, then model_type for non LLM models, then the deprecated backend tag.
+func profileBackend(tags map[string]string) string {
+	backend := tags["llm_engine"]
+	if backend == "" {
+		backend = tags["model_type"]
+	}
+	if backend == "" {
+		backend = tags["backend"]
+	}
+	// The spec engine is e.g. "tensorrt_llm", so a "triton" backend is treated as tensorrt.
+	if backend == "triton" {
+		backend = "tensorrt"
+	}
+	return backend
+}
+
+func isOptimizedEngine(engine string) bool {
+	return engine != "" && strings.Contains(strings.ToLower(engine), "tensorrt")
+}
+
+func matchesGPU(gpus []appsv1alpha1.GPUSpec, tags map[string]string, discoveredGPUs []string) bool {
+	found := false
+	for _, gpu := range gpus {
+		if gpu.Product == "" {
+			continue
+		}
+		product := strings.ToLower(gpu.Product)
+		if strings.Contains(strings.ToLower(tags["gpu"]), product) || strings.Contains(strings.ToLower(tags["key"]), product) {
+			found = true
+		}
+		if found && len(gpu.IDs) > 0 {
+			matchedID := false
+			for _, id := range gpu.IDs {
+				if id == strings.TrimSuffix(tags["gpu_device"], ":10de") {
+					matchedID = true
+					break
+				}
+			}
+			if !matchedID {
+				return false
+			}
+		}
+	}
+	if found {
+		return true
+	}
+
+	for _, label := range discoveredGPUs {
+		if label == "" {
+			continue
+		}
+		// An empty gpu tag would be contained in every label; without one only the regex can match.
+		if tags["gpu"] != "" && strings.Contains(strings.ToLower(label), strings.ToLower(tags["gpu"])) {
+			return true
+		}
+		if pattern := tags["product_name_regex"]; pattern != "" {
+			if re, err := regexp.Compile(pattern); err == nil && re.MatchString(label) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/kubernetes"
+
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+)
+
+// MultiNodeReadiness counts the ready leader and worker pods of a multi-node NIMService.
+type MultiNodeReadiness struct {
+	LeadersReady int
+	Leaders      int
+	WorkersReady int
+	Workers      int
+}
+
+// Leader returns the leader readiness as READY/TOTAL.
+func (r *MultiNodeReadiness) Leader() string {
+	return fmt.Sprintf("%d/%d", r.LeadersReady, r.Leaders)
+}
+
+// Worker returns the worker readiness as READY/TOTAL.
+func (r *MultiNodeReadiness) Worker() string {
+	return fmt.Sprintf("%d/%d", r.WorkersReady, r.Workers)
+}
+
+// IsMultiNode reports whether the NIMService runs as a LeaderWorkerSet.
+func IsMultiNode(nimservice *appsv1alpha1.NIMService) bool {
+	return nimservice.Spec.MultiNode != nil
+}
+
+// GetMultiNodeReadiness lists the pods of the NIMService's LeaderWorkerSet and counts ready leaders and workers.
+// The NIMService's Service only selects leaders, so pods are found through the LeaderWorkerSet label instead.
+func GetMultiNodeReadiness(ctx context.Context, kube kubernetes.Interface, nimservice *appsv1alpha1.NIMService) (*MultiNodeReadiness, error) {
+	pods, err := kube.CoreV1().Pods(nimservice.GetNamespace()).List(ctx, v1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{LWSNameLabel: nimservice.GetLWSName()}).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods for NIMService %s/%s: %w", nimservice.GetNamespace(), nimservice.GetName(), err)
+	}
+
+	readiness := &MultiNodeReadiness{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		ready := IsPodReady(pod)
+		if pod.Labels[LWSWorkerIndexLabel] == "0" || pod.Labels[NIMLLMRoleLabel] == "leader" {
+			readiness.Leaders++
+			if ready {
+				readiness.LeadersReady++
+			}
+			continue
+		}
+		readiness.Workers++
+		if ready {
+			readiness.WorkersReady++
+		}
+	}
+	return readiness, nil
+}
+
+// PrintMultiNodePods prints the leader and worker pod readiness of the multi-node NIMServices in the list, if there are
+// any. Readiness that cannot be read, e.g. without permission to list pods, is shown as unknown.
+func PrintMultiNodePods(ctx context.Context, kube kubernetes.Interface, nimServiceList *appsv1alpha1.NIMServiceList, output io.Writer) error {
+	resTable := &v1.Table{
+		ColumnDefinitions: []v1.TableColumnDefinition{
+			{Name: "Multi-Node NIMService", Type: "string"},
+			{Name: "Namespace", Type: "string"},
+			{Name: "Size", Type: "int"},
+			{Name: "GPUs Per Pod", Type: "int"},
+			{Name: "Leaders Ready", Type: "string"},
+			{Name: "Workers Ready", Type: "string"},
+		},
+	}
+	for _, nimservice := range nimServiceList.Items {
+		if !IsMultiNode(&nimservice) {
+			continue
+		}
+		leaders, workers := "<unknown>", "<unknown>"
+		if readiness, err := GetMultiNodeReadiness(ctx, kube, &nimservice); err == nil {
+			leaders, workers = readiness.Leader(), readiness.Worker()
+		}
+		resTable.Rows = append(resTable.Rows, v1.TableRow{
+			Cells: []interface{}{
+				nimservice.GetName(),
+				nimservice.GetNamespace(),
+				nimservice.Spec.MultiNode.Size,
+				nimservice.Spec.MultiNode.GPUSPerPod,
+				leaders,
+				workers,
+			},
+		})
+	}
+	if len(resTable.Rows) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(output)
+	return printers.NewTablePrinter(printers.PrintOptions{}).PrintObj(resTable, output)
+}
@@ -0,0 +1,117 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// Stdin is the file name that reads objects from standard input.
+const Stdin = "-"
+
+// FileObject is an object decoded from a manifest file, with the file it came from.
+type FileObject struct {
+	Source string
+	Object *unstructured.Unstructured
+}
+
+// ReadObjects decodes every object in the given files. Directories contribute their .yaml, .yml and .json files,
+// without recursing, and "-" reads from in. Files may hold several YAML documents, and List objects are expanded
+// into their items. Empty documents are skipped.
+func ReadObjects(paths []string, in io.Reader) ([]FileObject, error) {
+	var objects []FileObject
+	for _, path := range paths {
+		files, err := expandPath(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			decoded, err := readFile(file, in)
+			if err != nil {
+				return nil, err
+			}
+			objects = append(objects, decoded...)
+		}
+	}
+	return objects, nil
+}
+
+func expandPath(path string) ([]string, error) {
+	if path == Stdin {
+		return []string{path}, nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".yaml", ".yml", ".json":
+			if !entry.IsDir() {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func readFile(file string, in io.Reader) ([]FileObject, error) {
+	reader := in
+	source := "<stdin>"
+	if file != Stdin {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		reader = f
+		source = file
+	}
+
+	var objects []FileObject
+	decoder := utilyaml.NewYAMLOrJSONDecoder(reader, 4096)
+	for doc := 1; ; doc++ {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to decode %s, document %d: %w", source, doc, err)
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		if obj.GetKind() == "" {
+			return nil, fmt.Errorf("%s, document %d: object has no kind", source, doc)
+		}
+		if !obj.IsList() {
+			objects = append(objects, FileObject{Source: source, Object: obj})
+			continue
+		}
+		err := obj.EachListItem(func(item runtime.Object) error {
+			objects = append(objects, FileObject{Source: source, Object: item.(*unstructured.Unstructured)})
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode the list in %s, document %d: %w", source, doc, err)
+		}
+	}
+	return objects, nil
+}
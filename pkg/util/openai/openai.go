@@ -0,0 +1,236 @@
+package openai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Client talks to the OpenAI-compatible API served by a NIM.
+type Client struct {
+	// BaseURL is the server root, e.g. http://localhost:8000. The /v1 prefix is added per request.
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+func NewClient(baseURL string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimSuffix(strings.TrimSuffix(baseURL, "/"), "/v1"),
+		HTTPClient: http.DefaultClient,
+	}
+}
+
+type ChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type StreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+type ChatCompletionRequest struct {
+	Model         string         `json:"model"`
+	Messages      []ChatMessage  `json:"messages"`
+	MaxTokens     int            `json:"max_tokens,omitempty"`
+	Temperature   *float64       `json:"temperature,omitempty"`
+	Stream        bool           `json:"stream"`
+	StreamOptions *StreamOptions `json:"stream_options,omitempty"`
+}
+
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// ChatCompletionChunk is a single server-sent event of a streamed chat completion.
+type ChatCompletionChunk struct {
+	ID      string `json:"id"`
+	Model   string `json:"model"`
+	Choices []struct {
+		Index int `json:"index"`
+		Delta struct {
+			Role    string `json:"role,omitempty"`
+			Content string `json:"content,omitempty"`
+		} `json:"delta"`
+		FinishReason *string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *Usage `json:"usage,omitempty"`
+}
+
+// ChatCompletionResult is the accumulated result of a streamed chat completion.
+type ChatCompletionResult struct {
+	Content      string
+	FinishReason string
+	// Chunks is the number of events that carried content.
+	Chunks int
+	// Usage is only set when the server reports it.
+	Usage *Usage
+}
+
+// StreamChatCompletion calls /v1/chat/completions with streaming enabled and invokes onDelta for every content delta.
+func (c *Client) StreamChatCompletion(ctx context.Context, request ChatCompletionRequest, onDelta func(string) error) (*ChatCompletionResult, error) {
+	request.Stream = true
+	if request.StreamOptions == nil {
+		request.StreamOptions = &StreamOptions{IncludeUsage: true}
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode chat completion request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.BaseURL+"/v1/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("chat completion request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil {
+		return nil, fmt.Errorf("chat completion request failed: %w", err)
+	}
+
+	result := &ChatCompletionResult{}
+	var content strings.Builder
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "data:") {
+			// Blank separators, comments and other SSE fields are ignored.
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk ChatCompletionChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return nil, fmt.Errorf("failed to decode chat completion chunk %q: %w", data, err)
+		}
+		if chunk.Usage != nil {
+			result.Usage = chunk.Usage
+		}
+		for _, choice := range chunk.Choices {
+			if choice.FinishReason != nil {
+				result.FinishReason = *choice.FinishReason
+			}
+			if choice.Delta.Content == "" {
+				continue
+			}
+			result.Chunks++
+			content.WriteString(choice.Delta.Content)
+			if onDelta != nil {
+				if err := onDelta(choice.Delta.Content); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read chat completion stream: %w", err)
+	}
+
+	result.Content = content.String()
+	return result, nil
+}
+
+// Model is an entry of /v1/models. LoRA adapters are listed next to the base model and carry it in Parent.
+type Model struct {
+	ID          string `json:"id"`
+	Object      string `json:"object"`
+	Created     int64  `json:"created"`
+	OwnedBy     string `json:"owned_by"`
+	Root        string `json:"root,omitempty"`
+	Parent      string `json:"parent,omitempty"`
+	MaxModelLen int    `json:"max_model_len,omitempty"`
+}
+
+// IsAdapter reports whether the model is a LoRA adapter served on top of another model.
+func (m Model) IsAdapter() bool {
+	return m.Parent != "" && m.Parent != m.ID
+}
+
+type modelList struct {
+	Object string  `json:"object"`
+	Data   []Model `json:"data"`
+}
+
+// ListModels calls /v1/models and returns the served models, including LoRA adapters.
+func (c *Client) ListModels(ctx context.Context) ([]Model, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURL+"/v1/models", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("models request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil {
+		return nil, fmt.Errorf("models request failed: %w", err)
+	}
+
+	var list modelList
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("failed to decode models response: %w", err)
+	}
+	return list.Data, nil
+}
+
+// ProbeResult is the outcome of a single health endpoint request.
+type ProbeResult struct {
+	StatusCode int
+	Latency    time.Duration
+}
+
+// OK reports whether the endpoint answered with a 2xx status.
+func (r ProbeResult) OK() bool {
+	return r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+// Probe sends a GET to path, e.g. /v1/health/ready, and reports the status code and latency.
+// Only transport failures are returned as errors; non-2xx answers are a valid result.
+func (c *Client) Probe(ctx context.Context, path string) (ProbeResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURL+path, nil)
+	if err != nil {
+		return ProbeResult{}, err
+	}
+
+	start := time.Now()
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return ProbeResult{}, fmt.Errorf("%s request failed: %w", path, err)
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	resp.Body.Close()
+	return ProbeResult{StatusCode: resp.StatusCode, Latency: time.Since(start)}, nil
+}
+
+// Returns an error carrying the status and a bounded excerpt of the body for non-2xx responses.
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	excerpt, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if msg := strings.TrimSpace(string(excerpt)); msg != "" {
+		return fmt.Errorf("%s: %s", resp.Status, msg)
+	}
+	return fmt.Errorf("%s", resp.Status)
+}
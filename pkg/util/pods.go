@@ -0,0 +1,24 @@
+package util
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// Label the operator sets on the pods of a NIMService, with its name.
+	InstanceLabel = "app.kubernetes.io/instance"
+	// Label the operator sets on the caching job pods of a NIMCache, with its name. NIMService pods carry it too.
+	NameLabel = "app.kubernetes.io/name"
+)
+
+// PodOwner returns the type and name of the NIMService or NIMCache the operator labelled the pod for, or empty values
+// when it has neither label. The pods of a multi-node NIMService are found by their LWSNameLabel instead.
+func PodOwner(pod *corev1.Pod) (ResourceType, string) {
+	if instance := pod.Labels[InstanceLabel]; instance != "" {
+		return NIMService, instance
+	}
+	if name := pod.Labels[NameLabel]; name != "" {
+		return NIMCache, name
+	}
+	return "", ""
+}
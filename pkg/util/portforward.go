@@ -0,0 +1,161 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+	kubectlutil "k8s.io/kubectl/pkg/util"
+
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+)
+
+// PortForwardTarget is the pod and port that traffic for a NIMService's Service is forwarded to.
+type PortForwardTarget struct {
+	Namespace   string
+	ServiceName string
+	ServicePort int32
+	PodName     string
+	PodPort     int32
+}
+
+// NIMServiceServiceName returns the name of the Service the operator generates for a NIMService.
+// The operator always names it after the NIMService, regardless of spec.expose.service.name.
+func NIMServiceServiceName(nimservice *appsv1alpha1.NIMService) string {
+	return nimservice.GetName()
+}
+
+// ResolvePortForwardTarget finds the NIMService's Service and a ready pod backing it.
+func ResolvePortForwardTarget(ctx context.Context, kube kubernetes.Interface, nimservice *appsv1alpha1.NIMService) (*PortForwardTarget, error) {
+	svc, pods, err := ListNIMServicePods(ctx, kube, nimservice)
+	if err != nil {
+		return nil, err
+	}
+	pod := firstReadyPod(pods)
+	if pod == nil {
+		return nil, fmt.Errorf("no ready pods found for NIMService %s/%s", nimservice.GetNamespace(), nimservice.GetName())
+	}
+	return PodPortForwardTarget(svc, pod, nimservice.GetServicePort())
+}
+
+// ListNIMServicePods returns the NIMService's Service and all pods matched by its selector, ready or not.
+func ListNIMServicePods(ctx context.Context, kube kubernetes.Interface, nimservice *appsv1alpha1.NIMService) (*corev1.Service, []corev1.Pod, error) {
+	namespace := nimservice.GetNamespace()
+	serviceName := NIMServiceServiceName(nimservice)
+
+	svc, err := kube.CoreV1().Services(namespace).Get(ctx, serviceName, v1.GetOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get Service %s/%s for NIMService %s: %w", namespace, serviceName, nimservice.GetName(), err)
+	}
+	if len(svc.Spec.Selector) == 0 {
+		return nil, nil, fmt.Errorf("service %s/%s has no pod selector", namespace, serviceName)
+	}
+
+	pods, err := kube.CoreV1().Pods(namespace).List(ctx, v1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(svc.Spec.Selector).String(),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list pods for Service %s/%s: %w", namespace, serviceName, err)
+	}
+	sort.Slice(pods.Items, func(i, j int) bool { return pods.Items[i].Name < pods.Items[j].Name })
+	return svc, pods.Items, nil
+}
+
+// PodPortForwardTarget maps servicePort of svc onto the matching container port of pod.
+func PodPortForwardTarget(svc *corev1.Service, pod *corev1.Pod, servicePort int32) (*PortForwardTarget, error) {
+	podPort, err := kubectlutil.LookupContainerPortNumberByServicePort(*svc, *pod, servicePort)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve port %d of Service %s/%s: %w", servicePort, svc.GetNamespace(), svc.GetName(), err)
+	}
+	return &PortForwardTarget{
+		Namespace:   svc.GetNamespace(),
+		ServiceName: svc.GetName(),
+		ServicePort: servicePort,
+		PodName:     pod.GetName(),
+		PodPort:     podPort,
+	}, nil
+}
+
+// Returns the first running, ready and non-terminating pod, in the given order.
+func firstReadyPod(pods []corev1.Pod) *corev1.Pod {
+	for i := range pods {
+		if IsPodReady(&pods[i]) {
+			return &pods[i]
+		}
+	}
+	return nil
+}
+
+// IsPodReady reports whether a pod is running, not terminating and has the Ready condition set.
+func IsPodReady(pod *corev1.Pod) bool {
+	if pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodRunning {
+		return false
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// StartPortForward opens a SPDY port-forward from address:localPort to the target pod. A localPort of 0 picks a free port.
+// It returns once the forward is listening with the bound local port and a channel that yields the result of the forward
+// after ctx is cancelled or the connection is lost.
+func StartPortForward(ctx context.Context, restConfig *rest.Config, kube kubernetes.Interface, target *PortForwardTarget, address string, localPort int, out, errOut io.Writer) (uint16, <-chan error, error) {
+	transport, upgrader, err := spdy.RoundTripperFor(restConfig)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to create SPDY round tripper: %w", err)
+	}
+	url := kube.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(target.Namespace).
+		Name(target.PodName).
+		SubResource("portforward").
+		URL()
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, url)
+
+	stopCh := make(chan struct{})
+	readyCh := make(chan struct{})
+	ports := []string{fmt.Sprintf("%d:%d", localPort, target.PodPort)}
+	forwarder, err := portforward.NewOnAddresses(dialer, []string{address}, ports, stopCh, readyCh, out, errOut)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to create port-forward: %w", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- forwarder.ForwardPorts()
+	}()
+	go func() {
+		<-ctx.Done()
+		close(stopCh)
+	}()
+
+	select {
+	case <-readyCh:
+	case err := <-done:
+		if err == nil {
+			err = fmt.Errorf("port-forward to %s/%s closed before becoming ready", target.Namespace, target.PodName)
+		}
+		return 0, nil, err
+	}
+
+	forwarded, err := forwarder.GetPorts()
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to determine local port: %w", err)
+	}
+	if len(forwarded) == 0 {
+		return 0, nil, fmt.Errorf("port-forward to %s/%s has no listening ports", target.Namespace, target.PodName)
+	}
+	return forwarded[0].Local, done, nil
+}
@@ -0,0 +1,104 @@
+package util
+
+import (
+	"strings"
+
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+)
+
+// ProfileInfo is a NIM model profile with the commonly used config tags pulled out.
+// Tags missing from the profile config are left empty.
+type ProfileInfo struct {
+	ID        string
+	Model     string
+	Release   string
+	Engine    string
+	Precision string
+	TP        string
+	PP        string
+	GPU       string
+	LLMEngine string
+	Lora      string
+	Profile   string
+	Config    map[string]string
+}
+
+// Config keys as they appear in NIM model manifests. Later keys are fallbacks.
+var (
+	engineKeys    = []string{"engine", "backend"}
+	precisionKeys = []string{"precision"}
+	tpKeys        = []string{"tp", "tensor_parallelism"}
+	ppKeys        = []string{"pp", "pipeline_parallelism"}
+	gpuKeys       = []string{"gpu", "gpu_product"}
+	llmEngineKeys = []string{"llm_engine"}
+	loraKeys      = []string{"feat_lora", "lora"}
+	profileKeys   = []string{"profile"}
+)
+
+// NewProfileInfo parses the config tags of a profile reported in NIMCache status.
+func NewProfileInfo(profile appsv1alpha1.NIMProfile) ProfileInfo {
+	return NewProfileInfoFromTags(profile.Name, profile.Config, profile.Model, profile.Release)
+}
+
+// NewProfileInfoFromTags parses a profile given its ID and config tags.
+func NewProfileInfoFromTags(id string, tags map[string]string, model, release string) ProfileInfo {
+	return ProfileInfo{
+		ID:        id,
+		Model:     model,
+		Release:   release,
+		Engine:    lookupTag(tags, engineKeys),
+		Precision: lookupTag(tags, precisionKeys),
+		TP:        lookupTag(tags, tpKeys),
+		PP:        lookupTag(tags, ppKeys),
+		GPU:       lookupTag(tags, gpuKeys),
+		LLMEngine: lookupTag(tags, llmEngineKeys),
+		Lora:      lookupTag(tags, loraKeys),
+		Profile:   lookupTag(tags, profileKeys),
+		Config:    tags,
+	}
+}
+
+// Returns the value of the first key present in tags, matching keys case-insensitively.
+func lookupTag(tags map[string]string, keys []string) string {
+	for _, key := range keys {
+		for k, v := range tags {
+			if strings.EqualFold(k, key) {
+				return v
+			}
+		}
+	}
+	return ""
+}
+
+// ProfileFilter selects profiles by tag. Empty fields match everything; values are compared case-insensitively.
+type ProfileFilter struct {
+	GPU       string
+	Precision string
+	Engine    string
+	TP        string
+	Lora      string
+	Profile   string
+}
+
+// Matches reports whether the profile satisfies every set field of the filter.
+// GPU matches on substrings, so "h100" selects both "H100" and "H100_NVL".
+func (f ProfileFilter) Matches(p ProfileInfo) bool {
+	if f.GPU != "" && !strings.Contains(strings.ToLower(p.GPU), strings.ToLower(f.GPU)) {
+		return false
+	}
+	for _, pair := range [][2]string{
+		{f.Precision, p.Precision},
+		{f.TP, p.TP},
+		{f.Lora, p.Lora},
+		{f.Profile, p.Profile},
+	} {
+		if pair[0] != "" && !strings.EqualFold(pair[0], pair[1]) {
+			return false
+		}
+	}
+	// The engine filter accepts either the engine tag or the llm_engine tag, since manifests differ in which they set.
+	if f.Engine != "" && !strings.EqualFold(f.Engine, p.Engine) && !strings.EqualFold(f.Engine, p.LLMEngine) {
+		return false
+	}
+	return true
+}
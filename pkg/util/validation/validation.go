@@ -0,0 +1,522 @@
+// Package validation checks NIMService and NIMCache objects on the client, before they reach the API server.
+// It mirrors the rules of the operator's admission webhooks and CRD schema, and adds checks the webhooks leave to
+// the controller, so that every problem with an object is reported at once as a field.ErrorList.
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	apivalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const gpuResource = corev1.ResourceName("nvidia.com/gpu")
+
+var (
+	supportedPullPolicies   = []string{string(corev1.PullAlways), string(corev1.PullIfNotPresent), string(corev1.PullNever)}
+	supportedAccessModes    = []string{string(corev1.ReadWriteOnce), string(corev1.ReadOnlyMany), string(corev1.ReadWriteMany), string(corev1.ReadWriteOncePod)}
+	supportedServiceTypes   = []string{string(corev1.ServiceTypeClusterIP), string(corev1.ServiceTypeNodePort), string(corev1.ServiceTypeLoadBalancer)}
+	supportedPlatforms      = []string{string(appsv1alpha1.PlatformTypeStandalone), string(appsv1alpha1.PlatformTypeKServe)}
+	supportedBackends       = []string{string(appsv1alpha1.NIMBackendTypeLWS)}
+	supportedQoSProfiles    = []string{"latency", "throughput"}
+	supportedTaintEffects   = []string{string(corev1.TaintEffectNoSchedule), string(corev1.TaintEffectPreferNoSchedule), string(corev1.TaintEffectNoExecute)}
+	supportedTolerationOps  = []string{string(corev1.TolerationOpExists), string(corev1.TolerationOpEqual)}
+	proxyURL                = regexp.MustCompile(`^https?://`)
+	noProxyHost             = regexp.MustCompile(`^\.?[a-zA-Z0-9.-]+$`)
+	noProxyIPOrCIDR         = regexp.MustCompile(`^(\d{1,3}\.){3}\d{1,3}(:\d+)?(/\d{1,2})?$|^\[[0-9a-fA-F:]+\](:\d+|/\d{1,3})?$`)
+	kserveDeploymentModeKey = "serving.kserve.org/deploymentMode"
+)
+
+// ValidateNIMService validates the NIMService's name and spec.
+func ValidateNIMService(nimservice *appsv1alpha1.NIMService) field.ErrorList {
+	errs := validateObjectMeta(&nimservice.ObjectMeta, field.NewPath("metadata"))
+	return append(errs, ValidateNIMServiceSpec(&nimservice.Spec, field.NewPath("spec"))...)
+}
+
+// ValidateNIMCache validates the NIMCache's name and spec.
+func ValidateNIMCache(nimcache *appsv1alpha1.NIMCache) field.ErrorList {
+	errs := validateObjectMeta(&nimcache.ObjectMeta, field.NewPath("metadata"))
+	return append(errs, ValidateNIMCacheSpec(&nimcache.Spec, field.NewPath("spec"))...)
+}
+
+// ValidateObject decodes a NIMService or NIMCache manifest strictly and validates it. The second return is false for
+// other kinds, which are not checked; the error reports an object that does not decode, such as one with unknown fields.
+func ValidateObject(obj *unstructured.Unstructured) (field.ErrorList, bool, error) {
+	gvk := obj.GroupVersionKind()
+	if gvk.Group != appsv1alpha1.SchemeGroupVersion.Group || (gvk.Kind != "NIMService" && gvk.Kind != "NIMCache") {
+		return nil, false, nil
+	}
+	if gvk.Version != appsv1alpha1.SchemeGroupVersion.Version {
+		return field.ErrorList{field.NotSupported(field.NewPath("apiVersion"), obj.GetAPIVersion(), []string{appsv1alpha1.SchemeGroupVersion.String()})}, true, nil
+	}
+
+	if gvk.Kind == "NIMService" {
+		nimservice := &appsv1alpha1.NIMService{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructuredWithValidation(obj.Object, nimservice, true); err != nil {
+			return nil, true, err
+		}
+		return ValidateNIMService(nimservice), true, nil
+	}
+	nimcache := &appsv1alpha1.NIMCache{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructuredWithValidation(obj.Object, nimcache, true); err != nil {
+		return nil, true, err
+	}
+	return ValidateNIMCache(nimcache), true, nil
+}
+
+// Error returns nil for an empty list, and otherwise an error naming the object and listing every field error on its
+// own line. An empty name is left out, for objects that are not named yet.
+func Error(kind, name string, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	var b strings.Builder
+	if name == "" {
+		fmt.Fprintf(&b, "%s is invalid:", kind)
+	} else {
+		fmt.Fprintf(&b, "%s %q is invalid:", kind, name)
+	}
+	for _, err := range errs {
+		fmt.Fprintf(&b, "\n  * %s", err.Error())
+	}
+	return errors.New(b.String())
+}
+
+func validateObjectMeta(meta *v1.ObjectMeta, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if meta.Name == "" {
+		errs = append(errs, field.Required(fldPath.Child("name"), ""))
+	} else {
+		for _, msg := range apivalidation.IsDNS1123Subdomain(meta.Name) {
+			errs = append(errs, field.Invalid(fldPath.Child("name"), meta.Name, msg))
+		}
+	}
+	if meta.Namespace != "" {
+		for _, msg := range apivalidation.IsDNS1123Label(meta.Namespace) {
+			errs = append(errs, field.Invalid(fldPath.Child("namespace"), meta.Namespace, msg))
+		}
+	}
+	return errs
+}
+
+// ValidateNIMServiceSpec validates a NIMService spec rooted at fldPath.
+func ValidateNIMServiceSpec(spec *appsv1alpha1.NIMServiceSpec, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+
+	imagePath := fldPath.Child("image")
+	if spec.Image.Repository == "" {
+		errs = append(errs, field.Required(imagePath.Child("repository"), ""))
+	}
+	if spec.Image.Tag == "" {
+		errs = append(errs, field.Required(imagePath.Child("tag"), ""))
+	}
+	errs = append(errs, validateEnum(imagePath.Child("pullPolicy"), spec.Image.PullPolicy, supportedPullPolicies)...)
+	if spec.AuthSecret == "" {
+		errs = append(errs, field.Required(fldPath.Child("authSecret"), ""))
+	}
+
+	errs = append(errs, validateNIMServiceStorage(&spec.Storage, fldPath.Child("storage"))...)
+	errs = append(errs, validateExpose(&spec.Expose, fldPath.Child("expose"))...)
+
+	if spec.Metrics.Enabled != nil && *spec.Metrics.Enabled && reflect.DeepEqual(spec.Metrics.ServiceMonitor, appsv1alpha1.ServiceMonitor{}) {
+		errs = append(errs, field.Required(fldPath.Child("metrics", "serviceMonitor"), "must be defined when metrics are enabled"))
+	}
+	errs = append(errs, validateScale(&spec.Scale, fldPath.Child("scale"))...)
+	if spec.Replicas < 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("replicas"), spec.Replicas, "must not be negative"))
+	}
+	errs = append(errs, validateResources(spec.Resources, fldPath.Child("resources"))...)
+	errs = append(errs, validateEnv(spec.Env, fldPath.Child("env"))...)
+	errs = append(errs, validateTolerations(spec.Tolerations, fldPath.Child("tolerations"))...)
+	errs = append(errs, validateIDs(spec.UserID, spec.GroupID, fldPath)...)
+	errs = append(errs, validateProxy(spec.Proxy, fldPath.Child("proxy"))...)
+	errs = append(errs, validateEnum(fldPath.Child("inferencePlatform"), string(spec.InferencePlatform), supportedPlatforms)...)
+	errs = append(errs, validateMultiNode(spec, fldPath.Child("multiNode"))...)
+	errs = append(errs, validateKServe(spec, fldPath)...)
+	return errs
+}
+
+// Exactly one of nimCache, pvc and hostPath is the model store. Any PVC field counts as choosing a PVC.
+func validateNIMServiceStorage(storage *appsv1alpha1.NIMServiceStorage, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	var sources []string
+	if storage.NIMCache != (appsv1alpha1.NIMCacheVolSpec{}) {
+		sources = append(sources, "nimCache")
+	}
+	if !reflect.DeepEqual(storage.PVC, appsv1alpha1.PersistentVolumeClaim{}) {
+		sources = append(sources, "pvc")
+	}
+	if storage.HostPath != nil && *storage.HostPath != "" {
+		sources = append(sources, "hostPath")
+	}
+	switch {
+	case len(sources) == 0:
+		errs = append(errs, field.Required(fldPath, "one of nimCache, pvc or hostPath must be defined"))
+	case len(sources) > 1:
+		errs = append(errs, field.Invalid(fldPath, strings.Join(sources, ", "), "only one of nimCache, pvc or hostPath may be defined"))
+	}
+
+	if storage.NIMCache.Profile != "" && storage.NIMCache.Name == "" {
+		errs = append(errs, field.Required(fldPath.Child("nimCache", "name"), "is required when a profile is set"))
+	}
+	if !reflect.DeepEqual(storage.PVC, appsv1alpha1.PersistentVolumeClaim{}) {
+		errs = append(errs, validatePVC(&storage.PVC, fldPath.Child("pvc"))...)
+	}
+	if storage.HostPath != nil && *storage.HostPath != "" && !path.IsAbs(*storage.HostPath) {
+		errs = append(errs, field.Invalid(fldPath.Child("hostPath"), *storage.HostPath, "must be an absolute path"))
+	}
+	if storage.SharedMemorySizeLimit != nil && storage.SharedMemorySizeLimit.Sign() <= 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("sharedMemorySizeLimit"), storage.SharedMemorySizeLimit.String(), "must be greater than 0"))
+	}
+	return errs
+}
+
+// An existing PVC needs a name; a new one needs a size, access mode and storage class.
+func validatePVC(pvc *appsv1alpha1.PersistentVolumeClaim, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if pvc.Create == nil || !*pvc.Create {
+		if pvc.Name == "" {
+			errs = append(errs, field.Required(fldPath.Child("name"), "is required when create is false"))
+		}
+	} else {
+		if pvc.Size == "" {
+			errs = append(errs, field.Required(fldPath.Child("size"), "is required when create is true"))
+		}
+		if pvc.VolumeAccessMode == "" {
+			errs = append(errs, field.Required(fldPath.Child("volumeAccessMode"), "is required when create is true"))
+		}
+		if pvc.StorageClass == "" {
+			errs = append(errs, field.Required(fldPath.Child("storageClass"), "is required when create is true"))
+		}
+	}
+	if pvc.Size != "" {
+		if size, err := resource.ParseQuantity(pvc.Size); err != nil {
+			errs = append(errs, field.Invalid(fldPath.Child("size"), pvc.Size, err.Error()))
+		} else if size.Sign() <= 0 {
+			errs = append(errs, field.Invalid(fldPath.Child("size"), pvc.Size, "must be greater than 0"))
+		}
+	}
+	errs = append(errs, validateEnum(fldPath.Child("volumeAccessMode"), string(pvc.VolumeAccessMode), supportedAccessModes)...)
+	return errs
+}
+
+func validateExpose(expose *appsv1alpha1.Expose, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	servicePath := fldPath.Child("service")
+	errs = append(errs, validateEnum(servicePath.Child("type"), string(expose.Service.Type), supportedServiceTypes)...)
+	if port := expose.Service.Port; port != nil && (*port < 1 || *port > 65535) {
+		errs = append(errs, field.Invalid(servicePath.Child("port"), *port, "must be between 1 and 65535"))
+	}
+	if expose.Ingress.Enabled != nil && *expose.Ingress.Enabled && reflect.DeepEqual(expose.Ingress.Spec, networkingv1.IngressSpec{}) {
+		errs = append(errs, field.Required(fldPath.Child("ingress", "spec"), "must be defined when the ingress is enabled"))
+	}
+	if expose.HTTPRoute.Enabled != nil && *expose.HTTPRoute.Enabled && (expose.HTTPRoute.Spec == nil || reflect.DeepEqual(*expose.HTTPRoute.Spec, appsv1alpha1.HTTPRouteSpec{})) {
+		errs = append(errs, field.Required(fldPath.Child("httpRoute", "spec"), "must be defined when the HTTPRoute is enabled"))
+	}
+	return errs
+}
+
+func validateScale(scale *appsv1alpha1.Autoscaling, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if scale.Enabled == nil || !*scale.Enabled {
+		return errs
+	}
+	hpaPath := fldPath.Child("hpa")
+	if scale.HPA.MaxReplicas < 1 {
+		errs = append(errs, field.Invalid(hpaPath.Child("maxReplicas"), scale.HPA.MaxReplicas, "must be at least 1 when autoscaling is enabled"))
+	}
+	if min := scale.HPA.MinReplicas; min != nil {
+		if *min < 1 {
+			errs = append(errs, field.Invalid(hpaPath.Child("minReplicas"), *min, "must be at least 1"))
+		} else if scale.HPA.MaxReplicas >= 1 && *min > scale.HPA.MaxReplicas {
+			errs = append(errs, field.Invalid(hpaPath.Child("minReplicas"), *min, fmt.Sprintf("must not be greater than maxReplicas (%d)", scale.HPA.MaxReplicas)))
+		}
+	}
+	return errs
+}
+
+// Requests must not exceed limits, GPUs are whole devices, and DRA claims belong in draResources.
+func validateResources(resources *corev1.ResourceRequirements, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if resources == nil {
+		return errs
+	}
+	if len(resources.Claims) > 0 {
+		errs = append(errs, field.Forbidden(fldPath.Child("claims"), "must be empty; use draResources instead"))
+	}
+	for _, list := range []struct {
+		name      string
+		resources corev1.ResourceList
+	}{{"limits", resources.Limits}, {"requests", resources.Requests}} {
+		for name, quantity := range list.resources {
+			if quantity.Sign() < 0 {
+				errs = append(errs, field.Invalid(fldPath.Child(list.name).Key(string(name)), quantity.String(), "must not be negative"))
+			}
+		}
+		if gpus, ok := list.resources[gpuResource]; ok && gpus.MilliValue()%1000 != 0 {
+			errs = append(errs, field.Invalid(fldPath.Child(list.name).Key(string(gpuResource)), gpus.String(), "must be a whole number of GPUs"))
+		}
+	}
+	for name, request := range resources.Requests {
+		if limit, ok := resources.Limits[name]; ok && request.Cmp(limit) > 0 {
+			errs = append(errs, field.Invalid(fldPath.Child("requests").Key(string(name)), request.String(), fmt.Sprintf("must be less than or equal to the limit %s", limit.String())))
+		}
+	}
+	return errs
+}
+
+func validateEnv(env []corev1.EnvVar, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	for i, envVar := range env {
+		if envVar.Name == "" {
+			errs = append(errs, field.Required(fldPath.Index(i).Child("name"), ""))
+		}
+		if envVar.Value != "" && envVar.ValueFrom != nil {
+			errs = append(errs, field.Invalid(fldPath.Index(i).Child("valueFrom"), envVar.Name, "may not be set together with value"))
+		}
+	}
+	return errs
+}
+
+func validateTolerations(tolerations []corev1.Toleration, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	for i, toleration := range tolerations {
+		itemPath := fldPath.Index(i)
+		errs = append(errs, validateEnum(itemPath.Child("operator"), string(toleration.Operator), supportedTolerationOps)...)
+		errs = append(errs, validateEnum(itemPath.Child("effect"), string(toleration.Effect), supportedTaintEffects)...)
+		if toleration.Operator == corev1.TolerationOpExists && toleration.Value != "" {
+			errs = append(errs, field.Invalid(itemPath.Child("value"), toleration.Value, "must be empty when operator is Exists"))
+		}
+		if toleration.Key == "" && toleration.Operator != corev1.TolerationOpExists {
+			errs = append(errs, field.Invalid(itemPath.Child("operator"), toleration.Operator, "must be Exists when key is empty"))
+		}
+	}
+	return errs
+}
+
+func validateIDs(userID, groupID *int64, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if userID != nil && *userID < 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("userID"), *userID, "must not be negative"))
+	}
+	if groupID != nil && *groupID < 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("groupID"), *groupID, "must not be negative"))
+	}
+	return errs
+}
+
+func validateProxy(proxy *appsv1alpha1.ProxySpec, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if proxy == nil {
+		return errs
+	}
+	if proxy.HttpProxy != "" && !proxyURL.MatchString(proxy.HttpProxy) {
+		errs = append(errs, field.Invalid(fldPath.Child("httpProxy"), proxy.HttpProxy, "must start with http:// or https://"))
+	}
+	if proxy.HttpsProxy != "" && !proxyURL.MatchString(proxy.HttpsProxy) {
+		errs = append(errs, field.Invalid(fldPath.Child("httpsProxy"), proxy.HttpsProxy, "must start with http:// or https://"))
+	}
+	for i, token := range strings.Split(proxy.NoProxy, ",") {
+		token = strings.TrimSpace(token)
+		if token != "" && !noProxyHost.MatchString(token) && !noProxyIPOrCIDR.MatchString(token) {
+			errs = append(errs, field.Invalid(fldPath.Child("noProxy").Index(i), token, "must be a host, domain, IP address or CIDR"))
+		}
+	}
+	return errs
+}
+
+func validateMultiNode(spec *appsv1alpha1.NIMServiceSpec, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	multiNode := spec.MultiNode
+	if multiNode == nil {
+		return errs
+	}
+	errs = append(errs, validateEnum(fldPath.Child("backendType"), string(multiNode.BackendType), supportedBackends)...)
+	if multiNode.Size < 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("size"), multiNode.Size, "must be at least 1"))
+	}
+	if multiNode.GPUSPerPod < 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("gpusPerPod"), multiNode.GPUSPerPod, "must be at least 1"))
+	}
+	if multiNode.MPI != nil && multiNode.MPI.MPIStartTimeout < 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("mpi", "mpiStartTimeout"), multiNode.MPI.MPIStartTimeout, "must not be negative"))
+	}
+	if spec.Scale.Enabled != nil && *spec.Scale.Enabled {
+		errs = append(errs, field.Forbidden(fldPath, "cannot be set when autoscaling is enabled"))
+	}
+	if spec.InferencePlatform == appsv1alpha1.PlatformTypeKServe {
+		errs = append(errs, field.Forbidden(fldPath, "cannot be set when the inference platform is kserve"))
+	}
+	return errs
+}
+
+// KServe in serverless mode, its default, manages scaling, ingress and metrics itself.
+func validateKServe(spec *appsv1alpha1.NIMServiceSpec, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if spec.InferencePlatform != appsv1alpha1.PlatformTypeKServe {
+		return errs
+	}
+	mode, annotated := spec.Annotations[kserveDeploymentModeKey]
+	if annotated && !strings.EqualFold(mode, "serverless") {
+		return errs
+	}
+	if spec.Scale.Enabled != nil && *spec.Scale.Enabled {
+		errs = append(errs, field.Forbidden(fldPath.Child("scale", "enabled"), "cannot be set when KServe runs in serverless mode"))
+	}
+	if spec.Expose.Ingress.Enabled != nil && *spec.Expose.Ingress.Enabled {
+		errs = append(errs, field.Forbidden(fldPath.Child("expose", "ingress", "enabled"), "cannot be set when KServe runs in serverless mode"))
+	}
+	if spec.Metrics.Enabled != nil && *spec.Metrics.Enabled {
+		errs = append(errs, field.Forbidden(fldPath.Child("metrics", "enabled"), "cannot be set when KServe runs in serverless mode"))
+	}
+	return errs
+}
+
+// ValidateNIMCacheSpec validates a NIMCache spec rooted at fldPath.
+func ValidateNIMCacheSpec(spec *appsv1alpha1.NIMCacheSpec, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+
+	sourcePath := fldPath.Child("source")
+	var sources int
+	if spec.Source.NGC != nil {
+		sources++
+		errs = append(errs, validateNGCSource(spec.Source.NGC, sourcePath.Child("ngc"))...)
+	}
+	if spec.Source.HF != nil {
+		sources++
+		errs = append(errs, validateDSHFSource(spec.Source.HF.Endpoint, spec.Source.HF.Namespace, &spec.Source.HF.DSHFCommonFields, sourcePath.Child("hf"))...)
+	}
+	if spec.Source.DataStore != nil {
+		sources++
+		errs = append(errs, validateDSHFSource(spec.Source.DataStore.Endpoint, spec.Source.DataStore.Namespace, &spec.Source.DataStore.DSHFCommonFields, sourcePath.Child("dataStore"))...)
+	}
+	switch {
+	case sources == 0:
+		errs = append(errs, field.Required(sourcePath, "one of ngc, hf or dataStore must be defined"))
+	case sources > 1:
+		errs = append(errs, field.Invalid(sourcePath, sources, "only one of ngc, hf or dataStore may be defined"))
+	}
+
+	if reflect.DeepEqual(spec.Storage.PVC, appsv1alpha1.PersistentVolumeClaim{}) {
+		errs = append(errs, field.Required(fldPath.Child("storage", "pvc"), ""))
+	} else {
+		errs = append(errs, validatePVC(&spec.Storage.PVC, fldPath.Child("storage", "pvc"))...)
+	}
+
+	resourcesPath := fldPath.Child("resources")
+	if spec.Resources.CPU.Sign() < 0 {
+		errs = append(errs, field.Invalid(resourcesPath.Child("cpu"), spec.Resources.CPU.String(), "must not be negative"))
+	}
+	if spec.Resources.Memory.Sign() < 0 {
+		errs = append(errs, field.Invalid(resourcesPath.Child("memory"), spec.Resources.Memory.String(), "must not be negative"))
+	}
+	if spec.CertConfig != nil {
+		errs = append(errs, field.Forbidden(fldPath.Child("certConfig"), "is deprecated and rejected by the operator; use proxy.certConfigMap"))
+	}
+	errs = append(errs, validateEnv(spec.Env, fldPath.Child("env"))...)
+	errs = append(errs, validateTolerations(spec.Tolerations, fldPath.Child("tolerations"))...)
+	errs = append(errs, validateIDs(spec.UserID, spec.GroupID, fldPath)...)
+	errs = append(errs, validateProxy(spec.Proxy, fldPath.Child("proxy"))...)
+	return errs
+}
+
+func validateNGCSource(ngc *appsv1alpha1.NGCSource, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if ngc.AuthSecret == "" {
+		errs = append(errs, field.Required(fldPath.Child("authSecret"), ""))
+	}
+	if ngc.ModelPuller == "" {
+		errs = append(errs, field.Required(fldPath.Child("modelPuller"), ""))
+	}
+	if ngc.Model == nil {
+		return errs
+	}
+
+	model := ngc.Model
+	modelPath := fldPath.Child("model")
+	// Explicit profiles replace every other selection parameter.
+	if len(model.Profiles) > 0 {
+		for _, profile := range model.Profiles {
+			if profile == "all" && len(model.Profiles) != 1 {
+				errs = append(errs, field.Invalid(modelPath.Child("profiles"), model.Profiles, "must only have a single entry when it contains 'all'"))
+				break
+			}
+		}
+		for _, selector := range []struct {
+			name string
+			set  bool
+		}{
+			{"precision", model.Precision != ""},
+			{"engine", model.Engine != ""},
+			{"tensorParallelism", model.TensorParallelism != ""},
+			{"qosProfile", model.QoSProfile != ""},
+			{"gpus", len(model.GPUs) > 0},
+			{"lora", model.Lora != nil},
+			{"buildable", model.Buildable != nil},
+		} {
+			if selector.set {
+				errs = append(errs, field.Forbidden(modelPath.Child(selector.name), "must be empty when profiles are set"))
+			}
+		}
+	}
+	errs = append(errs, validateEnum(modelPath.Child("qosProfile"), model.QoSProfile, supportedQoSProfiles)...)
+	if model.TensorParallelism != "" {
+		if tp, err := strconv.Atoi(model.TensorParallelism); err != nil || tp < 1 {
+			errs = append(errs, field.Invalid(modelPath.Child("tensorParallelism"), model.TensorParallelism, "must be a positive number"))
+		}
+	}
+	return errs
+}
+
+func validateDSHFSource(endpoint, namespace string, common *appsv1alpha1.DSHFCommonFields, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if endpoint == "" {
+		errs = append(errs, field.Required(fldPath.Child("endpoint"), ""))
+	} else if !proxyURL.MatchString(endpoint) {
+		errs = append(errs, field.Invalid(fldPath.Child("endpoint"), endpoint, "must start with http:// or https://"))
+	}
+	if namespace == "" {
+		errs = append(errs, field.Required(fldPath.Child("namespace"), ""))
+	}
+	for _, required := range []struct{ name, value string }{
+		{"authSecret", common.AuthSecret},
+		{"modelPuller", common.ModelPuller},
+		{"pullSecret", common.PullSecret},
+	} {
+		if required.value == "" {
+			errs = append(errs, field.Required(fldPath.Child(required.name), ""))
+		}
+	}
+	hasModel := common.ModelName != nil && *common.ModelName != ""
+	hasDataset := common.DatasetName != nil && *common.DatasetName != ""
+	if hasModel == hasDataset {
+		errs = append(errs, field.Invalid(fldPath, "", "exactly one of modelName or datasetName must be defined"))
+	}
+	return errs
+}
+
+// Empty values are left to defaulting.
+func validateEnum(fldPath *field.Path, value string, supported []string) field.ErrorList {
+	if value == "" {
+		return nil
+	}
+	for _, s := range supported {
+		if value == s {
+			return nil
+		}
+	}
+	return field.ErrorList{field.NotSupported(fldPath, value, supported)}
+}
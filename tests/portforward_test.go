@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	portforwardcmd "k8s-nim-operator-cli/pkg/cmd/portforward"
+	"k8s-nim-operator-cli/pkg/util"
+)
+
+func newTestPod(name string, ready bool) *corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "nim", Labels: map[string]string{"app": "llama"}},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name:  "llama",
+			Ports: []corev1.ContainerPort{{Name: "api", ContainerPort: 8000}},
+		}}},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
+
+func Test_ResolvePortForwardTarget(t *testing.T) {
+	nimservice := &appsv1alpha1.NIMService{ObjectMeta: metav1.ObjectMeta{Name: "llama", Namespace: "nim"}}
+	nimservice.Spec.Expose.Service.Port = ptr.To(int32(8000))
+
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "llama", Namespace: "nim"},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": "llama"},
+			Ports:    []corev1.ServicePort{{Name: "api", Port: 8000, TargetPort: intstr.FromString("api")}},
+		},
+	}
+	kube := k8sfake.NewSimpleClientset(svc, newTestPod("llama-a", false), newTestPod("llama-b", true))
+
+	target, err := util.ResolvePortForwardTarget(context.Background(), kube, nimservice)
+	if err != nil {
+		t.Fatalf("ResolvePortForwardTarget error: %v", err)
+	}
+	if target.ServiceName != "llama" || target.ServicePort != 8000 {
+		t.Fatalf("unexpected service in target: %+v", target)
+	}
+	if target.PodName != "llama-b" || target.PodPort != 8000 {
+		t.Fatalf("expected ready pod llama-b:8000, got %+v", target)
+	}
+
+	// A NIMService whose Service has not been created yet is reported.
+	nimservice.Name = "missing"
+	if _, err := util.ResolvePortForwardTarget(context.Background(), kube, nimservice); err == nil {
+		t.Fatalf("expected error for missing Service")
+	}
+}
+
+func Test_ResolvePortForwardTarget_NoReadyPods(t *testing.T) {
+	nimservice := &appsv1alpha1.NIMService{ObjectMeta: metav1.ObjectMeta{Name: "llama", Namespace: "nim"}}
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "llama", Namespace: "nim"},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": "llama"},
+			Ports:    []corev1.ServicePort{{Port: appsv1alpha1.DefaultAPIPort}},
+		},
+	}
+	kube := k8sfake.NewSimpleClientset(svc, newTestPod("llama-a", false))
+
+	if _, err := util.ResolvePortForwardTarget(context.Background(), kube, nimservice); err == nil {
+		t.Fatalf("expected error when no pods are ready")
+	}
+}
+
+func Test_PortForward_Command_Wiring(t *testing.T) {
+	streams, _, _, _ := genericTestIOStreams()
+	cmd := portforwardcmd.NewPortForwardCommand(nil, streams)
+	if cmd.Use != "port-forward RESOURCE NAME [LOCAL_PORT]" {
+		t.Fatalf("Use = %q", cmd.Use)
+	}
+	if f := cmd.Flags().Lookup("address"); f == nil || f.DefValue != "localhost" {
+		t.Fatalf("expected address flag defaulting to localhost")
+	}
+	if got := portforwardcmd.BaseURL("localhost", 8000); got != "http://localhost:8000/v1" {
+		t.Fatalf("BaseURL = %q", got)
+	}
+}
@@ -0,0 +1,57 @@
+package tests
+
+import (
+	"testing"
+
+	appsv1alpha1 "github.com/NVIDIA/k8s-nim-operator/api/apps/v1alpha1"
+
+	"k8s-nim-operator-cli/pkg/util"
+)
+
+func Test_NewProfileInfo_ParsesTags(t *testing.T) {
+	info := util.NewProfileInfo(appsv1alpha1.NIMProfile{
+		Name:    "abc123",
+		Model:   "meta/llama-3.1-8b-instruct",
+		Release: "1.3.3",
+		Config: map[string]string{
+			"engine": "tensorrt_llm", "precision": "fp8", "tp": "2", "pp": "1",
+			"GPU": "H100", "llm_engine": "tensorrt_llm", "feat_lora": "false", "profile": "throughput",
+		},
+	})
+	if info.ID != "abc123" || info.Engine != "tensorrt_llm" || info.Precision != "fp8" || info.TP != "2" || info.PP != "1" ||
+		info.GPU != "H100" || info.LLMEngine != "tensorrt_llm" || info.Lora != "false" || info.Profile != "throughput" {
+		t.Fatalf("unexpected profile info: %+v", info)
+	}
+
+	// Missing tags stay empty.
+	if empty := util.NewProfileInfo(appsv1alpha1.NIMProfile{Name: "x"}); empty.Engine != "" || empty.GPU != "" {
+		t.Fatalf("expected empty tags, got %+v", empty)
+	}
+}
+
+func Test_ProfileFilter_Matches(t *testing.T) {
+	h100 := util.ProfileInfo{GPU: "H100_NVL", Precision: "fp8", Engine: "tensorrt_llm", LLMEngine: "tensorrt_llm", TP: "2", Lora: "false"}
+	vllm := util.ProfileInfo{Precision: "bf16", Engine: "vllm", TP: "1", Lora: "true"}
+
+	cases := []struct {
+		filter util.ProfileFilter
+		h100   bool
+		vllm   bool
+	}{
+		{util.ProfileFilter{}, true, true},
+		{util.ProfileFilter{GPU: "h100"}, true, false},
+		{util.ProfileFilter{Precision: "FP8"}, true, false},
+		{util.ProfileFilter{Engine: "vllm"}, false, true},
+		{util.ProfileFilter{TP: "1"}, false, true},
+		{util.ProfileFilter{Lora: "true"}, false, true},
+		{util.ProfileFilter{GPU: "h100", Precision: "bf16"}, false, false},
+	}
+	for _, tc := range cases {
+		if got := tc.filter.Matches(h100); got != tc.h100 {
+			t.Errorf("%+v on h100 profile: got %t", tc.filter, got)
+		}
+		if got := tc.filter.Matches(vllm); got != tc.vllm {
+			t.Errorf("%+v on vllm profile: got %t", tc.filter, got)
+		}
+	}
+}